@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"github.com/go-resty/resty/v2"
+	"github.com/inasknh/simple-poke-app/internal/api"
+	cache2 "github.com/inasknh/simple-poke-app/internal/cache"
+	"github.com/inasknh/simple-poke-app/internal/config"
+	db2 "github.com/inasknh/simple-poke-app/internal/db"
+	repository2 "github.com/inasknh/simple-poke-app/internal/repository"
+	service2 "github.com/inasknh/simple-poke-app/internal/service"
+	"github.com/spf13/viper"
+	"log"
+	"time"
+)
+
+// import ingests berries from a local PokeAPI data dump through the same
+// service and repository path as the online sync.
+func main() {
+	format := flag.String("format", api.ClientAPIData, "dump layout: api-data or csv")
+	path := flag.String("path", "", "data dump directory or .zip archive (defaults to api.data_dump)")
+	timeout := flag.Duration("timeout", 5*time.Minute, "maximum duration of the import")
+	flag.Parse()
+
+	viper.SetConfigName("config")
+	viper.SetConfigType("yaml")
+	viper.AddConfigPath(".")
+
+	if err := viper.ReadInConfig(); err != nil {
+		log.Fatalf("Couldn't read config")
+	}
+
+	var configuration config.Configurations
+	if err := viper.Unmarshal(&configuration); err != nil {
+		log.Fatalf("Couldn't unmarshal configuration")
+	}
+
+	if *format != api.ClientAPIData && *format != api.ClientCSV {
+		log.Fatalf("Unsupported dump format %q", *format)
+	}
+	configuration.Api.Client = *format
+	if *path != "" {
+		configuration.Api.DataDump = *path
+	}
+
+	client, err := api.New(configuration.Api, resty.New())
+	if err != nil {
+		log.Fatalf("Couldn't create api client: %v", err)
+	}
+
+	db := db2.NewMySql(configuration)
+	dbRepository := repository2.NewRepository(db)
+	cache := cache2.NewRedis(configuration.Cache)
+	redisRepository := repository2.NewRedisRepository(cache, configuration)
+	// berries carry no sprites of their own, nothing to mirror
+	service := service2.NewService(dbRepository, redisRepository, client, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	if err = service.SyncData(ctx); err != nil {
+		log.Fatalf("Import failed: %v", err)
+	}
+
+	log.Printf("Imported berries from %s", configuration.Api.DataDump)
+}
@@ -37,26 +37,81 @@ func main() {
 
 	db := db2.NewMySql(configuration)
 	dbRepository := repository2.NewRepository(db)
+	ctx, stop := context.WithCancel(context.Background())
+	defer stop()
+
 	cache := cache2.NewRedis(configuration.Cache)
 	redisRepository := repository2.NewRedisRepository(cache, configuration)
+	if configuration.Cache.LocalSize > 0 {
+		redisRepository = repository2.NewLocalRedisRepository(ctx, redisRepository, cache, configuration.Cache)
+	}
 
-	restyClient := resty.New().
-		SetTimeout(5 * time.Second).
-		SetRetryCount(3).
-		AddRetryCondition(func(response *resty.Response, err error) bool {
-			return err != nil || response.StatusCode() >= 500 || response.StatusCode() == http.StatusTooManyRequests
-		})
+	client, err := api.New(configuration.Api, newRestyClient())
+	if err != nil {
+		log.Fatalf("Couldn't create api client: %v", err)
+	}
 
-	client := api.NewClient(configuration.Api, restyClient)
-	service := service2.NewService(dbRepository, redisRepository, client)
+	var assets *service2.Assets
+	blobStore, err := repository2.NewBlobStore(configuration.Assets)
+	if err != nil {
+		log.Fatalf("Couldn't create blob store: %v", err)
+	}
+	if blobStore != nil {
+		assets = &service2.Assets{
+			Store:      blobStore,
+			// the api client may record or replay fixtures, sprites
+			// are always downloaded from upstream
+			Downloader: api.NewDownloader(newRestyClient()),
+			BaseURL:    configuration.Assets.BaseURL,
+		}
+		if assets.BaseURL == "" {
+			assets.BaseURL = "/assets/"
+		}
+	}
+
+	service := service2.NewService(dbRepository, redisRepository, client, assets)
 	handler := handler2.NewHandler(service)
+	adminHandler := handler2.NewAdminHandler(service, configuration.Admin.Token)
+
+	if configuration.App.WarmupTimeout > 0 {
+		warmCtx, cancel := context.WithTimeout(ctx, time.Duration(configuration.App.WarmupTimeout)*time.Second)
+		if err := service.WarmCache(warmCtx); err != nil {
+			log.Printf("Couldn't warm cache: %v", err)
+		}
+		cancel()
+	}
 
 	http.HandleFunc("/sync", handler.SyncData)
+	http.HandleFunc("/sync/pokemon", handler.SyncPokemon)
+	http.HandleFunc("/sync/items", handler.SyncItems)
+	http.HandleFunc("/sync/types", handler.SyncTypes)
+	http.HandleFunc("/sync/evolution-chains", handler.SyncEvolutionChains)
+	http.HandleFunc("/sync/natures", handler.SyncNatures)
+	http.HandleFunc("/sync/berry-growth", handler.SyncBerryGrowth)
 	http.HandleFunc("/items", handler.GetItems)
+	http.HandleFunc("GET /items/{name}", handler.GetItem)
+	http.HandleFunc("GET /firmnesses", handler.GetFirmnesses)
+	http.HandleFunc("GET /flavors", handler.GetFlavors)
+	http.HandleFunc("GET /pokemon", handler.GetPokemonList)
+	http.HandleFunc("GET /pokemon/{name}", handler.GetPokemon)
+	http.HandleFunc("GET /catalogue/items", handler.GetCatalogue)
+	http.HandleFunc("GET /catalogue/items/{name}", handler.GetCatalogueItem)
+	http.HandleFunc("GET /types/effectiveness", handler.GetEffectiveness)
+	http.HandleFunc("GET /evolution-chains/{species}", handler.GetEvolutionChain)
+	http.HandleFunc("GET /evolution-chains/{species}/links", handler.GetEvolutionLinks)
+	http.HandleFunc("GET /assets/{hash}", handler.GetAsset)
+	http.HandleFunc("GET /search", handler.Search)
+	http.HandleFunc("GET /berries/recommendations", handler.RecommendBerries)
+	http.HandleFunc("GET /berries/plan", handler.PlanFarm)
+	http.HandleFunc("GET /admin/cache/keys", adminHandler.Authenticate(adminHandler.ListCacheKeys))
+	http.HandleFunc("DELETE /admin/cache/keys", adminHandler.Authenticate(adminHandler.PurgeCache))
+	http.HandleFunc("DELETE /admin/cache/keys/{key...}", adminHandler.Authenticate(adminHandler.DeleteCacheKey))
+	http.HandleFunc("POST /admin/cache/warm", adminHandler.Authenticate(adminHandler.WarmCache))
 
 	port := configuration.App.Port
 	srv := &http.Server{
-		Addr: fmt.Sprintf(":%d", port),
+		Addr:    fmt.Sprintf(":%d", port),
+		Handler: handler2.Compress(http.DefaultServeMux),
 	}
 
 	done := make(chan os.Signal, 1)
@@ -78,3 +133,13 @@ func main() {
 
 	log.Println("All server stopped!")
 }
+
+// newRestyClient creates a client retrying transient upstream failures.
+func newRestyClient() *resty.Client {
+	return resty.New().
+		SetTimeout(5 * time.Second).
+		SetRetryCount(3).
+		AddRetryCondition(func(response *resty.Response, err error) bool {
+			return err != nil || response.StatusCode() >= 500 || response.StatusCode() == http.StatusTooManyRequests
+		})
+}
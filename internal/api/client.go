@@ -3,48 +3,131 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/go-resty/resty/v2"
 	"github.com/inasknh/simple-poke-app/internal/config"
+	"net/http"
 	"strconv"
 )
 
+const (
+	// ClientPokeAPI talks to the live PokeAPI.
+	ClientPokeAPI = "poke-api"
+	// ClientRecord talks to the live PokeAPI and records every response as a fixture.
+	ClientRecord = "poke-api-record"
+	// ClientReplay serves previously recorded fixtures without any network access.
+	ClientReplay = "poke-api-replay"
+	// ClientMirror talks to a self-hosted PokeAPI mirror at config.MirrorHost.
+	ClientMirror = "poke-api-mirror"
+	// ClientFile serves a local JSON dump of a PokeAPI list response.
+	ClientFile = "file"
+)
+
 type client struct {
 	host       string
-	path       string
 	rstyClient *resty.Client
 }
 
+// Client reads PokeAPI resources. Prefer the typed Get, List and ListAll
+// helpers over calling it directly.
 type Client interface {
-	GetBerries(ctx context.Context, request BerriesRequest) (*BerriesResponse, error)
+	ListResources(ctx context.Context, endpoint string, request ListRequest) (*NamedAPIResourceList, error)
+	GetResource(ctx context.Context, endpoint string, idOrName string, out interface{}) error
 }
 
 func NewClient(config config.Api, rstyClient *resty.Client) Client {
 	return &client{
 		host:       config.Host,
-		path:       config.Path,
 		rstyClient: rstyClient,
 	}
 }
 
-func (c *client) GetBerries(ctx context.Context, request BerriesRequest) (*BerriesResponse, error) {
+// New creates the Client selected by config.Client.
+func New(config config.Api, rstyClient *resty.Client) (Client, error) {
+	switch config.Client {
+	case "", ClientPokeAPI:
+		return NewClient(config, rstyClient), nil
+	case ClientRecord:
+		if config.FixturesDir == "" {
+			return nil, errors.New("fixtures_dir is required to record fixtures")
+		}
+		rstyClient.SetTransport(NewRecordingTransport(config.FixturesDir, rstyClient.GetClient().Transport))
+		return NewClient(config, rstyClient), nil
+	case ClientReplay:
+		if config.FixturesDir == "" {
+			return nil, errors.New("fixtures_dir is required to replay fixtures")
+		}
+		rstyClient.SetTransport(NewReplayTransport(config.FixturesDir))
+		return NewClient(config, rstyClient), nil
+	case ClientMirror:
+		if config.MirrorHost == "" {
+			return nil, errors.New("mirror_host is required to use a PokeAPI mirror")
+		}
+		config.Host = config.MirrorHost
+		return NewClient(config, rstyClient), nil
+	case ClientFile:
+		if config.DumpFile == "" {
+			return nil, errors.New("dump_file is required to use a local dump")
+		}
+		return NewFileClient(config.DumpFile), nil
+	case ClientAPIData:
+		if config.DataDump == "" {
+			return nil, errors.New("data_dump is required to import an api-data dump")
+		}
+		return NewAPIDataClient(config.DataDump, config.Host), nil
+	case ClientCSV:
+		if config.DataDump == "" {
+			return nil, errors.New("data_dump is required to import a csv dump")
+		}
+		return NewCSVClient(config.DataDump, config.Host), nil
+	default:
+		return nil, fmt.Errorf("unknown api client %q", config.Client)
+	}
+}
+
+func (c *client) ListResources(ctx context.Context, endpoint string, request ListRequest) (*NamedAPIResourceList, error) {
 	resp, err := c.rstyClient.
 		R().
 		SetContext(ctx).
 		SetQueryParam("limit", strconv.Itoa(request.Limit)).
 		SetQueryParam("offset", strconv.Itoa(request.Offset)).
-		Get(fmt.Sprintf("%s%s", c.host, c.path))
+		Get(fmt.Sprintf("%s%s", c.host, endpoint))
 
 	if err != nil {
 		return nil, err
 	}
 
-	var br BerriesResponse
-	err = json.Unmarshal(resp.Body(), &br)
+	var list NamedAPIResourceList
+	err = decodeResponse(resp, &list)
 	if err != nil {
 		return nil, err
 	}
 
-	return &br, nil
+	return &list, nil
+}
+
+func (c *client) GetResource(ctx context.Context, endpoint string, idOrName string, out interface{}) error {
+	resp, err := c.rstyClient.
+		R().
+		SetContext(ctx).
+		Get(fmt.Sprintf("%s%s/%s/", c.host, endpoint, idOrName))
+
+	if err != nil {
+		return err
+	}
+
+	return decodeResponse(resp, out)
+}
+
+// decodeResponse unmarshals a successful response body into out.
+func decodeResponse(resp *resty.Response, out interface{}) error {
+	if resp.StatusCode() == http.StatusNotFound {
+		return fmt.Errorf("%w: %s", ErrNotFound, resp.Request.URL)
+	}
+	if resp.IsError() {
+		return fmt.Errorf("unexpected status %d from %s", resp.StatusCode(), resp.Request.URL)
+	}
 
+	return json.Unmarshal(resp.Body(), out)
 }
@@ -10,25 +10,25 @@ import (
 	"testing"
 )
 
-func Test_client_GetBerries_Success(t *testing.T) {
+func Test_client_ListResources_Success(t *testing.T) {
 	r := resty.New()
 
 	// activate mock
 	httpmock.ActivateNonDefault(r.GetClient())
 	defer httpmock.DeactivateAndReset()
 
-	respSuccess := &BerriesResponse{
+	respSuccess := &NamedAPIResourceList{
 		Count:    2,
 		Next:     "",
 		Previous: "",
-		Results: []Berry{
+		Results: []NamedAPIResource{
 			{
 				Name: "leppa",
-				Url:  "https://pokeapi.co/api/v2/berry/6/",
+				URL:  "https://pokeapi.co/api/v2/berry/6/",
 			},
 			{
 				Name: "oran",
-				Url:  "https://pokeapi.co/api/v2/berry/7/",
+				URL:  "https://pokeapi.co/api/v2/berry/7/",
 			},
 		},
 	}
@@ -39,11 +39,10 @@ func Test_client_GetBerries_Success(t *testing.T) {
 
 	// Inject Resty into client
 	c := NewClient(config.Api{
-		Host:     "https://pokeapi.co/api/v2/",
-		BerryURL: "berry",
+		Host: "https://pokeapi.co/api/v2/",
 	}, r)
 
-	resp, err := c.GetBerries(context.Background(), BerriesRequest{
+	resp, err := List[Berry](context.Background(), c, ListRequest{
 		Limit:  10,
 		Offset: 0,
 	})
@@ -53,7 +52,7 @@ func Test_client_GetBerries_Success(t *testing.T) {
 	assert.Equal(t, 2, resp.Count)
 }
 
-func Test_client_GetBerries_ServerErrorThenRetry(t *testing.T) {
+func Test_client_ListResources_ServerErrorThenRetry(t *testing.T) {
 	// Create Resty with retry config
 	r := resty.New().
 		SetRetryCount(2).AddRetryCondition(func(response *resty.Response, err error) bool {
@@ -63,14 +62,14 @@ func Test_client_GetBerries_ServerErrorThenRetry(t *testing.T) {
 	httpmock.ActivateNonDefault(r.GetClient())
 	defer httpmock.DeactivateAndReset()
 
-	respSuccess := &BerriesResponse{
+	respSuccess := &NamedAPIResourceList{
 		Count:    1,
 		Next:     "",
 		Previous: "",
-		Results: []Berry{
+		Results: []NamedAPIResource{
 			{
 				Name: "leppa",
-				Url:  "https://pokeapi.co/api/v2/berry/6/",
+				URL:  "https://pokeapi.co/api/v2/berry/6/",
 			},
 		},
 	}
@@ -90,11 +89,10 @@ func Test_client_GetBerries_ServerErrorThenRetry(t *testing.T) {
 
 	// Inject Resty into client
 	c := NewClient(config.Api{
-		Host:     "https://pokeapi.co/api/v2/",
-		BerryURL: "berry",
+		Host: "https://pokeapi.co/api/v2/",
 	}, r)
 
-	resp, err := c.GetBerries(context.Background(), BerriesRequest{
+	resp, err := List[Berry](context.Background(), c, ListRequest{
 		Limit:  10,
 		Offset: 0,
 	})
@@ -104,3 +102,91 @@ func Test_client_GetBerries_ServerErrorThenRetry(t *testing.T) {
 	assert.Equal(t, 1, resp.Count)
 	assert.Equal(t, 2, callCount) // retried once
 }
+
+func Test_New_Mirror(t *testing.T) {
+	r := resty.New()
+
+	httpmock.ActivateNonDefault(r.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET",
+		"http://mirror.local/api/v2/berry?limit=10&offset=0",
+		httpmock.NewJsonResponderOrPanic(http.StatusOK, &NamedAPIResourceList{Count: 64}))
+
+	c, err := New(config.Api{
+		Client:     ClientMirror,
+		Host:       "https://pokeapi.co/api/v2/",
+		MirrorHost: "http://mirror.local/api/v2/",
+	}, r)
+	assert.NoError(t, err)
+
+	resp, err := List[Berry](context.Background(), c, ListRequest{
+		Limit:  10,
+		Offset: 0,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 64, resp.Count)
+}
+
+func Test_client_GetResource(t *testing.T) {
+	r := resty.New()
+
+	httpmock.ActivateNonDefault(r.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET",
+		"https://pokeapi.co/api/v2/berry/cheri/",
+		httpmock.NewStringResponder(http.StatusOK, `{
+			"id": 1,
+			"name": "cheri",
+			"growth_time": 3,
+			"firmness": {"name": "soft", "url": "https://pokeapi.co/api/v2/berry-firmness/2/"},
+			"flavors": [{"potency": 10, "flavor": {"name": "spicy", "url": "https://pokeapi.co/api/v2/berry-flavor/1/"}}]
+		}`))
+	httpmock.RegisterResponder("GET",
+		"https://pokeapi.co/api/v2/berry/unknown/",
+		httpmock.NewStringResponder(http.StatusNotFound, "Not Found"))
+
+	c := NewClient(config.Api{
+		Host: "https://pokeapi.co/api/v2/",
+	}, r)
+
+	berry, err := Get[Berry](context.Background(), c, "cheri")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, berry.ID)
+	assert.Equal(t, 3, berry.GrowthTime)
+	assert.Equal(t, "soft", berry.Firmness.Name)
+	assert.Equal(t, []BerryFlavorMap{
+		{
+			Potency: 10,
+			Flavor: NamedAPIResource{
+				Name: "spicy",
+				URL:  "https://pokeapi.co/api/v2/berry-flavor/1/",
+			},
+		},
+	}, berry.Flavors)
+
+	berry, err = Get[Berry](context.Background(), c, "unknown")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Nil(t, berry)
+}
+
+func Test_Endpoint(t *testing.T) {
+	assert.Equal(t, "berry", Endpoint[Berry]())
+	assert.Equal(t, "berry-firmness", Endpoint[BerryFirmness]())
+	assert.Equal(t, "berry-flavor", Endpoint[BerryFlavor]())
+	assert.Equal(t, "item", Endpoint[Item]())
+	assert.Equal(t, "pokemon", Endpoint[Pokemon]())
+	assert.Equal(t, "pokemon-species", Endpoint[PokemonSpecies]())
+	assert.Equal(t, "type", Endpoint[Type]())
+	assert.Equal(t, "evolution-chain", Endpoint[EvolutionChain]())
+	assert.Equal(t, "nature", Endpoint[Nature]())
+}
+
+func Test_NamedAPIResource_ID(t *testing.T) {
+	assert.Equal(t, 25, NamedAPIResource{URL: "https://pokeapi.co/api/v2/pokemon/25/"}.ID())
+	assert.Equal(t, 7, NamedAPIResource{URL: "https://pokeapi.co/api/v2/type/7"}.ID())
+	assert.Equal(t, 0, NamedAPIResource{URL: "https://pokeapi.co/api/v2/type/fire/"}.ID())
+	assert.Equal(t, 0, NamedAPIResource{}.ID())
+}
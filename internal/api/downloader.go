@@ -0,0 +1,51 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-resty/resty/v2"
+	"net/http"
+)
+
+// Download is a raw file fetched from upstream. Its type is not trusted,
+// callers sniff Data themselves.
+type Download struct {
+	Data []byte
+}
+
+// Downloader fetches upstream files referenced by resources, such as sprites.
+type Downloader interface {
+	// Download fetches url, or returns ErrNotFound.
+	Download(ctx context.Context, url string) (*Download, error)
+}
+
+type downloader struct {
+	rstyClient *resty.Client
+}
+
+// NewDownloader creates a Downloader. rstyClient must not be shared with a
+// Client, whose fixture transports only keep text bodies.
+func NewDownloader(rstyClient *resty.Client) Downloader {
+	return &downloader{rstyClient: rstyClient}
+}
+
+func (d *downloader) Download(ctx context.Context, url string) (*Download, error) {
+	resp, err := d.rstyClient.
+		R().
+		SetContext(ctx).
+		Get(url)
+
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode() == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, url)
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("unexpected status %d from %s", resp.StatusCode(), url)
+	}
+
+	return &Download{
+		Data: resp.Body(),
+	}, nil
+}
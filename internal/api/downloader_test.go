@@ -0,0 +1,34 @@
+package api
+
+import (
+	"context"
+	"github.com/go-resty/resty/v2"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func Test_downloader_Download(t *testing.T) {
+	r := resty.New()
+
+	httpmock.ActivateNonDefault(r.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	png := "\x89PNG\r\n\x1a\n"
+	httpmock.RegisterResponder("GET", "https://sprites/sprite.png", httpmock.NewStringResponder(http.StatusOK, png))
+	httpmock.RegisterResponder("GET", "https://sprites/missing.png", httpmock.NewStringResponder(http.StatusNotFound, "Not Found"))
+	httpmock.RegisterResponder("GET", "https://sprites/error.png", httpmock.NewStringResponder(http.StatusInternalServerError, ""))
+
+	d := NewDownloader(r)
+
+	got, err := d.Download(context.Background(), "https://sprites/sprite.png")
+	assert.NoError(t, err)
+	assert.Equal(t, &Download{Data: []byte(png)}, got)
+
+	_, err = d.Download(context.Background(), "https://sprites/missing.png")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = d.Download(context.Background(), "https://sprites/error.png")
+	assert.Error(t, err)
+}
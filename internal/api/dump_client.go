@@ -0,0 +1,329 @@
+package api
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// ClientAPIData reads a checkout or zip archive of the PokeAPI api-data repository.
+	ClientAPIData = "api-data"
+	// ClientCSV reads a checkout or zip archive of the CSV data shipped in the PokeAPI repository.
+	ClientCSV = "csv"
+)
+
+const (
+	apiDataRoot = "api/v2"
+	csvRoot     = "v2/csv"
+)
+
+// ErrDumpFileNotFound is returned when a required file is missing from a data dump.
+var ErrDumpFileNotFound = errors.New("file not found in data dump")
+
+// dumpLayout reads resources out of an opened data dump rooted at root.
+type dumpLayout interface {
+	root() string
+	list(fsys fs.FS, root, endpoint, baseURL string) ([]NamedAPIResource, error)
+	get(fsys fs.FS, root, endpoint, idOrName, baseURL string, out interface{}) error
+}
+
+// dumpClient serves resources from a local PokeAPI data dump, either a
+// directory or a zip archive, resolving resource URLs against baseURL so
+// imported rows look the same as the ones synced online.
+type dumpClient struct {
+	path    string
+	baseURL string
+	layout  dumpLayout
+
+	// the dump is opened and its root located once, on first use
+	open sync.Once
+	fsys fs.FS
+	root string
+	err  error
+}
+
+// NewAPIDataClient creates a Client reading the api-data JSON layout at path.
+func NewAPIDataClient(path, baseURL string) Client {
+	return &dumpClient{path: path, baseURL: baseURL, layout: apiDataLayout{}}
+}
+
+// NewCSVClient creates a Client reading the PokeAPI CSV layout at path.
+func NewCSVClient(path, baseURL string) Client {
+	return &dumpClient{path: path, baseURL: baseURL, layout: csvLayout{}}
+}
+
+func (c *dumpClient) ListResources(ctx context.Context, endpoint string, request ListRequest) (*NamedAPIResourceList, error) {
+	var results []NamedAPIResource
+	err := c.withDump(ctx, func(fsys fs.FS, root string) error {
+		var err error
+		results, err = c.layout.list(fsys, root, endpoint, c.baseURL)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return paginate(c.path, results, request), nil
+}
+
+func (c *dumpClient) GetResource(ctx context.Context, endpoint string, idOrName string, out interface{}) error {
+	return c.withDump(ctx, func(fsys fs.FS, root string) error {
+		return c.layout.get(fsys, root, endpoint, idOrName, c.baseURL, out)
+	})
+}
+
+// withDump calls fn with the dump and the layout root inside it. The dump
+// stays open for the lifetime of the client.
+func (c *dumpClient) withDump(ctx context.Context, fn func(fsys fs.FS, root string) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	c.open.Do(func() {
+		c.fsys, c.root, c.err = c.openRoot()
+	})
+	if c.err != nil {
+		return c.err
+	}
+
+	if err := fn(c.fsys, c.root); err != nil {
+		return fmt.Errorf("failed to read dump %s: %w", c.path, err)
+	}
+
+	return nil
+}
+
+// openRoot opens the dump and locates the layout root inside it.
+func (c *dumpClient) openRoot() (fs.FS, string, error) {
+	fsys, closer, err := openDump(c.path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	root, err := findDumpDir(fsys, c.layout.root())
+	if err != nil {
+		_ = closer.Close()
+		return nil, "", fmt.Errorf("failed to read dump %s: %w", c.path, err)
+	}
+
+	return fsys, root, nil
+}
+
+// openDump opens a data dump directory or zip archive as a fs.FS.
+func openDump(name string) (fs.FS, io.Closer, error) {
+	info, err := os.Stat(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if info.IsDir() {
+		return os.DirFS(name), io.NopCloser(nil), nil
+	}
+
+	if strings.HasSuffix(name, ".zip") {
+		r, err := zip.OpenReader(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		return r, r, nil
+	}
+
+	return nil, nil, fmt.Errorf("unsupported data dump %s: expected a directory or a .zip archive", name)
+}
+
+// findDumpDir locates the directory ending in suffix, tolerating the extra
+// top level directories archives and checkouts usually have
+// (e.g. api-data-master/data/api/v2).
+func findDumpDir(fsys fs.FS, suffix string) (string, error) {
+	found := ""
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if p == suffix || strings.HasSuffix(p, "/"+suffix) {
+			found = p
+			return fs.SkipAll
+		}
+		// checkouts carry a .git directory as large as the data itself
+		if p != "." && strings.HasPrefix(d.Name(), ".") {
+			return fs.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if found == "" {
+		return "", fmt.Errorf("%w: %s", ErrDumpFileNotFound, suffix)
+	}
+
+	return found, nil
+}
+
+func readDumpFile(fsys fs.FS, name string) ([]byte, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrDumpFileNotFound, name)
+	}
+
+	return data, err
+}
+
+// apiDataLayout reads the api-data repository, which stores every response
+// at api/v2/{endpoint}/index.json and api/v2/{endpoint}/{id}/index.json.
+type apiDataLayout struct{}
+
+func (apiDataLayout) root() string {
+	return apiDataRoot
+}
+
+func (l apiDataLayout) list(fsys fs.FS, root, endpoint, baseURL string) ([]NamedAPIResource, error) {
+	var list NamedAPIResourceList
+	err := l.read(fsys, path.Join(root, endpoint, "index.json"), baseURL, &list)
+	if err != nil {
+		if errors.Is(err, ErrDumpFileNotFound) {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, endpoint)
+		}
+		return nil, err
+	}
+
+	return list.Results, nil
+}
+
+func (l apiDataLayout) get(fsys fs.FS, root, endpoint, idOrName, baseURL string, out interface{}) error {
+	id := idOrName
+	if _, err := strconv.Atoi(idOrName); err != nil {
+		// details are stored by id only, so resolve names through the list
+		results, err := l.list(fsys, root, endpoint, baseURL)
+		if err != nil {
+			return err
+		}
+		id = ""
+		for _, r := range results {
+			if r.Name == idOrName {
+				id = path.Base(strings.TrimSuffix(r.URL, "/"))
+			}
+		}
+		if id == "" {
+			return fmt.Errorf("%w: %s/%s", ErrNotFound, endpoint, idOrName)
+		}
+	}
+
+	err := l.read(fsys, path.Join(root, endpoint, id, "index.json"), baseURL, out)
+	if errors.Is(err, ErrDumpFileNotFound) {
+		return fmt.Errorf("%w: %s/%s", ErrNotFound, endpoint, idOrName)
+	}
+
+	return err
+}
+
+// read unmarshals a response file, rewriting the URLs api-data stores
+// relative to the API root (e.g. /api/v2/berry/1/) against baseURL.
+func (apiDataLayout) read(fsys fs.FS, name, baseURL string, out interface{}) error {
+	data, err := readDumpFile(fsys, name)
+	if err != nil {
+		return err
+	}
+
+	data = bytes.ReplaceAll(data, []byte(`"/api/v2/`), []byte(`"`+baseURL))
+
+	return json.Unmarshal(data, out)
+}
+
+// csvLayout reads the CSV tables of the PokeAPI repository, which only
+// lists berries.
+type csvLayout struct{}
+
+func (csvLayout) root() string {
+	return csvRoot
+}
+
+func (csvLayout) list(fsys fs.FS, root, endpoint, baseURL string) ([]NamedAPIResource, error) {
+	if endpoint != EndpointBerry {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, endpoint)
+	}
+
+	berryRows, err := readDumpCSV(fsys, path.Join(root, "berries.csv"))
+	if err != nil {
+		return nil, err
+	}
+	itemRows, err := readDumpCSV(fsys, path.Join(root, "items.csv"))
+	if err != nil {
+		return nil, err
+	}
+
+	// berries.csv only references items; the berry name is the item
+	// identifier without its "-berry" suffix
+	items := make(map[string]string, len(itemRows))
+	for _, row := range itemRows {
+		items[row["id"]] = row["identifier"]
+	}
+
+	berries := make([]NamedAPIResource, 0, len(berryRows))
+	for _, row := range berryRows {
+		id, err := strconv.Atoi(row["id"])
+		if err != nil {
+			return nil, fmt.Errorf("invalid berry id %q: %w", row["id"], err)
+		}
+		identifier, ok := items[row["item_id"]]
+		if !ok {
+			return nil, fmt.Errorf("berry %d references unknown item %s", id, row["item_id"])
+		}
+
+		berries = append(berries, NamedAPIResource{
+			Name: strings.TrimSuffix(identifier, "-berry"),
+			URL:  fmt.Sprintf("%sberry/%d/", baseURL, id),
+		})
+	}
+
+	return berries, nil
+}
+
+func (csvLayout) get(fsys fs.FS, root, endpoint, idOrName, baseURL string, out interface{}) error {
+	return fmt.Errorf("%w: %s/%s is not available in csv dumps", ErrNotFound, endpoint, idOrName)
+}
+
+// readDumpCSV reads a CSV file with a header row into one map per record.
+func readDumpCSV(fsys fs.FS, name string) ([]map[string]string, error) {
+	data, err := readDumpFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path.Base(name), err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	header := records[0]
+	rows := make([]map[string]string, 0, len(records)-1)
+	for _, record := range records[1:] {
+		row := make(map[string]string, len(header))
+		for i, column := range header {
+			if i < len(record) {
+				row[column] = record[i]
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,190 @@
+package api
+
+import (
+	"archive/zip"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const dumpBaseURL = "https://pokeapi.co/api/v2/"
+
+var dumpBerries = []NamedAPIResource{
+	{
+		Name: "cheri",
+		URL:  "https://pokeapi.co/api/v2/berry/1/",
+	},
+	{
+		Name: "chesto",
+		URL:  "https://pokeapi.co/api/v2/berry/2/",
+	},
+	{
+		Name: "pecha",
+		URL:  "https://pokeapi.co/api/v2/berry/3/",
+	},
+}
+
+// zipDir archives dir into a zip file under an extra top level directory,
+// the way GitHub archives of the PokeAPI repositories are laid out.
+func zipDir(t *testing.T, dir string) string {
+	name := filepath.Join(t.TempDir(), "dump.zip")
+	f, err := os.Create(name)
+	assert.NoError(t, err)
+	defer f.Close()
+
+	w := zip.NewWriter(f)
+	err = filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		zf, err := w.Create("dump-master/" + filepath.ToSlash(rel))
+		if err != nil {
+			return err
+		}
+		_, err = zf.Write(data)
+		return err
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+
+	return name
+}
+
+func Test_dumpClient_ListResources(t *testing.T) {
+	tests := []struct {
+		name    string
+		client  Client
+		want    []NamedAPIResource
+		wantErr bool
+	}{
+		{
+			name:   "given api-data directory should return berries with absolute urls",
+			client: NewAPIDataClient("testdata/api-data", dumpBaseURL),
+			want:   dumpBerries,
+		},
+		{
+			name:   "given api-data zip archive should return berries with absolute urls",
+			client: NewAPIDataClient(zipDir(t, "testdata/api-data"), dumpBaseURL),
+			want:   dumpBerries,
+		},
+		{
+			name:   "given csv directory should return berries named after their items",
+			client: NewCSVClient("testdata/csv", dumpBaseURL),
+			want:   dumpBerries,
+		},
+		{
+			name:   "given csv zip archive should return berries named after their items",
+			client: NewCSVClient(zipDir(t, "testdata/csv"), dumpBaseURL),
+			want:   dumpBerries,
+		},
+		{
+			name:    "given directory without the expected layout should return an error",
+			client:  NewAPIDataClient("testdata/csv", dumpBaseURL),
+			wantErr: true,
+		},
+		{
+			name:    "given missing path should return an error",
+			client:  NewCSVClient("testdata/missing", dumpBaseURL),
+			wantErr: true,
+		},
+		{
+			name:    "given unsupported archive should return an error",
+			client:  NewCSVClient("testdata/csv/data/v2/csv/items.csv", dumpBaseURL),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := List[Berry](context.Background(), tt.client, ListRequest{Limit: 10})
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, got)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, len(tt.want), got.Count)
+			assert.Equal(t, tt.want, got.Results)
+		})
+	}
+}
+
+func Test_dumpClient_GetResource(t *testing.T) {
+	tests := []struct {
+		name     string
+		client   Client
+		idOrName string
+		wantErr  error
+	}{
+		{
+			name:     "given api-data dump and id should return the berry",
+			client:   NewAPIDataClient("testdata/api-data", dumpBaseURL),
+			idOrName: "1",
+		},
+		{
+			name:     "given api-data dump and name should resolve it through the list",
+			client:   NewAPIDataClient("testdata/api-data", dumpBaseURL),
+			idOrName: "cheri",
+		},
+		{
+			name:     "given api-data dump and unknown name should return not found",
+			client:   NewAPIDataClient("testdata/api-data", dumpBaseURL),
+			idOrName: "unknown",
+			wantErr:  ErrNotFound,
+		},
+		{
+			name:     "given api-data dump without the detail file should return not found",
+			client:   NewAPIDataClient("testdata/api-data", dumpBaseURL),
+			idOrName: "chesto",
+			wantErr:  ErrNotFound,
+		},
+		{
+			name:     "given csv dump should return not found",
+			client:   NewCSVClient("testdata/csv", dumpBaseURL),
+			idOrName: "1",
+			wantErr:  ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Get[Berry](context.Background(), tt.client, tt.idOrName)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, "cheri", got.Name)
+			assert.Equal(t, NamedAPIResource{
+				Name: "soft",
+				URL:  "https://pokeapi.co/api/v2/berry-firmness/2/",
+			}, got.Firmness)
+		})
+	}
+}
+
+func Test_dumpClient_OpensOnce(t *testing.T) {
+	name := zipDir(t, "testdata/api-data")
+	client := NewAPIDataClient(name, dumpBaseURL)
+
+	_, err := List[Berry](context.Background(), client, ListRequest{Limit: 10})
+	assert.NoError(t, err)
+
+	// the archive opened by the first call keeps serving the later ones
+	assert.NoError(t, os.Remove(name))
+	berry, err := Get[Berry](context.Background(), client, "cheri")
+	assert.NoError(t, err)
+	assert.Equal(t, "cheri", berry.Name)
+}
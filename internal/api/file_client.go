@@ -0,0 +1,78 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// defaultListLimit mirrors the page size PokeAPI uses when no limit is given.
+const defaultListLimit = 20
+
+// fileClient serves berries from a local JSON dump of a PokeAPI list
+// response, e.g. the output of `curl 'https://pokeapi.co/api/v2/berry?limit=100000'`.
+// The dump only holds the berry list, so every other request is not found.
+type fileClient struct {
+	path string
+}
+
+// NewFileClient creates a Client reading from the JSON dump at path.
+func NewFileClient(path string) Client {
+	return &fileClient{path: path}
+}
+
+func (c *fileClient) ListResources(ctx context.Context, endpoint string, request ListRequest) (*NamedAPIResourceList, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if endpoint != EndpointBerry {
+		return nil, fmt.Errorf("%w: %s is not part of dump %s", ErrNotFound, endpoint, c.path)
+	}
+
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return nil, err
+	}
+
+	var dump NamedAPIResourceList
+	err = json.Unmarshal(data, &dump)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dump %s: %w", c.path, err)
+	}
+
+	return paginate(c.path, dump.Results, request), nil
+}
+
+func (c *fileClient) GetResource(ctx context.Context, endpoint string, idOrName string, out interface{}) error {
+	return fmt.Errorf("%w: dump %s has no %s/%s", ErrNotFound, c.path, endpoint, idOrName)
+}
+
+// paginate slices results the same way PokeAPI pages its list endpoints.
+func paginate(source string, results []NamedAPIResource, request ListRequest) *NamedAPIResourceList {
+	limit := request.Limit
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+	offset := request.Offset
+	if offset < 0 {
+		offset = 0
+	}
+
+	res := &NamedAPIResourceList{Count: len(results), Results: []NamedAPIResource{}}
+	if offset < len(results) {
+		end := offset + limit
+		if end > len(results) {
+			end = len(results)
+		}
+		res.Results = results[offset:end]
+	}
+	if offset+limit < len(results) {
+		res.Next = fmt.Sprintf("%s?offset=%d&limit=%d", source, offset+limit, limit)
+	}
+	if offset > 0 {
+		res.Previous = fmt.Sprintf("%s?offset=%d&limit=%d", source, max(offset-limit, 0), limit)
+	}
+
+	return res
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_fileClient_ListResources(t *testing.T) {
+	dir := t.TempDir()
+	dump := filepath.Join(dir, "berries.json")
+	err := os.WriteFile(dump, []byte(`{"count": 3, "results": [
+		{"name": "cheri", "url": "https://pokeapi.co/api/v2/berry/1/"},
+		{"name": "chesto", "url": "https://pokeapi.co/api/v2/berry/2/"},
+		{"name": "pecha", "url": "https://pokeapi.co/api/v2/berry/3/"}
+	]}`), 0o644)
+	assert.NoError(t, err)
+
+	invalid := filepath.Join(dir, "invalid.json")
+	err = os.WriteFile(invalid, []byte(`invalid-json`), 0o644)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		path      string
+		request   ListRequest
+		wantNames []string
+		wantNext  bool
+		wantErr   bool
+	}{
+		{
+			name:      "given first page should return results and next",
+			path:      dump,
+			request:   ListRequest{Limit: 2},
+			wantNames: []string{"cheri", "chesto"},
+			wantNext:  true,
+		},
+		{
+			name:      "given last page should return results without next",
+			path:      dump,
+			request:   ListRequest{Limit: 2, Offset: 2},
+			wantNames: []string{"pecha"},
+			wantNext:  false,
+		},
+		{
+			name:      "given no limit should return the default page",
+			path:      dump,
+			request:   ListRequest{},
+			wantNames: []string{"cheri", "chesto", "pecha"},
+			wantNext:  false,
+		},
+		{
+			name:      "given offset past the end should return empty results",
+			path:      dump,
+			request:   ListRequest{Limit: 2, Offset: 10},
+			wantNames: []string{},
+			wantNext:  false,
+		},
+		{
+			name:    "given missing dump should return an error",
+			path:    filepath.Join(dir, "missing.json"),
+			wantErr: true,
+		},
+		{
+			name:    "given invalid dump should return an error",
+			path:    invalid,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewFileClient(tt.path)
+			got, err := List[Berry](context.Background(), c, tt.request)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, got)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, 3, got.Count)
+			names := []string{}
+			for _, b := range got.Results {
+				names = append(names, b.Name)
+			}
+			assert.Equal(t, tt.wantNames, names)
+			assert.Equal(t, tt.wantNext, got.Next != "")
+		})
+	}
+}
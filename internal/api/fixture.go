@@ -0,0 +1,141 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ErrFixtureNotFound is returned in replay mode when no fixture was recorded for a request.
+var ErrFixtureNotFound = errors.New("no fixture recorded for request")
+
+var fixtureNameSanitizer = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+
+// fixture is the on-disk representation of a recorded upstream response.
+type fixture struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Status int         `json:"status"`
+	Header http.Header `json:"header"`
+	Body   string      `json:"body"`
+}
+
+// recordingTransport forwards requests to the next transport and stores every
+// successful response in dir so it can be served back by replayTransport.
+type recordingTransport struct {
+	dir  string
+	next http.RoundTripper
+}
+
+// NewRecordingTransport creates a http.RoundTripper that records upstream responses into dir.
+func NewRecordingTransport(dir string, next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &recordingTransport{dir: dir, next: next}
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// only successful responses are recorded, so a transient upstream
+	// failure never ends up frozen in a fixture
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	f := fixture{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Status: resp.StatusCode,
+		Header: resp.Header,
+		Body:   string(body),
+	}
+	if err = writeFixture(t.dir, fixtureName(req), f); err != nil {
+		return nil, fmt.Errorf("failed to record fixture: %w", err)
+	}
+
+	return resp, nil
+}
+
+// replayTransport serves responses previously stored by recordingTransport
+// and never touches the network.
+type replayTransport struct {
+	dir string
+}
+
+// NewReplayTransport creates a http.RoundTripper that serves recorded fixtures from dir.
+func NewReplayTransport(dir string) http.RoundTripper {
+	return &replayTransport{dir: dir}
+}
+
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	path := filepath.Join(t.dir, fixtureName(req))
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s %s (expected %s)", ErrFixtureNotFound, req.Method, req.URL, path)
+		}
+		return nil, err
+	}
+
+	var f fixture
+	if err = json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to read fixture %s: %w", path, err)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", f.Status, http.StatusText(f.Status)),
+		StatusCode:    f.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        f.Header,
+		Body:          io.NopCloser(strings.NewReader(f.Body)),
+		ContentLength: int64(len(f.Body)),
+		Request:       req,
+	}, nil
+}
+
+// fixtureName derives a stable file name from the request method, path and
+// sorted query, keeping it readable while the hash suffix prevents collisions.
+func fixtureName(req *http.Request) string {
+	query := req.URL.Query().Encode()
+	key := fmt.Sprintf("%s %s://%s%s?%s", req.Method, req.URL.Scheme, req.URL.Host, req.URL.Path, query)
+	sum := sha1.Sum([]byte(key))
+
+	readable := strings.Trim(fixtureNameSanitizer.ReplaceAllString(req.URL.Path+"_"+query, "_"), "_")
+	return fmt.Sprintf("%s_%s.json", readable, hex.EncodeToString(sum[:4]))
+}
+
+func writeFixture(dir, name string, f fixture) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, name), data, 0o644)
+}
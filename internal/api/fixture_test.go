@@ -0,0 +1,115 @@
+package api
+
+import (
+	"context"
+	"github.com/go-resty/resty/v2"
+	"github.com/inasknh/simple-poke-app/internal/config"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"os"
+	"testing"
+)
+
+func Test_New_RecordThenReplay(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.Api{
+		Host:        "https://pokeapi.co/api/v2/",
+		FixturesDir: dir,
+	}
+
+	respSuccess := &NamedAPIResourceList{
+		Count: 1,
+		Results: []NamedAPIResource{
+			{
+				Name: "cheri",
+				URL:  "https://pokeapi.co/api/v2/berry/1/",
+			},
+		},
+	}
+
+	// record against a mocked upstream
+	r := resty.New()
+	httpmock.ActivateNonDefault(r.GetClient())
+	httpmock.RegisterResponder("GET",
+		"https://pokeapi.co/api/v2/berry?limit=10&offset=0",
+		httpmock.NewJsonResponderOrPanic(http.StatusOK, respSuccess))
+
+	cfg.Client = ClientRecord
+	recorder, err := New(cfg, r)
+	assert.NoError(t, err)
+
+	resp, err := List[Berry](context.Background(), recorder, ListRequest{Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, respSuccess, resp)
+	httpmock.DeactivateAndReset()
+
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	// replay without any upstream
+	cfg.Client = ClientReplay
+	replayer, err := New(cfg, resty.New())
+	assert.NoError(t, err)
+
+	resp, err = List[Berry](context.Background(), replayer, ListRequest{Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, respSuccess, resp)
+}
+
+func Test_New_ReplayUnknownURL(t *testing.T) {
+	c, err := New(config.Api{
+		Client:      ClientReplay,
+		Host:        "https://pokeapi.co/api/v2/",
+		FixturesDir: t.TempDir(),
+	}, resty.New())
+	assert.NoError(t, err)
+
+	resp, err := List[Berry](context.Background(), c, ListRequest{Limit: 10})
+	assert.Nil(t, resp)
+	assert.ErrorIs(t, err, ErrFixtureNotFound)
+}
+
+func Test_New_InvalidClient(t *testing.T) {
+	tests := []struct {
+		name   string
+		config config.Api
+	}{
+		{
+			name:   "given unknown client should return an error",
+			config: config.Api{Client: "unknown"},
+		},
+		{
+			name:   "given record client without fixtures dir should return an error",
+			config: config.Api{Client: ClientRecord},
+		},
+		{
+			name:   "given replay client without fixtures dir should return an error",
+			config: config.Api{Client: ClientReplay},
+		},
+		{
+			name:   "given mirror client without mirror host should return an error",
+			config: config.Api{Client: ClientMirror},
+		},
+		{
+			name:   "given file client without dump file should return an error",
+			config: config.Api{Client: ClientFile},
+		},
+		{
+			name:   "given api-data client without data dump should return an error",
+			config: config.Api{Client: ClientAPIData},
+		},
+		{
+			name:   "given csv client without data dump should return an error",
+			config: config.Api{Client: ClientCSV},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := New(tt.config, resty.New())
+			assert.Error(t, err)
+			assert.Nil(t, c)
+		})
+	}
+}
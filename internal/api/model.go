@@ -1,18 +1,290 @@
 package api
 
-type BerriesRequest struct {
+import (
+	"strconv"
+	"strings"
+)
+
+// ListRequest pages through a PokeAPI list endpoint.
+type ListRequest struct {
 	Offset int `json:"offset"`
 	Limit  int `json:"limit"`
 }
 
-type Berry struct {
+// NamedAPIResource is a reference to another resource by name and url.
+type NamedAPIResource struct {
 	Name string `json:"name"`
-	Url  string `json:"url"`
+	URL  string `json:"url"`
+}
+
+// ID returns the id at the end of the resource url, or 0 when it has none.
+func (r NamedAPIResource) ID() int {
+	parts := strings.Split(strings.TrimSuffix(r.URL, "/"), "/")
+	id, err := strconv.Atoi(parts[len(parts)-1])
+	if err != nil {
+		return 0
+	}
+
+	return id
+}
+
+// APIResource is a reference to another resource by url only.
+type APIResource struct {
+	URL string `json:"url"`
+}
+
+// NamedAPIResourceList is the paginated response of every list endpoint.
+type NamedAPIResourceList struct {
+	Count    int                `json:"count"`
+	Next     string             `json:"next"`
+	Previous string             `json:"previous"`
+	Results  []NamedAPIResource `json:"results"`
+}
+
+// Name is the localized name of a resource.
+type Name struct {
+	Name     string           `json:"name"`
+	Language NamedAPIResource `json:"language"`
+}
+
+// Effect is a localized description of an effect.
+type Effect struct {
+	Effect   string           `json:"effect"`
+	Language NamedAPIResource `json:"language"`
+}
+
+// VerboseEffect is a localized description of an effect with a short version.
+type VerboseEffect struct {
+	Effect      string           `json:"effect"`
+	ShortEffect string           `json:"short_effect"`
+	Language    NamedAPIResource `json:"language"`
+}
+
+// VersionGroupFlavorText is a localized flavor text for a version group.
+type VersionGroupFlavorText struct {
+	Text         string           `json:"text"`
+	Language     NamedAPIResource `json:"language"`
+	VersionGroup NamedAPIResource `json:"version_group"`
+}
+
+// Berry is the /berry/{id or name} resource.
+type Berry struct {
+	ID               int              `json:"id"`
+	Name             string           `json:"name"`
+	GrowthTime       int              `json:"growth_time"`
+	MaxHarvest       int              `json:"max_harvest"`
+	NaturalGiftPower int              `json:"natural_gift_power"`
+	Size             int              `json:"size"`
+	Smoothness       int              `json:"smoothness"`
+	SoilDryness      int              `json:"soil_dryness"`
+	Firmness         NamedAPIResource `json:"firmness"`
+	Flavors          []BerryFlavorMap `json:"flavors"`
+	Item             NamedAPIResource `json:"item"`
+	NaturalGiftType  NamedAPIResource `json:"natural_gift_type"`
+}
+
+// BerryFlavorMap is the potency of a flavor in a berry.
+type BerryFlavorMap struct {
+	Potency int              `json:"potency"`
+	Flavor  NamedAPIResource `json:"flavor"`
+}
+
+// BerryFirmness is the /berry-firmness/{id or name} resource.
+type BerryFirmness struct {
+	ID      int                `json:"id"`
+	Name    string             `json:"name"`
+	Berries []NamedAPIResource `json:"berries"`
+	Names   []Name             `json:"names"`
+}
+
+// BerryFlavor is the /berry-flavor/{id or name} resource.
+type BerryFlavor struct {
+	ID          int              `json:"id"`
+	Name        string           `json:"name"`
+	Berries     []FlavorBerryMap `json:"berries"`
+	ContestType NamedAPIResource `json:"contest_type"`
+	Names       []Name           `json:"names"`
+}
+
+// FlavorBerryMap is the potency of a flavor in a berry, seen from the flavor.
+type FlavorBerryMap struct {
+	Potency int              `json:"potency"`
+	Berry   NamedAPIResource `json:"berry"`
+}
+
+// Item is the /item/{id or name} resource.
+type Item struct {
+	ID                int                      `json:"id"`
+	Name              string                   `json:"name"`
+	Cost              int                      `json:"cost"`
+	FlingPower        int                      `json:"fling_power"`
+	FlingEffect       *NamedAPIResource        `json:"fling_effect"`
+	Attributes        []NamedAPIResource       `json:"attributes"`
+	Category          NamedAPIResource         `json:"category"`
+	EffectEntries     []VerboseEffect          `json:"effect_entries"`
+	FlavorTextEntries []VersionGroupFlavorText `json:"flavor_text_entries"`
+	Names             []Name                   `json:"names"`
+	Sprites           ItemSprites              `json:"sprites"`
+}
+
+// ItemSprites holds the sprite urls of an item.
+type ItemSprites struct {
+	Default string `json:"default"`
+}
+
+// Pokemon is the /pokemon/{id or name} resource.
+type Pokemon struct {
+	ID             int              `json:"id"`
+	Name           string           `json:"name"`
+	BaseExperience int              `json:"base_experience"`
+	Height         int              `json:"height"`
+	Weight         int              `json:"weight"`
+	Order          int              `json:"order"`
+	IsDefault      bool             `json:"is_default"`
+	Abilities      []PokemonAbility `json:"abilities"`
+	Stats          []PokemonStat    `json:"stats"`
+	Types          []PokemonType    `json:"types"`
+	Species        NamedAPIResource `json:"species"`
+	Sprites        PokemonSprites   `json:"sprites"`
+}
+
+// PokemonAbility is an ability a Pokémon may have.
+type PokemonAbility struct {
+	IsHidden bool             `json:"is_hidden"`
+	Slot     int              `json:"slot"`
+	Ability  NamedAPIResource `json:"ability"`
+}
+
+// PokemonStat is a base stat of a Pokémon.
+type PokemonStat struct {
+	BaseStat int              `json:"base_stat"`
+	Effort   int              `json:"effort"`
+	Stat     NamedAPIResource `json:"stat"`
+}
+
+// PokemonType is a type of a Pokémon.
+type PokemonType struct {
+	Slot int              `json:"slot"`
+	Type NamedAPIResource `json:"type"`
+}
+
+// PokemonSprites holds the sprite urls of a Pokémon.
+type PokemonSprites struct {
+	FrontDefault string `json:"front_default"`
+	FrontShiny   string `json:"front_shiny"`
+	BackDefault  string `json:"back_default"`
+	BackShiny    string `json:"back_shiny"`
+}
+
+// PokemonSpecies is the /pokemon-species/{id or name} resource.
+type PokemonSpecies struct {
+	ID                 int                     `json:"id"`
+	Name               string                  `json:"name"`
+	Order              int                     `json:"order"`
+	CaptureRate        int                     `json:"capture_rate"`
+	BaseHappiness      int                     `json:"base_happiness"`
+	IsBaby             bool                    `json:"is_baby"`
+	IsLegendary        bool                    `json:"is_legendary"`
+	IsMythical         bool                    `json:"is_mythical"`
+	GrowthRate         NamedAPIResource        `json:"growth_rate"`
+	Color              NamedAPIResource        `json:"color"`
+	EvolvesFromSpecies *NamedAPIResource       `json:"evolves_from_species"`
+	EvolutionChain     APIResource             `json:"evolution_chain"`
+	Genera             []Genus                 `json:"genera"`
+	FlavorTextEntries  []FlavorText            `json:"flavor_text_entries"`
+	Names              []Name                  `json:"names"`
+	Varieties          []PokemonSpeciesVariety `json:"varieties"`
+}
+
+// Genus is the localized genus of a Pokémon species.
+type Genus struct {
+	Genus    string           `json:"genus"`
+	Language NamedAPIResource `json:"language"`
+}
+
+// FlavorText is a localized flavor text from a game version.
+type FlavorText struct {
+	FlavorText string           `json:"flavor_text"`
+	Language   NamedAPIResource `json:"language"`
+	Version    NamedAPIResource `json:"version"`
+}
+
+// PokemonSpeciesVariety is a Pokémon belonging to a species.
+type PokemonSpeciesVariety struct {
+	IsDefault bool             `json:"is_default"`
+	Pokemon   NamedAPIResource `json:"pokemon"`
+}
+
+// Type is the /type/{id or name} resource.
+type Type struct {
+	ID              int               `json:"id"`
+	Name            string            `json:"name"`
+	DamageRelations TypeRelations     `json:"damage_relations"`
+	Pokemon         []TypePokemon     `json:"pokemon"`
+	Names           []Name            `json:"names"`
+	MoveDamageClass *NamedAPIResource `json:"move_damage_class"`
+}
+
+// TypeRelations lists the types a type deals or takes modified damage from.
+type TypeRelations struct {
+	NoDamageTo       []NamedAPIResource `json:"no_damage_to"`
+	HalfDamageTo     []NamedAPIResource `json:"half_damage_to"`
+	DoubleDamageTo   []NamedAPIResource `json:"double_damage_to"`
+	NoDamageFrom     []NamedAPIResource `json:"no_damage_from"`
+	HalfDamageFrom   []NamedAPIResource `json:"half_damage_from"`
+	DoubleDamageFrom []NamedAPIResource `json:"double_damage_from"`
+}
+
+// TypePokemon is a Pokémon having a type.
+type TypePokemon struct {
+	Slot    int              `json:"slot"`
+	Pokemon NamedAPIResource `json:"pokemon"`
+}
+
+// EvolutionChain is the /evolution-chain/{id} resource.
+type EvolutionChain struct {
+	ID              int               `json:"id"`
+	BabyTriggerItem *NamedAPIResource `json:"baby_trigger_item"`
+	Chain           ChainLink         `json:"chain"`
+}
+
+// ChainLink is a species of an evolution chain and the species it evolves to.
+type ChainLink struct {
+	IsBaby           bool              `json:"is_baby"`
+	Species          NamedAPIResource  `json:"species"`
+	EvolutionDetails []EvolutionDetail `json:"evolution_details"`
+	EvolvesTo        []ChainLink       `json:"evolves_to"`
+}
+
+// EvolutionDetail is one way of evolving into a species.
+type EvolutionDetail struct {
+	Item                  *NamedAPIResource `json:"item"`
+	Trigger               NamedAPIResource  `json:"trigger"`
+	Gender                *int              `json:"gender"`
+	HeldItem              *NamedAPIResource `json:"held_item"`
+	KnownMove             *NamedAPIResource `json:"known_move"`
+	KnownMoveType         *NamedAPIResource `json:"known_move_type"`
+	Location              *NamedAPIResource `json:"location"`
+	MinLevel              *int              `json:"min_level"`
+	MinHappiness          *int              `json:"min_happiness"`
+	MinBeauty             *int              `json:"min_beauty"`
+	MinAffection          *int              `json:"min_affection"`
+	NeedsOverworldRain    bool              `json:"needs_overworld_rain"`
+	PartySpecies          *NamedAPIResource `json:"party_species"`
+	PartyType             *NamedAPIResource `json:"party_type"`
+	RelativePhysicalStats *int              `json:"relative_physical_stats"`
+	TimeOfDay             string            `json:"time_of_day"`
+	TradeSpecies          *NamedAPIResource `json:"trade_species"`
+	TurnUpsideDown        bool              `json:"turn_upside_down"`
 }
 
-type BerriesResponse struct {
-	Count    int     `json:"count"`
-	Next     string  `json:"next"`
-	Previous string  `json:"previous"`
-	Results  []Berry `json:"results"`
+// Nature is the /nature/{id or name} resource. Neutral natures raise and
+// lower no stat and have no flavor preference.
+type Nature struct {
+	ID            int               `json:"id"`
+	Name          string            `json:"name"`
+	DecreasedStat *NamedAPIResource `json:"decreased_stat"`
+	IncreasedStat *NamedAPIResource `json:"increased_stat"`
+	LikesFlavor   *NamedAPIResource `json:"likes_flavor"`
+	HatesFlavor   *NamedAPIResource `json:"hates_flavor"`
 }
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"context"
+	"errors"
+)
+
+// PokeAPI endpoints of the resources we consume.
+const (
+	EndpointBerry         = "berry"
+	EndpointBerryFirmness = "berry-firmness"
+	EndpointBerryFlavor   = "berry-flavor"
+	EndpointItem          = "item"
+	EndpointPokemon       = "pokemon"
+	EndpointSpecies       = "pokemon-species"
+	EndpointType          = "type"
+	EndpointEvolution     = "evolution-chain"
+	EndpointNature        = "nature"
+)
+
+// ErrNotFound is returned when a resource does not exist upstream.
+var ErrNotFound = errors.New("resource not found")
+
+// Resource is a typed PokeAPI resource that can be listed and fetched by id or name.
+type Resource interface {
+	Berry | BerryFirmness | BerryFlavor | Item | Pokemon | PokemonSpecies | Type | EvolutionChain | Nature
+}
+
+// Endpoint returns the PokeAPI endpoint serving T.
+func Endpoint[T Resource]() string {
+	var zero T
+	switch any(zero).(type) {
+	case Berry:
+		return EndpointBerry
+	case BerryFirmness:
+		return EndpointBerryFirmness
+	case BerryFlavor:
+		return EndpointBerryFlavor
+	case Item:
+		return EndpointItem
+	case Pokemon:
+		return EndpointPokemon
+	case PokemonSpecies:
+		return EndpointSpecies
+	case Type:
+		return EndpointType
+	case EvolutionChain:
+		return EndpointEvolution
+	case Nature:
+		return EndpointNature
+	default:
+		panic("api: resource without endpoint")
+	}
+}
+
+// Get fetches a single T by id or name.
+func Get[T Resource](ctx context.Context, c Client, idOrName string) (*T, error) {
+	var res T
+	if err := c.GetResource(ctx, Endpoint[T](), idOrName, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// List fetches one page of references to T.
+func List[T Resource](ctx context.Context, c Client, request ListRequest) (*NamedAPIResourceList, error) {
+	return c.ListResources(ctx, Endpoint[T](), request)
+}
+
+// ListAll follows the pagination of the T list endpoint and returns every reference.
+func ListAll[T Resource](ctx context.Context, c Client, pageSize int) ([]NamedAPIResource, error) {
+	var all []NamedAPIResource
+	err := EachPage(ctx, c, Endpoint[T](), pageSize, func(results []NamedAPIResource) error {
+		all = append(all, results...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return all, nil
+}
+
+// EachPage calls fn with every page of endpoint until the list is exhausted.
+func EachPage(ctx context.Context, c Client, endpoint string, pageSize int, fn func([]NamedAPIResource) error) error {
+	request := ListRequest{Limit: pageSize}
+	for {
+		res, err := c.ListResources(ctx, endpoint, request)
+		if err != nil {
+			return err
+		}
+
+		if err = fn(res.Results); err != nil {
+			return err
+		}
+
+		if res.Next == "" || len(res.Results) == 0 {
+			return nil
+		}
+		request.Offset += len(res.Results)
+	}
+}
@@ -0,0 +1,76 @@
+package cache
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func Test_LRU_EvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewLRU[string, int](2, time.Minute)
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+
+	// touch a so b becomes the least recently used entry
+	_, ok := c.Get("a")
+	assert.True(t, ok)
+
+	c.Set("c", 3)
+
+	_, ok = c.Get("b")
+	assert.False(t, ok)
+
+	v, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+
+	v, ok = c.Get("c")
+	assert.True(t, ok)
+	assert.Equal(t, 3, v)
+	assert.Equal(t, 2, c.Len())
+}
+
+func Test_LRU_Expiry(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewLRU[string, int](2, time.Minute)
+	c.now = func() time.Time { return now }
+
+	c.Set("a", 1)
+
+	now = now.Add(30 * time.Second)
+	_, ok := c.Get("a")
+	assert.True(t, ok)
+
+	now = now.Add(time.Minute)
+	_, ok = c.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 0, c.Len())
+}
+
+func Test_LRU_UpdateDeleteAndPurge(t *testing.T) {
+	c := NewLRU[string, int](2, time.Minute)
+
+	c.Set("a", 1)
+	c.Set("a", 2)
+	v, _ := c.Get("a")
+	assert.Equal(t, 2, v)
+	assert.Equal(t, 1, c.Len())
+
+	c.Delete("a")
+	_, ok := c.Get("a")
+	assert.False(t, ok)
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Purge()
+	assert.Equal(t, 0, c.Len())
+}
+
+func Test_LRU_ZeroSize(t *testing.T) {
+	c := NewLRU[string, int](0, time.Minute)
+
+	c.Set("a", 1)
+	_, ok := c.Get("a")
+	assert.False(t, ok)
+}
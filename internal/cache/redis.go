@@ -7,11 +7,37 @@ import (
 	"log"
 )
 
-func NewRedis(config config.Cache) *redis.Client {
-	rdb := redis.NewClient(&redis.Options{
-		Addr:     fmt.Sprintf("%s:%s", config.Host, config.Port),
-		Password: config.Password,
-	})
+// Redis deployment modes.
+const (
+	ModeSingle   = "single"
+	ModeSentinel = "sentinel"
+	ModeCluster  = "cluster"
+)
+
+func NewRedis(config config.Cache) redis.UniversalClient {
+	addrs := config.Addrs
+	if len(addrs) == 0 {
+		addrs = []string{fmt.Sprintf("%s:%s", config.Host, config.Port)}
+	}
+
+	opts := &redis.UniversalOptions{
+		Addrs:      addrs,
+		Username:   config.User,
+		Password:   config.Password,
+		MasterName: config.MasterName,
+	}
+
+	var rdb redis.UniversalClient
+	switch config.Mode {
+	case ModeCluster:
+		rdb = redis.NewClusterClient(opts.Cluster())
+	case ModeSentinel:
+		rdb = redis.NewFailoverClient(opts.Failover())
+	case ModeSingle, "":
+		rdb = redis.NewClient(opts.Simple())
+	default:
+		log.Fatalf("Unknown redis mode %q", config.Mode)
+	}
 
 	if err := rdb.Ping().Err(); err != nil {
 		log.Fatalf("Redis cannot be pinged %s", err)
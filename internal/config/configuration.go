@@ -8,22 +8,62 @@ type DatabaseConfiguration struct {
 }
 
 type AppConfiguration struct {
-	Name string `yaml:"name"`
-	Port int    `yaml:"port"`
-	TTL  int    `yaml:"ttl"`
+	Name    string `yaml:"name"`
+	Port    int    `yaml:"port"`
+	TTL     int    `yaml:"ttl"`
+	SoftTTL int    `yaml:"soft_ttl" mapstructure:"soft_ttl"`
+	// WarmupTimeout bounds warming the cache on startup, in seconds, 0 skips it.
+	WarmupTimeout int `yaml:"warmup_timeout" mapstructure:"warmup_timeout"`
 }
 
 type Cache struct {
-	Host     string `yaml:"host"`
-	Port     string `yaml:"port"`
+	Host string `yaml:"host"`
+	Port string `yaml:"port"`
+	// User is the ACL username, empty authenticates with Password only.
 	User     string `yaml:"user"`
 	Password string `yaml:"password"`
+	// Mode is the Redis deployment, "single", "sentinel" or "cluster".
+	Mode string `yaml:"mode"`
+	// Addrs lists the sentinel or cluster seed nodes, Host and Port are used when empty.
+	Addrs []string `yaml:"addrs"`
+	// MasterName is the Sentinel master set name.
+	MasterName string `yaml:"master_name" mapstructure:"master_name"`
+	// LocalSize bounds the in-process cache in front of Redis, 0 disables it.
+	LocalSize int `yaml:"local_size" mapstructure:"local_size"`
+	// LocalTTL is how long, in seconds, an entry stays in the in-process cache.
+	LocalTTL int `yaml:"local_ttl" mapstructure:"local_ttl"`
+	// Timeout bounds every Redis call, in milliseconds, 0 disables it.
+	Timeout int `yaml:"timeout"`
+	// Compression of cached values, "gzip" or "none".
+	Compression string `yaml:"compression"`
+	// CompressionThreshold is the payload size, in bytes, from which values are compressed.
+	CompressionThreshold int `yaml:"compression_threshold" mapstructure:"compression_threshold"`
+	// Prefix namespaces every key and channel used by the app. In cluster
+	// mode a hash tag such as "{poke-app}:" keeps them in one slot.
+	Prefix string `yaml:"prefix"`
+}
+
+type Admin struct {
+	// Token authenticates admin requests as a bearer token, empty disables the admin endpoints.
+	Token string `yaml:"token"`
 }
 
 type Api struct {
-	Client string `yaml:"client"`
-	Host   string `yaml:"host"`
-	Path   string `yaml:"path"`
+	Client      string `yaml:"client"`
+	Host        string `yaml:"host"`
+	FixturesDir string `yaml:"fixtures_dir" mapstructure:"fixtures_dir"`
+	MirrorHost  string `yaml:"mirror_host" mapstructure:"mirror_host"`
+	DumpFile    string `yaml:"dump_file" mapstructure:"dump_file"`
+	DataDump    string `yaml:"data_dump" mapstructure:"data_dump"`
+}
+
+type Assets struct {
+	// Store is the blob store sprites are mirrored to, "file" or "none" to serve upstream urls.
+	Store string `yaml:"store"`
+	// Dir is the root directory of the file store.
+	Dir string `yaml:"dir"`
+	// BaseURL prefixes the content hash in the asset urls exposed by the API.
+	BaseURL string `yaml:"base_url" mapstructure:"base_url"`
 }
 
 type Configurations struct {
@@ -31,4 +71,6 @@ type Configurations struct {
 	Database DatabaseConfiguration `yaml:"database"`
 	Cache    Cache                 `yaml:"cache"`
 	Api      Api                   `yaml:"api"`
+	Admin    Admin                 `yaml:"admin"`
+	Assets   Assets                `yaml:"assets"`
 }
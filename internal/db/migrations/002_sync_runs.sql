@@ -0,0 +1,7 @@
+USE poke_app;
+
+-- Record every successful sync, the latest run versions the dataset
+CREATE TABLE IF NOT EXISTS `sync_runs` (
+                                        id INT AUTO_INCREMENT PRIMARY KEY,
+                                        finished_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
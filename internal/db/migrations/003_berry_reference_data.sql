@@ -0,0 +1,29 @@
+USE poke_app;
+
+-- Berries are upserted by name from now on, drop duplicates left by earlier syncs
+DELETE b1 FROM berries b1 JOIN berries b2 ON b1.name = b2.name AND b1.id > b2.id;
+
+-- Firmness and flavor ids are the PokeAPI ids
+CREATE TABLE IF NOT EXISTS `firmnesses` (
+                                         id INT PRIMARY KEY,
+                                         name VARCHAR(255) NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS `flavors` (
+                                      id INT PRIMARY KEY,
+                                      name VARCHAR(255) NOT NULL UNIQUE
+);
+
+ALTER TABLE `berries`
+    ADD UNIQUE KEY uq_berries_name (name),
+    ADD COLUMN firmness_id INT NULL,
+    ADD CONSTRAINT fk_berries_firmness FOREIGN KEY (firmness_id) REFERENCES firmnesses (id);
+
+CREATE TABLE IF NOT EXISTS `berry_flavors` (
+                                            berry_id INT NOT NULL,
+                                            flavor_id INT NOT NULL,
+                                            potency INT NOT NULL,
+                                            PRIMARY KEY (berry_id, flavor_id),
+                                            FOREIGN KEY (berry_id) REFERENCES berries (id) ON DELETE CASCADE,
+                                            FOREIGN KEY (flavor_id) REFERENCES flavors (id)
+);
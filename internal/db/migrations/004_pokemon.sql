@@ -0,0 +1,54 @@
+USE poke_app;
+
+-- Pokémon, type and ability ids are the PokeAPI ids
+CREATE TABLE IF NOT EXISTS `pokemon` (
+                                      id INT PRIMARY KEY,
+                                      name VARCHAR(255) NOT NULL UNIQUE,
+                                      species VARCHAR(255) NOT NULL,
+                                      height INT NOT NULL,
+                                      weight INT NOT NULL,
+                                      base_experience INT NOT NULL,
+                                      capture_rate INT NOT NULL,
+                                      is_legendary BOOLEAN NOT NULL,
+                                      is_mythical BOOLEAN NOT NULL,
+                                      sprite VARCHAR(255) NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS `types` (
+                                    id INT PRIMARY KEY,
+                                    name VARCHAR(255) NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS `abilities` (
+                                        id INT PRIMARY KEY,
+                                        name VARCHAR(255) NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS `pokemon_types` (
+                                            pokemon_id INT NOT NULL,
+                                            type_id INT NOT NULL,
+                                            slot INT NOT NULL,
+                                            PRIMARY KEY (pokemon_id, type_id),
+                                            FOREIGN KEY (pokemon_id) REFERENCES pokemon (id) ON DELETE CASCADE,
+                                            FOREIGN KEY (type_id) REFERENCES types (id)
+);
+
+CREATE TABLE IF NOT EXISTS `pokemon_abilities` (
+                                                pokemon_id INT NOT NULL,
+                                                ability_id INT NOT NULL,
+                                                slot INT NOT NULL,
+                                                is_hidden BOOLEAN NOT NULL,
+                                                PRIMARY KEY (pokemon_id, ability_id),
+                                                FOREIGN KEY (pokemon_id) REFERENCES pokemon (id) ON DELETE CASCADE,
+                                                FOREIGN KEY (ability_id) REFERENCES abilities (id)
+);
+
+CREATE TABLE IF NOT EXISTS `pokemon_stats` (
+                                            pokemon_id INT NOT NULL,
+                                            stat VARCHAR(64) NOT NULL,
+                                            slot INT NOT NULL,
+                                            base_stat INT NOT NULL,
+                                            effort INT NOT NULL,
+                                            PRIMARY KEY (pokemon_id, stat),
+                                            FOREIGN KEY (pokemon_id) REFERENCES pokemon (id) ON DELETE CASCADE
+);
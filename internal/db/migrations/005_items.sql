@@ -0,0 +1,43 @@
+USE poke_app;
+
+-- Item, category, attribute and fling effect ids are the PokeAPI ids
+CREATE TABLE IF NOT EXISTS `item_categories` (
+                                              id INT PRIMARY KEY,
+                                              name VARCHAR(255) NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS `item_attributes` (
+                                              id INT PRIMARY KEY,
+                                              name VARCHAR(255) NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS `fling_effects` (
+                                            id INT PRIMARY KEY,
+                                            name VARCHAR(255) NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS `items` (
+                                    id INT PRIMARY KEY,
+                                    name VARCHAR(255) NOT NULL UNIQUE,
+                                    cost INT NOT NULL,
+                                    fling_power INT NOT NULL,
+                                    fling_effect_id INT NULL,
+                                    category_id INT NOT NULL,
+                                    effect TEXT NOT NULL,
+                                    sprite VARCHAR(255) NOT NULL,
+                                    FOREIGN KEY (fling_effect_id) REFERENCES fling_effects (id),
+                                    FOREIGN KEY (category_id) REFERENCES item_categories (id)
+);
+
+CREATE TABLE IF NOT EXISTS `item_attribute_map` (
+                                                 item_id INT NOT NULL,
+                                                 attribute_id INT NOT NULL,
+                                                 PRIMARY KEY (item_id, attribute_id),
+                                                 FOREIGN KEY (item_id) REFERENCES items (id) ON DELETE CASCADE,
+                                                 FOREIGN KEY (attribute_id) REFERENCES item_attributes (id)
+);
+
+-- Every berry is also an item
+ALTER TABLE `berries`
+    ADD COLUMN item_id INT NULL,
+    ADD CONSTRAINT fk_berries_item FOREIGN KEY (item_id) REFERENCES items (id);
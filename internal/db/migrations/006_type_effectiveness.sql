@@ -0,0 +1,17 @@
+USE poke_app;
+
+-- Damage multiplier of an attacking type against a defending one, pairs
+-- dealing regular damage are not stored
+CREATE TABLE IF NOT EXISTS `type_damage_relations` (
+                                                    attacking_type_id INT NOT NULL,
+                                                    defending_type_id INT NOT NULL,
+                                                    multiplier DECIMAL(2, 1) NOT NULL,
+                                                    PRIMARY KEY (attacking_type_id, defending_type_id),
+                                                    FOREIGN KEY (attacking_type_id) REFERENCES types (id) ON DELETE CASCADE,
+                                                    FOREIGN KEY (defending_type_id) REFERENCES types (id) ON DELETE CASCADE
+);
+
+ALTER TABLE `berries`
+    ADD COLUMN natural_gift_type_id INT NULL,
+    ADD COLUMN natural_gift_power INT NULL,
+    ADD CONSTRAINT fk_berries_natural_gift_type FOREIGN KEY (natural_gift_type_id) REFERENCES types (id);
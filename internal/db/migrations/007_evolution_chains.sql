@@ -0,0 +1,42 @@
+USE poke_app;
+
+-- Evolution chains are stored as a graph: species are the nodes, every way
+-- of evolving from one species into another is an edge. Chain and species
+-- ids are the PokeAPI ids.
+CREATE TABLE IF NOT EXISTS `evolution_chains` (
+                                               id INT PRIMARY KEY,
+                                               baby_trigger_item VARCHAR(255) NULL
+);
+
+CREATE TABLE IF NOT EXISTS `evolution_species` (
+                                                id INT PRIMARY KEY,
+                                                name VARCHAR(255) NOT NULL UNIQUE,
+                                                chain_id INT NOT NULL,
+                                                is_baby BOOLEAN NOT NULL,
+                                                FOREIGN KEY (chain_id) REFERENCES evolution_chains (id) ON DELETE CASCADE
+);
+
+-- Items are kept by name, the item catalogue may not be synced
+CREATE TABLE IF NOT EXISTS `evolution_edges` (
+                                              id INT AUTO_INCREMENT PRIMARY KEY,
+                                              chain_id INT NOT NULL,
+                                              from_species_id INT NOT NULL,
+                                              to_species_id INT NOT NULL,
+                                              `trigger` VARCHAR(255) NOT NULL,
+                                              item VARCHAR(255) NULL,
+                                              held_item VARCHAR(255) NULL,
+                                              FOREIGN KEY (chain_id) REFERENCES evolution_chains (id) ON DELETE CASCADE,
+                                              FOREIGN KEY (from_species_id) REFERENCES evolution_species (id),
+                                              FOREIGN KEY (to_species_id) REFERENCES evolution_species (id),
+                                              INDEX idx_evolution_edges_from (from_species_id),
+                                              INDEX idx_evolution_edges_to (to_species_id)
+);
+
+-- Any other requirement of an edge, e.g. min_level = 16 or time_of_day = night
+CREATE TABLE IF NOT EXISTS `evolution_conditions` (
+                                                   edge_id INT NOT NULL,
+                                                   name VARCHAR(64) NOT NULL,
+                                                   value VARCHAR(255) NOT NULL,
+                                                   PRIMARY KEY (edge_id, name),
+                                                   FOREIGN KEY (edge_id) REFERENCES evolution_edges (id) ON DELETE CASCADE
+);
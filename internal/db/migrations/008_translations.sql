@@ -0,0 +1,12 @@
+USE poke_app;
+
+-- Localized item names and flavor texts, language is the PokeAPI language
+-- name. Berries are translated through their item.
+CREATE TABLE IF NOT EXISTS `item_translations` (
+                                                item_id INT NOT NULL,
+                                                language VARCHAR(16) NOT NULL,
+                                                name VARCHAR(255) NOT NULL,
+                                                description TEXT NOT NULL,
+                                                PRIMARY KEY (item_id, language),
+                                                FOREIGN KEY (item_id) REFERENCES items (id) ON DELETE CASCADE
+);
@@ -0,0 +1,11 @@
+USE poke_app;
+
+-- Upstream sprite urls mirrored to the blob store, blobs are keyed by the
+-- SHA-256 of their content so urls serving the same image share one blob.
+CREATE TABLE IF NOT EXISTS `assets` (
+                                     url VARCHAR(255) PRIMARY KEY,
+                                     hash CHAR(64) NOT NULL,
+                                     content_type VARCHAR(64) NOT NULL,
+                                     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+                                     INDEX (hash)
+);
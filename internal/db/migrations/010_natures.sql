@@ -0,0 +1,15 @@
+USE poke_app;
+
+-- Natures raise one stat and lower another, the Pokémon likes the flavor
+-- tied to the raised stat and hates the one tied to the lowered stat.
+-- Neutral natures have no preference and leave every column NULL.
+CREATE TABLE IF NOT EXISTS `natures` (
+                                      id INT PRIMARY KEY,
+                                      name VARCHAR(255) NOT NULL UNIQUE,
+                                      increased_stat VARCHAR(32) NULL,
+                                      decreased_stat VARCHAR(32) NULL,
+                                      likes_flavor_id INT NULL,
+                                      hates_flavor_id INT NULL,
+                                      FOREIGN KEY (likes_flavor_id) REFERENCES flavors (id),
+                                      FOREIGN KEY (hates_flavor_id) REFERENCES flavors (id)
+);
@@ -0,0 +1,8 @@
+USE poke_app;
+
+-- How a berry tree grows, null until the berry growth sync ran
+ALTER TABLE `berries`
+    ADD COLUMN growth_time INT NULL,
+    ADD COLUMN max_harvest INT NULL,
+    ADD COLUMN size INT NULL,
+    ADD COLUMN soil_dryness INT NULL;
@@ -0,0 +1,11 @@
+USE poke_app;
+
+-- Localized names of the species of every Pokémon, language is the PokeAPI
+-- language name
+CREATE TABLE IF NOT EXISTS `pokemon_names` (
+                                            pokemon_id INT NOT NULL,
+                                            language VARCHAR(16) NOT NULL,
+                                            name VARCHAR(255) NOT NULL,
+                                            PRIMARY KEY (pokemon_id, language),
+                                            FOREIGN KEY (pokemon_id) REFERENCES pokemon (id) ON DELETE CASCADE
+);
@@ -0,0 +1,4 @@
+USE poke_app;
+
+-- unknown and shadow have no damage relations and are no longer synced
+DELETE FROM `types` WHERE name IN ('unknown', 'shadow');
@@ -0,0 +1,278 @@
+package fakepokeapi
+
+// flavorNames are indexed by PokeAPI berry-flavor id - 1.
+var flavorNames = []string{"spicy", "dry", "sweet", "bitter", "sour"}
+
+// firmnessNames are indexed by PokeAPI berry-firmness id - 1.
+var firmnessNames = []string{"very-soft", "soft", "hard", "very-hard", "super-hard"}
+
+// seedBerry is a compact row of the berry data served by the fake server.
+type seedBerry struct {
+	ID               int
+	Name             string
+	GrowthTime       int
+	MaxHarvest       int
+	NaturalGiftPower int
+	NaturalGiftType  string
+	Size             int
+	Smoothness       int
+	SoilDryness      int
+	Firmness         int
+	// Potency per flavor, in the same order as flavorNames.
+	Potency [5]int
+}
+
+// seedBerries mirrors the first berries of the real PokeAPI dataset.
+var seedBerries = []seedBerry{
+	{1, "cheri", 3, 5, 60, "fire", 20, 25, 15, 2, [5]int{10, 0, 0, 0, 0}},
+	{2, "chesto", 3, 5, 60, "water", 80, 25, 15, 5, [5]int{0, 10, 0, 0, 0}},
+	{3, "pecha", 3, 5, 60, "electric", 40, 25, 15, 1, [5]int{0, 0, 10, 0, 0}},
+	{4, "rawst", 3, 5, 60, "grass", 32, 25, 15, 3, [5]int{0, 0, 0, 10, 0}},
+	{5, "aspear", 3, 5, 60, "ice", 50, 25, 15, 5, [5]int{0, 0, 0, 0, 10}},
+	{6, "leppa", 4, 5, 60, "fighting", 28, 20, 15, 4, [5]int{10, 0, 10, 10, 10}},
+	{7, "oran", 4, 5, 60, "poison", 35, 20, 15, 5, [5]int{10, 10, 0, 10, 10}},
+	{8, "persim", 4, 5, 60, "ground", 47, 20, 15, 3, [5]int{10, 10, 10, 0, 10}},
+	{9, "lum", 12, 5, 60, "flying", 34, 20, 8, 5, [5]int{10, 10, 10, 10, 0}},
+	{10, "sitrus", 8, 5, 60, "psychic", 95, 20, 7, 4, [5]int{0, 10, 10, 10, 10}},
+	{11, "figy", 5, 5, 60, "bug", 100, 25, 10, 2, [5]int{15, 0, 0, 0, 0}},
+	{12, "wiki", 5, 5, 60, "rock", 115, 25, 10, 3, [5]int{0, 15, 0, 0, 0}},
+}
+
+// seedPokemon is a compact row of the Pokémon data served by the fake server.
+type seedPokemon struct {
+	ID             int
+	Name           string
+	Height         int
+	Weight         int
+	BaseExperience int
+	CaptureRate    int
+	// Types and Abilities are PokeAPI ids and names, in slot order.
+	Types     []seedRef
+	Abilities []seedRef
+	// Stats are the base stats in PokeAPI order: hp, attack, defense,
+	// special-attack, special-defense, speed.
+	Stats [6]int
+}
+
+type seedRef struct {
+	ID   int
+	Name string
+}
+
+var statNames = []string{"hp", "attack", "defense", "special-attack", "special-defense", "speed"}
+
+// seedPokemonList mirrors the first Pokémon of the real PokeAPI dataset.
+var seedPokemonList = []seedPokemon{
+	{1, "bulbasaur", 7, 69, 64, 45, []seedRef{{12, "grass"}, {4, "poison"}}, []seedRef{{65, "overgrow"}, {34, "chlorophyll"}}, [6]int{45, 49, 49, 65, 65, 45}},
+	{4, "charmander", 6, 85, 62, 45, []seedRef{{10, "fire"}}, []seedRef{{66, "blaze"}, {94, "solar-power"}}, [6]int{39, 52, 43, 60, 50, 65}},
+	{7, "squirtle", 5, 90, 63, 45, []seedRef{{11, "water"}}, []seedRef{{67, "torrent"}, {44, "rain-dish"}}, [6]int{44, 48, 65, 50, 64, 43}},
+}
+
+// pokemonNames are the localized species names served besides the English one.
+var pokemonNames = map[string]map[string]string{
+	"bulbasaur":  {"fr": "Bulbizarre", "ja": "フシギダネ"},
+	"charmander": {"fr": "Salamèche", "ja": "ヒトカゲ"},
+	"squirtle":   {"fr": "Carapuce", "ja": "ゼニガメ"},
+}
+
+// seedItem is a compact row of the item data served by the fake server.
+type seedItem struct {
+	ID          int
+	Name        string
+	Cost        int
+	FlingPower  int
+	FlingEffect *seedRef
+	Category    seedRef
+	Attributes  []seedRef
+	Effect      string
+}
+
+var (
+	countable       = seedRef{1, "countable"}
+	consumable      = seedRef{2, "consumable"}
+	usableOverworld = seedRef{3, "usable-overworld"}
+	usableInBattle  = seedRef{4, "usable-in-battle"}
+	holdable        = seedRef{5, "holdable"}
+)
+
+// seedItems mirrors a few real PokeAPI items followed by the item of every
+// seed berry.
+var seedItems = append([]seedItem{
+	{1, "master-ball", 0, 0, nil, seedRef{34, "standard-balls"}, []seedRef{countable, consumable, usableInBattle, holdable}, "Catches a wild Pokémon every time."},
+	{17, "potion", 300, 30, nil, seedRef{27, "healing"}, []seedRef{countable, consumable, usableOverworld, usableInBattle, holdable}, "Restores 20 HP."},
+}, berryItems()...)
+
+// pickyHealing are the berries confusing a Pokémon that dislikes their flavor.
+var pickyHealing = map[string]bool{"figy": true, "wiki": true, "mago": true, "aguav": true, "iapapa": true}
+
+func berryItems() []seedItem {
+	items := make([]seedItem, 0, len(seedBerries))
+	for _, b := range seedBerries {
+		category := seedRef{3, "medicine"}
+		if pickyHealing[b.Name] {
+			category = seedRef{6, "picky-healing"}
+		}
+		items = append(items, seedItem{
+			ID:          berryItemID(b),
+			Name:        b.Name + "-berry",
+			Cost:        20,
+			FlingPower:  10,
+			FlingEffect: &seedRef{3, "berry-effect"},
+			Category:    category,
+			Attributes:  []seedRef{holdable},
+			Effect:      "Held: Consumed when the holder needs it.",
+		})
+	}
+	return items
+}
+
+func berryItemID(b seedBerry) int {
+	return 125 + b.ID
+}
+
+// seedNature is a nature and the stats it raises and lowers, neutral
+// natures have neither.
+type seedNature struct {
+	ID        int
+	Name      string
+	Increased string
+	Decreased string
+}
+
+// statFlavors maps a stat to the flavor liked by the natures raising it.
+var statFlavors = map[string]string{
+	"attack":          "spicy",
+	"defense":         "sour",
+	"special-attack":  "dry",
+	"special-defense": "bitter",
+	"speed":           "sweet",
+}
+
+// seedNatures mirrors every PokeAPI nature.
+var seedNatures = []seedNature{
+	{1, "hardy", "", ""},
+	{2, "bold", "defense", "attack"},
+	{3, "modest", "special-attack", "attack"},
+	{4, "calm", "special-defense", "attack"},
+	{5, "timid", "speed", "attack"},
+	{6, "lonely", "attack", "defense"},
+	{7, "docile", "", ""},
+	{8, "mild", "special-attack", "defense"},
+	{9, "gentle", "special-defense", "defense"},
+	{10, "hasty", "speed", "defense"},
+	{11, "adamant", "attack", "special-attack"},
+	{12, "impish", "defense", "special-attack"},
+	{13, "bashful", "", ""},
+	{14, "careful", "special-defense", "special-attack"},
+	{15, "rash", "special-attack", "special-defense"},
+	{16, "jolly", "speed", "special-attack"},
+	{17, "naughty", "attack", "special-defense"},
+	{18, "lax", "defense", "special-defense"},
+	{19, "quirky", "", ""},
+	{20, "naive", "speed", "special-defense"},
+	{21, "brave", "attack", "speed"},
+	{22, "relaxed", "defense", "speed"},
+	{23, "quiet", "special-attack", "speed"},
+	{24, "sassy", "special-defense", "speed"},
+	{25, "serious", "", ""},
+}
+
+// typeNames are indexed by PokeAPI type id - 1.
+var typeNames = []string{
+	"normal", "fighting", "flying", "poison", "ground", "rock", "bug", "ghost", "steel",
+	"fire", "water", "grass", "electric", "psychic", "ice", "dragon", "dark", "fairy",
+}
+
+// specialTypes are listed by PokeAPI without damage relations, no Pokémon
+// or move has them in the main series.
+var specialTypes = []seedRef{{ID: 10001, Name: "unknown"}, {ID: 10002, Name: "shadow"}}
+
+// seedDamage lists the types an attacking type deals modified damage to.
+type seedDamage struct {
+	Double []string
+	Half   []string
+	No     []string
+}
+
+// typeDamage is the real type chart, keyed by attacking type.
+var typeDamage = map[string]seedDamage{
+	"normal":   {nil, []string{"rock", "steel"}, []string{"ghost"}},
+	"fighting": {[]string{"normal", "rock", "steel", "ice", "dark"}, []string{"flying", "poison", "bug", "psychic", "fairy"}, []string{"ghost"}},
+	"flying":   {[]string{"fighting", "bug", "grass"}, []string{"rock", "steel", "electric"}, nil},
+	"poison":   {[]string{"grass", "fairy"}, []string{"poison", "ground", "rock", "ghost"}, []string{"steel"}},
+	"ground":   {[]string{"poison", "rock", "steel", "fire", "electric"}, []string{"bug", "grass"}, []string{"flying"}},
+	"rock":     {[]string{"flying", "bug", "fire", "ice"}, []string{"fighting", "ground", "steel"}, nil},
+	"bug":      {[]string{"grass", "psychic", "dark"}, []string{"fighting", "flying", "poison", "ghost", "steel", "fire", "fairy"}, nil},
+	"ghost":    {[]string{"ghost", "psychic"}, []string{"dark"}, []string{"normal"}},
+	"steel":    {[]string{"rock", "ice", "fairy"}, []string{"steel", "fire", "water", "electric"}, nil},
+	"fire":     {[]string{"bug", "steel", "grass", "ice"}, []string{"rock", "fire", "water", "dragon"}, nil},
+	"water":    {[]string{"ground", "rock", "fire"}, []string{"water", "grass", "dragon"}, nil},
+	"grass":    {[]string{"ground", "rock", "water"}, []string{"flying", "poison", "bug", "steel", "fire", "grass", "dragon"}, nil},
+	"electric": {[]string{"flying", "water"}, []string{"grass", "electric", "dragon"}, []string{"ground"}},
+	"psychic":  {[]string{"fighting", "poison"}, []string{"steel", "psychic"}, []string{"dark"}},
+	"ice":      {[]string{"flying", "ground", "grass", "dragon"}, []string{"steel", "fire", "water", "ice"}, nil},
+	"dragon":   {[]string{"dragon"}, []string{"steel"}, []string{"fairy"}},
+	"dark":     {[]string{"ghost", "psychic"}, []string{"fighting", "dark", "fairy"}, nil},
+	"fairy":    {[]string{"fighting", "dragon", "dark"}, []string{"poison", "steel", "fire"}, nil},
+}
+
+func typeID(name string) int {
+	for i, t := range typeNames {
+		if t == name {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// seedEvolution is a species of an evolution chain and how it is reached
+// from its parent.
+type seedEvolution struct {
+	Species      seedRef
+	Trigger      string
+	Item         *seedRef
+	MinLevel     int
+	MinHappiness int
+	TimeOfDay    string
+	EvolvesTo    []seedEvolution
+}
+
+type seedChain struct {
+	ID    int
+	Chain seedEvolution
+}
+
+// evolutionTriggers maps trigger names to their PokeAPI ids.
+var evolutionTriggers = map[string]int{"level-up": 1, "trade": 2, "use-item": 3}
+
+func levelUp(id int, name string, level int, evolvesTo ...seedEvolution) seedEvolution {
+	return seedEvolution{Species: seedRef{id, name}, Trigger: "level-up", MinLevel: level, EvolvesTo: evolvesTo}
+}
+
+func useItem(id int, name string, item seedRef) seedEvolution {
+	return seedEvolution{Species: seedRef{id, name}, Trigger: "use-item", Item: &item}
+}
+
+func friendship(id int, name string, timeOfDay string) seedEvolution {
+	return seedEvolution{Species: seedRef{id, name}, Trigger: "level-up", MinHappiness: 160, TimeOfDay: timeOfDay}
+}
+
+// seedChains mirrors the evolution chains of the seed Pokémon and Eevee.
+var seedChains = []seedChain{
+	{1, seedEvolution{Species: seedRef{1, "bulbasaur"}, EvolvesTo: []seedEvolution{
+		levelUp(2, "ivysaur", 16, levelUp(3, "venusaur", 32)),
+	}}},
+	{2, seedEvolution{Species: seedRef{4, "charmander"}, EvolvesTo: []seedEvolution{
+		levelUp(5, "charmeleon", 16, levelUp(6, "charizard", 36)),
+	}}},
+	{3, seedEvolution{Species: seedRef{7, "squirtle"}, EvolvesTo: []seedEvolution{
+		levelUp(8, "wartortle", 16, levelUp(9, "blastoise", 36)),
+	}}},
+	{67, seedEvolution{Species: seedRef{133, "eevee"}, EvolvesTo: []seedEvolution{
+		useItem(134, "vaporeon", seedRef{84, "water-stone"}),
+		useItem(135, "jolteon", seedRef{83, "thunder-stone"}),
+		useItem(136, "flareon", seedRef{82, "fire-stone"}),
+		friendship(196, "espeon", "day"),
+		friendship(197, "umbreon", "night"),
+	}}},
+}
@@ -0,0 +1,601 @@
+// Package fakepokeapi provides an in-process fake of the PokeAPI berry,
+// item, nature and Pokémon endpoints and their sprites for integration
+// tests, with knobs to inject upstream faults.
+package fakepokeapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	apiPrefix    = "/api/v2/"
+	spritePrefix = "/sprites/"
+	defaultLimit = 20
+)
+
+type namedAPIResource struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type listResponse struct {
+	Count    int                `json:"count"`
+	Next     *string            `json:"next"`
+	Previous *string            `json:"previous"`
+	Results  []namedAPIResource `json:"results"`
+}
+
+// Server is a fake PokeAPI backed by seed data.
+type Server struct {
+	*httptest.Server
+
+	mu              sync.Mutex
+	latency         time.Duration
+	tooManyRequests int
+	serverErrors    int
+	malformedJSON   int
+	maxLimit        int
+	requests        int
+}
+
+// New starts a fake PokeAPI server. Callers must Close it.
+func New() *Server {
+	s := &Server{}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// BaseURL returns the API root, equivalent to https://pokeapi.co/api/v2/.
+func (s *Server) BaseURL() string {
+	return s.URL + apiPrefix
+}
+
+// SetLatency delays every response by d.
+func (s *Server) SetLatency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = d
+}
+
+// FailWithTooManyRequests answers the next n requests with 429.
+func (s *Server) FailWithTooManyRequests(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tooManyRequests = n
+}
+
+// FailWithServerErrors answers the next n requests with 500.
+func (s *Server) FailWithServerErrors(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.serverErrors = n
+}
+
+// FailWithMalformedJSON answers the next n requests with 200 and a truncated JSON body.
+func (s *Server) FailWithMalformedJSON(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.malformedJSON = n
+}
+
+// SetMaxLimit caps the page size of list endpoints regardless of the requested limit.
+func (s *Server) SetMaxLimit(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.maxLimit = n
+}
+
+// Requests returns how many requests the server has received.
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+// nextFault consumes one pending fault, returning the status to answer with
+// and whether the body must be malformed.
+func (s *Server) nextFault() (time.Duration, int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+
+	switch {
+	case s.tooManyRequests > 0:
+		s.tooManyRequests--
+		return s.latency, http.StatusTooManyRequests, false
+	case s.serverErrors > 0:
+		s.serverErrors--
+		return s.latency, http.StatusInternalServerError, false
+	case s.malformedJSON > 0:
+		s.malformedJSON--
+		return s.latency, http.StatusOK, true
+	default:
+		return s.latency, 0, false
+	}
+}
+
+func (s *Server) serveHTTP(rw http.ResponseWriter, r *http.Request) {
+	latency, status, malformed := s.nextFault()
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if status != 0 && status != http.StatusOK {
+		http.Error(rw, http.StatusText(status), status)
+		return
+	}
+	if malformed {
+		rw.Header().Set("Content-Type", "application/json")
+		_, _ = rw.Write([]byte(`{"count": 1, "results": [`))
+		return
+	}
+
+	if r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, spritePrefix) {
+		serveSprite(rw)
+		return
+	}
+	if r.Method != http.MethodGet || !strings.HasPrefix(r.URL.Path, apiPrefix) {
+		http.NotFound(rw, r)
+		return
+	}
+
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, apiPrefix), "/"), "/")
+	switch {
+	case len(parts) == 1:
+		s.serveList(rw, r, parts[0])
+	case len(parts) == 2:
+		s.serveDetail(rw, r, parts[0], parts[1])
+	default:
+		http.NotFound(rw, r)
+	}
+}
+
+// serveSprite answers every sprite with the same blank 1x1 PNG.
+func serveSprite(rw http.ResponseWriter) {
+	rw.Header().Set("Content-Type", "image/png")
+	_ = png.Encode(rw, image.NewRGBA(image.Rect(0, 0, 1, 1)))
+}
+
+func (s *Server) serveList(rw http.ResponseWriter, r *http.Request, resource string) {
+	var all []namedAPIResource
+	switch resource {
+	case "berry":
+		for _, b := range seedBerries {
+			all = append(all, s.resource(resource, b.ID, b.Name))
+		}
+	case "berry-firmness":
+		for i, name := range firmnessNames {
+			all = append(all, s.resource(resource, i+1, name))
+		}
+	case "berry-flavor":
+		for i, name := range flavorNames {
+			all = append(all, s.resource(resource, i+1, name))
+		}
+	case "pokemon", "pokemon-species":
+		for _, p := range seedPokemonList {
+			all = append(all, s.resource(resource, p.ID, p.Name))
+		}
+	case "item":
+		for _, item := range seedItems {
+			all = append(all, s.resource(resource, item.ID, item.Name))
+		}
+	case "type":
+		for i, name := range typeNames {
+			all = append(all, s.resource(resource, i+1, name))
+		}
+		for _, t := range specialTypes {
+			all = append(all, s.resource(resource, t.ID, t.Name))
+		}
+	case "nature":
+		for _, n := range seedNatures {
+			all = append(all, s.resource(resource, n.ID, n.Name))
+		}
+	case "evolution-chain":
+		for _, c := range seedChains {
+			all = append(all, namedAPIResource{URL: fmt.Sprintf("%s%s/%d/", s.BaseURL(), resource, c.ID)})
+		}
+	default:
+		http.NotFound(rw, r)
+		return
+	}
+
+	limit := queryInt(r, "limit", defaultLimit)
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+	s.mu.Lock()
+	if s.maxLimit > 0 && limit > s.maxLimit {
+		limit = s.maxLimit
+	}
+	s.mu.Unlock()
+	offset := queryInt(r, "offset", 0)
+	if offset < 0 {
+		offset = 0
+	}
+
+	res := listResponse{Count: len(all), Results: []namedAPIResource{}}
+	if offset < len(all) {
+		end := offset + limit
+		if end > len(all) {
+			end = len(all)
+		}
+		res.Results = all[offset:end]
+	}
+	if offset+limit < len(all) {
+		next := fmt.Sprintf("%s%s?offset=%d&limit=%d", s.BaseURL(), resource, offset+limit, limit)
+		res.Next = &next
+	}
+	if offset > 0 {
+		prevOffset := offset - limit
+		if prevOffset < 0 {
+			prevOffset = 0
+		}
+		prev := fmt.Sprintf("%s%s?offset=%d&limit=%d", s.BaseURL(), resource, prevOffset, limit)
+		res.Previous = &prev
+	}
+
+	writeJSON(rw, res)
+}
+
+func (s *Server) serveDetail(rw http.ResponseWriter, r *http.Request, resource, idOrName string) {
+	var body interface{}
+	switch resource {
+	case "berry":
+		for _, b := range seedBerries {
+			if matches(idOrName, b.ID, b.Name) {
+				body = s.berry(b)
+			}
+		}
+	case "berry-firmness":
+		for i, name := range firmnessNames {
+			if matches(idOrName, i+1, name) {
+				body = s.firmness(i+1, name)
+			}
+		}
+	case "berry-flavor":
+		for i, name := range flavorNames {
+			if matches(idOrName, i+1, name) {
+				body = s.flavor(i+1, name)
+			}
+		}
+	case "pokemon":
+		for _, p := range seedPokemonList {
+			if matches(idOrName, p.ID, p.Name) {
+				body = s.pokemon(p)
+			}
+		}
+	case "pokemon-species":
+		for _, p := range seedPokemonList {
+			if matches(idOrName, p.ID, p.Name) {
+				body = s.species(p)
+			}
+		}
+	case "item":
+		for _, item := range seedItems {
+			if matches(idOrName, item.ID, item.Name) {
+				body = s.item(item)
+			}
+		}
+	case "type":
+		for i, name := range typeNames {
+			if matches(idOrName, i+1, name) {
+				body = s.typ(i+1, name)
+			}
+		}
+		for _, t := range specialTypes {
+			if matches(idOrName, t.ID, t.Name) {
+				body = s.typ(t.ID, t.Name)
+			}
+		}
+	case "nature":
+		for _, n := range seedNatures {
+			if matches(idOrName, n.ID, n.Name) {
+				body = s.nature(n)
+			}
+		}
+	case "evolution-chain":
+		for _, c := range seedChains {
+			if idOrName == strconv.Itoa(c.ID) {
+				body = map[string]interface{}{
+					"id":                c.ID,
+					"baby_trigger_item": nil,
+					"chain":             s.chainLink(c.Chain, false),
+				}
+			}
+		}
+	}
+
+	if body == nil {
+		http.NotFound(rw, r)
+		return
+	}
+
+	writeJSON(rw, body)
+}
+
+func (s *Server) berry(b seedBerry) map[string]interface{} {
+	flavors := make([]map[string]interface{}, 0, len(flavorNames))
+	for i, name := range flavorNames {
+		flavors = append(flavors, map[string]interface{}{
+			"potency": b.Potency[i],
+			"flavor":  s.resource("berry-flavor", i+1, name),
+		})
+	}
+
+	return map[string]interface{}{
+		"id":                 b.ID,
+		"name":               b.Name,
+		"growth_time":        b.GrowthTime,
+		"max_harvest":        b.MaxHarvest,
+		"natural_gift_power": b.NaturalGiftPower,
+		"size":               b.Size,
+		"smoothness":         b.Smoothness,
+		"soil_dryness":       b.SoilDryness,
+		"firmness":           s.resource("berry-firmness", b.Firmness, firmnessNames[b.Firmness-1]),
+		"flavors":            flavors,
+		"item":               s.resource("item", berryItemID(b), b.Name+"-berry"),
+		"natural_gift_type":  s.resource("type", typeID(b.NaturalGiftType), b.NaturalGiftType),
+	}
+}
+
+func (s *Server) firmness(id int, name string) map[string]interface{} {
+	berries := []namedAPIResource{}
+	for _, b := range seedBerries {
+		if b.Firmness == id {
+			berries = append(berries, s.resource("berry", b.ID, b.Name))
+		}
+	}
+
+	return map[string]interface{}{
+		"id":      id,
+		"name":    name,
+		"berries": berries,
+	}
+}
+
+func (s *Server) flavor(id int, name string) map[string]interface{} {
+	berries := []map[string]interface{}{}
+	for _, b := range seedBerries {
+		if b.Potency[id-1] > 0 {
+			berries = append(berries, map[string]interface{}{
+				"potency": b.Potency[id-1],
+				"berry":   s.resource("berry", b.ID, b.Name),
+			})
+		}
+	}
+
+	return map[string]interface{}{
+		"id":      id,
+		"name":    name,
+		"berries": berries,
+	}
+}
+
+func (s *Server) pokemon(p seedPokemon) map[string]interface{} {
+	types := make([]map[string]interface{}, 0, len(p.Types))
+	for i, t := range p.Types {
+		types = append(types, map[string]interface{}{
+			"slot": i + 1,
+			"type": s.resource("type", t.ID, t.Name),
+		})
+	}
+	abilities := make([]map[string]interface{}, 0, len(p.Abilities))
+	for i, a := range p.Abilities {
+		abilities = append(abilities, map[string]interface{}{
+			"is_hidden": i == len(p.Abilities)-1,
+			"slot":      i + 1,
+			"ability":   s.resource("ability", a.ID, a.Name),
+		})
+	}
+	stats := make([]map[string]interface{}, 0, len(statNames))
+	for i, name := range statNames {
+		stats = append(stats, map[string]interface{}{
+			"base_stat": p.Stats[i],
+			"effort":    0,
+			"stat":      s.resource("stat", i+1, name),
+		})
+	}
+
+	return map[string]interface{}{
+		"id":              p.ID,
+		"name":            p.Name,
+		"base_experience": p.BaseExperience,
+		"height":          p.Height,
+		"weight":          p.Weight,
+		"order":           p.ID,
+		"is_default":      true,
+		"abilities":       abilities,
+		"stats":           stats,
+		"types":           types,
+		"species":         s.resource("pokemon-species", p.ID, p.Name),
+		"sprites": map[string]interface{}{
+			"front_default": fmt.Sprintf("%s/sprites/pokemon/%d.png", s.URL, p.ID),
+		},
+	}
+}
+
+func (s *Server) species(p seedPokemon) map[string]interface{} {
+	names := []map[string]interface{}{
+		{"name": displayName(p.Name), "language": s.resource("language", 9, "en")},
+	}
+	if name, ok := pokemonNames[p.Name]["fr"]; ok {
+		names = append(names, map[string]interface{}{"name": name, "language": s.resource("language", 5, "fr")})
+	}
+	if name, ok := pokemonNames[p.Name]["ja"]; ok {
+		names = append(names, map[string]interface{}{"name": name, "language": s.resource("language", 11, "ja")})
+	}
+
+	return map[string]interface{}{
+		"id":              p.ID,
+		"name":            p.Name,
+		"order":           p.ID,
+		"capture_rate":    p.CaptureRate,
+		"base_happiness":  50,
+		"is_baby":         false,
+		"is_legendary":    false,
+		"is_mythical":     false,
+		"evolution_chain": map[string]interface{}{"url": fmt.Sprintf("%sevolution-chain/%d/", s.BaseURL(), (p.ID+2)/3)},
+		"names":           names,
+		"varieties": []map[string]interface{}{
+			{"is_default": true, "pokemon": s.resource("pokemon", p.ID, p.Name)},
+		},
+	}
+}
+
+func (s *Server) item(item seedItem) map[string]interface{} {
+	attributes := make([]namedAPIResource, 0, len(item.Attributes))
+	for _, a := range item.Attributes {
+		attributes = append(attributes, s.resource("item-attribute", a.ID, a.Name))
+	}
+	var flingEffect *namedAPIResource
+	if item.FlingEffect != nil {
+		res := s.resource("item-fling-effect", item.FlingEffect.ID, item.FlingEffect.Name)
+		flingEffect = &res
+	}
+
+	return map[string]interface{}{
+		"id":           item.ID,
+		"name":         item.Name,
+		"cost":         item.Cost,
+		"fling_power":  item.FlingPower,
+		"fling_effect": flingEffect,
+		"attributes":   attributes,
+		"category":     s.resource("item-category", item.Category.ID, item.Category.Name),
+		"effect_entries": []map[string]interface{}{
+			{"effect": item.Effect, "short_effect": item.Effect, "language": s.resource("language", 9, "en")},
+		},
+		"names": []map[string]interface{}{
+			{"name": displayName(item.Name), "language": s.resource("language", 9, "en")},
+		},
+		"flavor_text_entries": []map[string]interface{}{
+			{"text": "An old\nflavor text.", "language": s.resource("language", 9, "en"), "version_group": s.resource("version-group", 1, "red-blue")},
+			{"text": item.Effect, "language": s.resource("language", 9, "en"), "version_group": s.resource("version-group", 20, "sword-shield")},
+		},
+		"sprites": map[string]interface{}{
+			"default": fmt.Sprintf("%s/sprites/items/%s.png", s.URL, item.Name),
+		},
+	}
+}
+
+func (s *Server) typ(id int, name string) map[string]interface{} {
+	damage := typeDamage[name]
+	types := func(names []string) []namedAPIResource {
+		res := []namedAPIResource{}
+		for _, n := range names {
+			res = append(res, s.resource("type", typeID(n), n))
+		}
+		return res
+	}
+
+	return map[string]interface{}{
+		"id":   id,
+		"name": name,
+		"damage_relations": map[string]interface{}{
+			"double_damage_to": types(damage.Double),
+			"half_damage_to":   types(damage.Half),
+			"no_damage_to":     types(damage.No),
+		},
+	}
+}
+
+func (s *Server) chainLink(e seedEvolution, evolved bool) map[string]interface{} {
+	details := []map[string]interface{}{}
+	if evolved {
+		detail := map[string]interface{}{
+			"trigger":     s.resource("evolution-trigger", evolutionTriggers[e.Trigger], e.Trigger),
+			"item":        nil,
+			"min_level":   nil,
+			"time_of_day": e.TimeOfDay,
+		}
+		if e.Item != nil {
+			detail["item"] = s.resource("item", e.Item.ID, e.Item.Name)
+		}
+		if e.MinLevel > 0 {
+			detail["min_level"] = e.MinLevel
+		}
+		if e.MinHappiness > 0 {
+			detail["min_happiness"] = e.MinHappiness
+		}
+		details = append(details, detail)
+	}
+	evolvesTo := []map[string]interface{}{}
+	for _, next := range e.EvolvesTo {
+		evolvesTo = append(evolvesTo, s.chainLink(next, true))
+	}
+
+	return map[string]interface{}{
+		"is_baby":           false,
+		"species":           s.resource("pokemon-species", e.Species.ID, e.Species.Name),
+		"evolution_details": details,
+		"evolves_to":        evolvesTo,
+	}
+}
+
+func (s *Server) nature(n seedNature) map[string]interface{} {
+	body := map[string]interface{}{
+		"id":             n.ID,
+		"name":           n.Name,
+		"increased_stat": nil,
+		"decreased_stat": nil,
+		"likes_flavor":   nil,
+		"hates_flavor":   nil,
+	}
+	if n.Increased == "" {
+		return body
+	}
+
+	body["increased_stat"] = s.resource("stat", slices.Index(statNames, n.Increased)+1, n.Increased)
+	body["decreased_stat"] = s.resource("stat", slices.Index(statNames, n.Decreased)+1, n.Decreased)
+	liked, hated := statFlavors[n.Increased], statFlavors[n.Decreased]
+	body["likes_flavor"] = s.resource("berry-flavor", slices.Index(flavorNames, liked)+1, liked)
+	body["hates_flavor"] = s.resource("berry-flavor", slices.Index(flavorNames, hated)+1, hated)
+	return body
+}
+
+func (s *Server) resource(resource string, id int, name string) namedAPIResource {
+	return namedAPIResource{
+		Name: name,
+		URL:  fmt.Sprintf("%s%s/%d/", s.BaseURL(), resource, id),
+	}
+}
+
+// displayName turns a slug into an English name, e.g. cheri-berry into Cheri Berry.
+func displayName(slug string) string {
+	words := strings.Split(slug, "-")
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + w[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+func matches(idOrName string, id int, name string) bool {
+	return idOrName == name || idOrName == strconv.Itoa(id)
+}
+
+func queryInt(r *http.Request, key string, fallback int) int {
+	v, err := strconv.Atoi(r.URL.Query().Get(key))
+	if err != nil {
+		return fallback
+	}
+	return v
+}
+
+func writeJSON(rw http.ResponseWriter, data interface{}) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(rw).Encode(data)
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"errors"
+	"github.com/inasknh/simple-poke-app/internal/model"
+	"github.com/inasknh/simple-poke-app/internal/service"
+	"net/http"
+	"strings"
+)
+
+// AdminHandler handles the cache management endpoints.
+type AdminHandler struct {
+	service service.Service
+	token   string
+}
+
+// NewAdminHandler creates an AdminHandler authenticating requests with token.
+func NewAdminHandler(service service.Service, token string) *AdminHandler {
+	return &AdminHandler{
+		service: service,
+		token:   token,
+	}
+}
+
+// Authenticate rejects requests without the admin bearer token. Every
+// request is rejected when no token is configured.
+func (h *AdminHandler) Authenticate(next http.HandlerFunc) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if h.token == "" || !ok || subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) != 1 {
+			httpResponseWrite(rw, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		next(rw, r)
+	}
+}
+
+func (h *AdminHandler) ListCacheKeys(rw http.ResponseWriter, r *http.Request) {
+	keys, err := h.service.ListCacheKeys(r.Context())
+	if err != nil {
+		httpResponseWrite(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	httpResponseWrite(rw, keys, http.StatusOK)
+}
+
+func (h *AdminHandler) DeleteCacheKey(rw http.ResponseWriter, r *http.Request) {
+	err := h.service.DeleteCacheKey(r.Context(), r.PathValue("key"))
+	if errors.Is(err, model.ErrNotFound) {
+		httpResponseWrite(rw, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		httpResponseWrite(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	httpResponseWrite(rw, "OK", http.StatusOK)
+}
+
+func (h *AdminHandler) PurgeCache(rw http.ResponseWriter, r *http.Request) {
+	purged, err := h.service.PurgeCache(r.Context())
+	if err != nil {
+		httpResponseWrite(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	httpResponseWrite(rw, map[string]int{"purged": purged}, http.StatusOK)
+}
+
+func (h *AdminHandler) WarmCache(rw http.ResponseWriter, r *http.Request) {
+	if err := h.service.WarmCache(r.Context()); err != nil {
+		httpResponseWrite(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	httpResponseWrite(rw, "OK", http.StatusOK)
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_AdminHandler_Authenticate(t *testing.T) {
+	tests := []struct {
+		name          string
+		token         string
+		authorization string
+		wantStatus    int
+	}{
+		{
+			name:          "given the configured bearer token should call the handler",
+			token:         "secret",
+			authorization: "Bearer secret",
+			wantStatus:    http.StatusOK,
+		},
+		{
+			name:       "given no Authorization header should return unauthorized",
+			token:      "secret",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:          "given a wrong bearer token should return unauthorized",
+			token:         "secret",
+			authorization: "Bearer secrets",
+			wantStatus:    http.StatusUnauthorized,
+		},
+		{
+			name:          "given the token without the Bearer scheme should return unauthorized",
+			token:         "secret",
+			authorization: "secret",
+			wantStatus:    http.StatusUnauthorized,
+		},
+		{
+			name:          "given another scheme should return unauthorized",
+			token:         "secret",
+			authorization: "Basic secret",
+			wantStatus:    http.StatusUnauthorized,
+		},
+		{
+			name:          "given no configured token should reject an empty bearer token",
+			token:         "",
+			authorization: "Bearer ",
+			wantStatus:    http.StatusUnauthorized,
+		},
+		{
+			name:          "given no configured token should reject any bearer token",
+			token:         "",
+			authorization: "Bearer secret",
+			wantStatus:    http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			h := NewAdminHandler(nil, tt.token)
+			next := h.Authenticate(func(rw http.ResponseWriter, r *http.Request) {
+				called = true
+				rw.WriteHeader(http.StatusOK)
+			})
+
+			r := httptest.NewRequest(http.MethodGet, "/admin/cache/keys", nil)
+			if tt.authorization != "" {
+				r.Header.Set("Authorization", tt.authorization)
+			}
+			rw := httptest.NewRecorder()
+			next(rw, r)
+
+			assert.Equal(t, tt.wantStatus, rw.Code)
+			assert.Equal(t, tt.wantStatus == http.StatusOK, called)
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"compress/gzip"
+	"github.com/andybalholm/brotli"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Content codings offered to clients, most preferred first.
+const (
+	encodingBrotli = "br"
+	encodingGzip   = "gzip"
+)
+
+// Compress encodes responses with the best coding the client accepts.
+func Compress(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Add("Vary", "Accept-Encoding")
+
+		encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+		if encoding == "" {
+			next.ServeHTTP(rw, r)
+			return
+		}
+
+		cw := &compressResponseWriter{ResponseWriter: rw, encoding: encoding}
+		defer func() {
+			_ = cw.Close()
+		}()
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// negotiateEncoding picks the supported coding with the highest q-value in
+// an Accept-Encoding header, or "" for an identity response.
+func negotiateEncoding(header string) string {
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != encodingBrotli && coding != encodingGzip {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		// q=0 marks a coding as not acceptable
+		if q <= 0 {
+			continue
+		}
+
+		if q > bestQ || (q == bestQ && coding == encodingBrotli) {
+			best, bestQ = coding, q
+		}
+	}
+
+	return best
+}
+
+// precompressed reports whether a media type is already compressed, like
+// most image formats, so encoding it again only costs CPU.
+func precompressed(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	return strings.HasPrefix(mediaType, "image/") && mediaType != "image/svg+xml"
+}
+
+// compressResponseWriter compresses the body once the status is known, so
+// responses without a body are left untouched.
+type compressResponseWriter struct {
+	http.ResponseWriter
+	encoding    string
+	encoder     io.WriteCloser
+	wroteHeader bool
+}
+
+func (w *compressResponseWriter) WriteHeader(statusCode int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+
+	header := w.Header()
+	// a range of the identity representation cannot be encoded on its own
+	if statusCode == http.StatusNoContent || statusCode == http.StatusPartialContent ||
+		header.Get("Content-Encoding") != "" || precompressed(header.Get("Content-Type")) {
+		w.ResponseWriter.WriteHeader(statusCode)
+		return
+	}
+
+	// the encoded bytes differ from the identity representation, a 304
+	// must carry the validator of the response it stands for
+	if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		header.Set("ETag", "W/"+etag)
+	}
+
+	if statusCode != http.StatusNotModified {
+		header.Set("Content-Encoding", w.encoding)
+		header.Del("Content-Length")
+
+		switch w.encoding {
+		case encodingBrotli:
+			w.encoder = brotli.NewWriter(w.ResponseWriter)
+		case encodingGzip:
+			w.encoder = gzip.NewWriter(w.ResponseWriter)
+		}
+	}
+
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *compressResponseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.encoder == nil {
+		return w.ResponseWriter.Write(b)
+	}
+
+	return w.encoder.Write(b)
+}
+
+// Flush sends the data compressed so far, letting streamed listings reach the client early.
+func (w *compressResponseWriter) Flush() {
+	if f, ok := w.encoder.(interface{ Flush() error }); ok {
+		_ = f.Flush()
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Close flushes the remaining compressed data.
+func (w *compressResponseWriter) Close() error {
+	if w.encoder == nil {
+		return nil
+	}
+
+	return w.encoder.Close()
+}
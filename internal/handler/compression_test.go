@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"compress/gzip"
+	"github.com/andybalholm/brotli"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_negotiateEncoding(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{
+			name:   "given no header should return identity",
+			header: "",
+			want:   "",
+		},
+		{
+			name:   "given both codings should prefer brotli",
+			header: "gzip, deflate, br",
+			want:   encodingBrotli,
+		},
+		{
+			name:   "given a higher q-value should prefer it",
+			header: "br;q=0.5, gzip;q=0.8",
+			want:   encodingGzip,
+		},
+		{
+			name:   "given mixed case and spaces should match the coding",
+			header: " GZIP ; q=0.3 ",
+			want:   encodingGzip,
+		},
+		{
+			name:   "given a coding refused with q=0 should not pick it",
+			header: "br;q=0, gzip",
+			want:   encodingGzip,
+		},
+		{
+			name:   "given every supported coding refused should return identity",
+			header: "br;q=0, gzip;q=0",
+			want:   "",
+		},
+		{
+			name:   "given an invalid q-value should ignore the coding",
+			header: "br;q=high, gzip;q=0.1",
+			want:   encodingGzip,
+		},
+		{
+			name:   "given only unsupported codings should return identity",
+			header: "deflate, compress",
+			want:   "",
+		},
+		{
+			name:   "given identity refused with q=0 should still pick a supported coding",
+			header: "identity;q=0, gzip",
+			want:   encodingGzip,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, negotiateEncoding(tt.header))
+		})
+	}
+}
+
+func Test_Compress(t *testing.T) {
+	body := `{"berries":[{"name":"cheri","url":"https://pokeapi.co/api/v2/berry/1/"}]}`
+
+	tests := []struct {
+		name         string
+		encoding     string
+		status       int
+		contentType  string
+		wantEncoding string
+		wantETag     string
+	}{
+		{
+			name:         "given brotli should encode the body and weaken the etag",
+			encoding:     "br",
+			status:       http.StatusOK,
+			contentType:  "application/json",
+			wantEncoding: encodingBrotli,
+			wantETag:     `W/"sync-1-en"`,
+		},
+		{
+			name:         "given gzip should encode the body and weaken the etag",
+			encoding:     "gzip",
+			status:       http.StatusOK,
+			contentType:  "application/json",
+			wantEncoding: encodingGzip,
+			wantETag:     `W/"sync-1-en"`,
+		},
+		{
+			name:        "given a not modified response should weaken the etag without encoding",
+			encoding:    "gzip",
+			status:      http.StatusNotModified,
+			contentType: "application/json",
+			wantETag:    `W/"sync-1-en"`,
+		},
+		{
+			name:        "given an image should leave it and its etag untouched",
+			encoding:    "gzip",
+			status:      http.StatusOK,
+			contentType: "image/png",
+			wantETag:    `"sync-1-en"`,
+		},
+		{
+			name:        "given identity should leave the response untouched",
+			encoding:    "identity",
+			status:      http.StatusOK,
+			contentType: "application/json",
+			wantETag:    `"sync-1-en"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				rw.Header().Set("Content-Type", tt.contentType)
+				rw.Header().Set("ETag", `"sync-1-en"`)
+				rw.WriteHeader(tt.status)
+				if tt.status != http.StatusNotModified {
+					_, _ = io.WriteString(rw, body)
+				}
+			})
+
+			r := httptest.NewRequest(http.MethodGet, "/items", nil)
+			r.Header.Set("Accept-Encoding", tt.encoding)
+			rw := httptest.NewRecorder()
+			Compress(next).ServeHTTP(rw, r)
+
+			assert.Equal(t, tt.status, rw.Code)
+			assert.Equal(t, tt.wantEncoding, rw.Header().Get("Content-Encoding"))
+			assert.Equal(t, tt.wantETag, rw.Header().Get("ETag"))
+			assert.Equal(t, "Accept-Encoding", rw.Header().Get("Vary"))
+
+			var reader io.Reader = rw.Body
+			switch tt.wantEncoding {
+			case encodingBrotli:
+				reader = brotli.NewReader(rw.Body)
+			case encodingGzip:
+				gz, err := gzip.NewReader(rw.Body)
+				assert.NoError(t, err)
+				reader = gz
+			}
+			got, err := io.ReadAll(reader)
+			assert.NoError(t, err)
+			if tt.status == http.StatusNotModified {
+				assert.Empty(t, got)
+			} else {
+				assert.Equal(t, body, string(got))
+			}
+		})
+	}
+}
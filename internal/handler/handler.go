@@ -2,8 +2,16 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/inasknh/simple-poke-app/internal/model"
 	"github.com/inasknh/simple-poke-app/internal/service"
+	"log"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // Handler struct handles HTTP requests related to simple-poke-app.
@@ -31,17 +39,388 @@ func (h *Handler) SyncData(rw http.ResponseWriter, r *http.Request) {
 
 }
 
+func (h *Handler) SyncPokemon(rw http.ResponseWriter, r *http.Request) {
+	if err := h.service.SyncPokemon(r.Context()); err != nil {
+		httpResponseWrite(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	httpResponseWrite(rw, "OK", http.StatusOK)
+}
+
+// GetItems serves the cached listing. Only a no-cache request streams it
+// from the database, a cache miss is rebuilt whole as it is cached for the
+// next readers.
 func (h *Handler) GetItems(rw http.ResponseWriter, r *http.Request) {
-	res, err := h.service.GetItems(r.Context())
+	lang := language(r)
+	setLanguageHeaders(rw, lang)
+	if bypassCache(r) {
+		h.streamItems(rw, r, lang)
+		return
+	}
+
+	res, status, err := h.service.GetItems(r.Context(), lang)
+	rw.Header().Set("X-Cache", string(status))
 	if err != nil {
 		httpResponseWrite(rw, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	etag := itemsETag(res, lang)
+	setCacheHeaders(rw, etag, res.SyncedAt)
+	if notModified(r, etag, res.SyncedAt) {
+		rw.WriteHeader(http.StatusNotModified)
+		return
+	}
+
 	httpResponseWrite(rw, res, http.StatusOK)
 
 }
 
+// streamItems writes the listing straight from the database.
+func (h *Handler) streamItems(rw http.ResponseWriter, r *http.Request, lang string) {
+	rw.Header().Set("X-Cache", string(service.CacheBypass))
+	stream := &itemsStream{rw: rw, r: r, lang: lang}
+	err := h.service.StreamItems(r.Context(), lang, stream)
+	switch {
+	case errors.Is(err, errNotModified):
+		rw.WriteHeader(http.StatusNotModified)
+	case err != nil && !stream.started:
+		httpResponseWrite(rw, err.Error(), http.StatusInternalServerError)
+	case err != nil:
+		// the status is already sent, the truncated document tells the client
+		log.Printf("failed to stream items: %v", err)
+	default:
+		if err = stream.End(); err != nil {
+			log.Printf("failed to stream items: %v", err)
+		}
+	}
+}
+
+func (h *Handler) GetItem(rw http.ResponseWriter, r *http.Request) {
+	res, status, err := h.service.GetItem(r.Context(), r.PathValue("name"))
+	rw.Header().Set("X-Cache", string(status))
+	if errors.Is(err, model.ErrNotFound) {
+		httpResponseWrite(rw, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		httpResponseWrite(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	httpResponseWrite(rw, res, http.StatusOK)
+}
+
+func (h *Handler) GetFirmnesses(rw http.ResponseWriter, r *http.Request) {
+	res, status, err := h.service.GetFirmnesses(r.Context())
+	rw.Header().Set("X-Cache", string(status))
+	if err != nil {
+		httpResponseWrite(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	httpResponseWrite(rw, res, http.StatusOK)
+}
+
+func (h *Handler) GetFlavors(rw http.ResponseWriter, r *http.Request) {
+	res, status, err := h.service.GetFlavors(r.Context())
+	rw.Header().Set("X-Cache", string(status))
+	if err != nil {
+		httpResponseWrite(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	httpResponseWrite(rw, res, http.StatusOK)
+}
+
+func (h *Handler) GetPokemonList(rw http.ResponseWriter, r *http.Request) {
+	res, status, err := h.service.GetPokemonList(r.Context())
+	rw.Header().Set("X-Cache", string(status))
+	if err != nil {
+		httpResponseWrite(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	httpResponseWrite(rw, res, http.StatusOK)
+}
+
+func (h *Handler) GetPokemon(rw http.ResponseWriter, r *http.Request) {
+	res, status, err := h.service.GetPokemon(r.Context(), r.PathValue("name"))
+	rw.Header().Set("X-Cache", string(status))
+	if errors.Is(err, model.ErrNotFound) {
+		httpResponseWrite(rw, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		httpResponseWrite(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	httpResponseWrite(rw, res, http.StatusOK)
+}
+
+func (h *Handler) SyncItems(rw http.ResponseWriter, r *http.Request) {
+	if err := h.service.SyncItems(r.Context()); err != nil {
+		httpResponseWrite(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	httpResponseWrite(rw, "OK", http.StatusOK)
+}
+
+func (h *Handler) GetCatalogue(rw http.ResponseWriter, r *http.Request) {
+	filter := model.ItemFilter{
+		Category:  r.URL.Query().Get("category"),
+		Attribute: r.URL.Query().Get("attribute"),
+	}
+	res, status, err := h.service.GetCatalogue(r.Context(), filter)
+	rw.Header().Set("X-Cache", string(status))
+	if err != nil {
+		httpResponseWrite(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	httpResponseWrite(rw, res, http.StatusOK)
+}
+
+func (h *Handler) GetCatalogueItem(rw http.ResponseWriter, r *http.Request) {
+	res, status, err := h.service.GetCatalogueItem(r.Context(), r.PathValue("name"))
+	rw.Header().Set("X-Cache", string(status))
+	if errors.Is(err, model.ErrNotFound) {
+		httpResponseWrite(rw, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		httpResponseWrite(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	httpResponseWrite(rw, res, http.StatusOK)
+}
+
+func (h *Handler) SyncTypes(rw http.ResponseWriter, r *http.Request) {
+	if err := h.service.SyncTypes(r.Context()); err != nil {
+		httpResponseWrite(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	httpResponseWrite(rw, "OK", http.StatusOK)
+}
+
+// GetEffectiveness takes the defender as either ?pokemon=name or ?types=a,b.
+func (h *Handler) GetEffectiveness(rw http.ResponseWriter, r *http.Request) {
+	var query model.EffectivenessQuery
+	query.Pokemon = r.URL.Query().Get("pokemon")
+	if types := r.URL.Query().Get("types"); types != "" {
+		query.Types = strings.Split(types, ",")
+	}
+	// a repeated type would apply its multipliers twice
+	for i, t := range query.Types {
+		if slices.Contains(query.Types[:i], t) {
+			httpResponseWrite(rw, fmt.Sprintf("type %q is repeated", t), http.StatusBadRequest)
+			return
+		}
+	}
+	if (query.Pokemon == "") == (len(query.Types) == 0) {
+		httpResponseWrite(rw, "either pokemon or types is required", http.StatusBadRequest)
+		return
+	}
+
+	res, status, err := h.service.GetEffectiveness(r.Context(), query)
+	rw.Header().Set("X-Cache", string(status))
+	if errors.Is(err, model.ErrNotFound) {
+		httpResponseWrite(rw, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		httpResponseWrite(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	httpResponseWrite(rw, res, http.StatusOK)
+}
+
+func (h *Handler) SyncEvolutionChains(rw http.ResponseWriter, r *http.Request) {
+	if err := h.service.SyncEvolutionChains(r.Context()); err != nil {
+		httpResponseWrite(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	httpResponseWrite(rw, "OK", http.StatusOK)
+}
+
+func (h *Handler) GetEvolutionChain(rw http.ResponseWriter, r *http.Request) {
+	res, status, err := h.service.GetEvolutionChain(r.Context(), r.PathValue("species"))
+	rw.Header().Set("X-Cache", string(status))
+	if errors.Is(err, model.ErrNotFound) {
+		httpResponseWrite(rw, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		httpResponseWrite(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	httpResponseWrite(rw, res, http.StatusOK)
+}
+
+func (h *Handler) GetEvolutionLinks(rw http.ResponseWriter, r *http.Request) {
+	res, status, err := h.service.GetEvolutionLinks(r.Context(), r.PathValue("species"))
+	rw.Header().Set("X-Cache", string(status))
+	if errors.Is(err, model.ErrNotFound) {
+		httpResponseWrite(rw, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		httpResponseWrite(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	httpResponseWrite(rw, res, http.StatusOK)
+}
+
+func (h *Handler) SyncNatures(rw http.ResponseWriter, r *http.Request) {
+	if err := h.service.SyncNatures(r.Context()); err != nil {
+		httpResponseWrite(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	httpResponseWrite(rw, "OK", http.StatusOK)
+}
+
+// RecommendBerries takes the preference as either ?nature=name or
+// ?likes=flavor&dislikes=flavor, either flavor being optional.
+func (h *Handler) RecommendBerries(rw http.ResponseWriter, r *http.Request) {
+	query := model.RecommendationQuery{
+		Nature:   r.URL.Query().Get("nature"),
+		Likes:    r.URL.Query().Get("likes"),
+		Dislikes: r.URL.Query().Get("dislikes"),
+	}
+	flavors := query.Likes != "" || query.Dislikes != ""
+	if (query.Nature == "") == !flavors {
+		httpResponseWrite(rw, "either nature or likes and dislikes is required", http.StatusBadRequest)
+		return
+	}
+	if query.Likes != "" && query.Likes == query.Dislikes {
+		httpResponseWrite(rw, "likes and dislikes must differ", http.StatusBadRequest)
+		return
+	}
+
+	res, status, err := h.service.RecommendBerries(r.Context(), query)
+	rw.Header().Set("X-Cache", string(status))
+	if errors.Is(err, model.ErrNotFound) {
+		httpResponseWrite(rw, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		httpResponseWrite(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	httpResponseWrite(rw, res, http.StatusOK)
+}
+
+func (h *Handler) SyncBerryGrowth(rw http.ResponseWriter, r *http.Request) {
+	if err := h.service.SyncBerryGrowth(r.Context()); err != nil {
+		httpResponseWrite(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	httpResponseWrite(rw, "OK", http.StatusOK)
+}
+
+// PlanFarm takes ?berries=a,b planted in turn over ?plots= plots for ?hours= hours.
+func (h *Handler) PlanFarm(rw http.ResponseWriter, r *http.Request) {
+	var query model.PlanQuery
+	if berries := r.URL.Query().Get("berries"); berries != "" {
+		query.Berries = strings.Split(berries, ",")
+	}
+	if len(query.Berries) == 0 {
+		httpResponseWrite(rw, "berries is required", http.StatusBadRequest)
+		return
+	}
+
+	var err error
+	query.Plots, err = strconv.Atoi(r.URL.Query().Get("plots"))
+	if err != nil || query.Plots < 1 || query.Plots > service.MaxPlanPlots {
+		httpResponseWrite(rw, fmt.Sprintf("plots must be between 1 and %d", service.MaxPlanPlots), http.StatusBadRequest)
+		return
+	}
+	if len(query.Berries) > query.Plots {
+		httpResponseWrite(rw, "every berry needs a plot", http.StatusBadRequest)
+		return
+	}
+	query.Hours, err = strconv.Atoi(r.URL.Query().Get("hours"))
+	if err != nil || query.Hours < 1 || query.Hours > service.MaxPlanHours {
+		httpResponseWrite(rw, fmt.Sprintf("hours must be between 1 and %d", service.MaxPlanHours), http.StatusBadRequest)
+		return
+	}
+
+	res, status, err := h.service.PlanFarm(r.Context(), query)
+	rw.Header().Set("X-Cache", string(status))
+	if errors.Is(err, model.ErrNotFound) {
+		httpResponseWrite(rw, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		httpResponseWrite(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	httpResponseWrite(rw, res, http.StatusOK)
+}
+
+// Search takes the query as ?q= and an optional ?limit= of results.
+func (h *Handler) Search(rw http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		httpResponseWrite(rw, "q is required", http.StatusBadRequest)
+		return
+	}
+
+	limit := service.DefaultSearchLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 || parsed > service.MaxSearchLimit {
+			httpResponseWrite(rw, fmt.Sprintf("limit must be between 1 and %d", service.MaxSearchLimit), http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	res, status, err := h.service.Search(r.Context(), query, limit)
+	rw.Header().Set("X-Cache", string(status))
+	if err != nil {
+		httpResponseWrite(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	httpResponseWrite(rw, res, http.StatusOK)
+}
+
+// GetAsset serves a mirrored asset, range and conditional requests included.
+func (h *Handler) GetAsset(rw http.ResponseWriter, r *http.Request) {
+	hash := r.PathValue("hash")
+	asset, blob, err := h.service.GetAsset(r.Context(), hash)
+	if errors.Is(err, model.ErrNotFound) {
+		httpResponseWrite(rw, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		httpResponseWrite(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer func() {
+		_ = blob.Close()
+	}()
+
+	rw.Header().Set("Content-Type", asset.ContentType)
+	rw.Header().Set("X-Content-Type-Options", "nosniff")
+	setAssetCacheHeaders(rw, hash)
+	http.ServeContent(rw, r, "", time.Time{}, blob)
+}
+
 // httpResponseWrite is a helper function to write JSON responses with the given data and status code.
 func httpResponseWrite(rw http.ResponseWriter, data interface{}, statusCode int) {
 	rw.Header().Set("Content-type", "application/json")
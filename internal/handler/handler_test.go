@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_Handler_GetEffectiveness_BadRequest(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		wantBody string
+	}{
+		{
+			name:     "given neither pokemon nor types should return bad request",
+			query:    "",
+			wantBody: `"either pokemon or types is required"`,
+		},
+		{
+			name:     "given both pokemon and types should return bad request",
+			query:    "?pokemon=charmander&types=fire",
+			wantBody: `"either pokemon or types is required"`,
+		},
+		{
+			name:     "given a repeated type should return bad request",
+			query:    "?types=fire,fire",
+			wantBody: `"type \"fire\" is repeated"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the query is rejected before the service is called
+			h := NewHandler(nil)
+			rw := httptest.NewRecorder()
+			h.GetEffectiveness(rw, httptest.NewRequest(http.MethodGet, "/effectiveness"+tt.query, nil))
+
+			assert.Equal(t, http.StatusBadRequest, rw.Code)
+			assert.JSONEq(t, tt.wantBody, rw.Body.String())
+		})
+	}
+}
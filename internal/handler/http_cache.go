@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"github.com/inasknh/simple-poke-app/internal/model"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// itemsMaxAge caps how long clients may reuse a listing without revalidating.
+const itemsMaxAge = 5 * time.Minute
+
+// assetMaxAge is how long clients may reuse an asset, which never changes
+// as it is addressed by its content.
+const assetMaxAge = 365 * 24 * time.Hour
+
+// itemsETag returns a strong validator for the listing in lang: the sync run
+// it was read after, or a hash of its content before the first sync.
+func itemsETag(res *model.BerriesResponse, lang string) string {
+	if res.SyncID > 0 {
+		return syncETag(res.SyncID, lang)
+	}
+
+	h := sha256.New()
+	_ = json.NewEncoder(h).Encode(res.Berries)
+	return fmt.Sprintf(`"%x"`, h.Sum(nil)[:16])
+}
+
+func syncETag(id int64, lang string) string {
+	return fmt.Sprintf(`"sync-%d-%s"`, id, lang)
+}
+
+// setCacheHeaders emits the validators and a freshness lifetime of a tenth
+// of the time since the last sync, capped at itemsMaxAge.
+func setCacheHeaders(rw http.ResponseWriter, etag string, lastModified *time.Time) {
+	header := rw.Header()
+	header.Set("ETag", etag)
+
+	var maxAge time.Duration
+	if lastModified != nil {
+		header.Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+		maxAge = max(min(time.Since(*lastModified)/10, itemsMaxAge), 0)
+	}
+	header.Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(maxAge.Seconds())))
+}
+
+// notModified reports whether the client's cached copy is still current.
+// If-Modified-Since is only considered without If-None-Match.
+func notModified(r *http.Request, etag string, lastModified *time.Time) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, candidate := range strings.Split(inm, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if lastModified == nil {
+		return false
+	}
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+
+	return !lastModified.Truncate(time.Second).After(since)
+}
+
+// setAssetCacheHeaders lets clients and proxies keep an asset for assetMaxAge
+// without ever revalidating it.
+func setAssetCacheHeaders(rw http.ResponseWriter, hash string) {
+	header := rw.Header()
+	header.Set("ETag", fmt.Sprintf(`"%s"`, hash))
+	header.Set("Cache-Control", fmt.Sprintf("public, max-age=%d, immutable", int(assetMaxAge.Seconds())))
+}
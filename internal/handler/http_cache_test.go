@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"github.com/inasknh/simple-poke-app/internal/model"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func Test_itemsETag(t *testing.T) {
+	berries := []model.Berry{{Name: "cheri", URL: "https://pokeapi.co/api/v2/berry/1/"}}
+
+	tests := []struct {
+		name string
+		res  *model.BerriesResponse
+		lang string
+		want string
+	}{
+		{
+			name: "given a synced listing should return the sync run and language",
+			res:  &model.BerriesResponse{Berries: berries, SyncID: 7},
+			lang: "fr",
+			want: `"sync-7-fr"`,
+		},
+		{
+			name: "given a listing before the first sync should return a hash of its content only",
+			res:  &model.BerriesResponse{Berries: berries},
+			lang: "en",
+			want: itemsETag(&model.BerriesResponse{Berries: berries}, "fr"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := itemsETag(tt.res, tt.lang)
+			assert.Equal(t, tt.want, got)
+			assert.Regexp(t, `^"[^"]+"$`, got)
+		})
+	}
+
+	t.Run("given different content should return different hashes", func(t *testing.T) {
+		other := []model.Berry{{Name: "chesto", URL: "https://pokeapi.co/api/v2/berry/2/"}}
+		assert.NotEqual(t,
+			itemsETag(&model.BerriesResponse{Berries: berries}, "en"),
+			itemsETag(&model.BerriesResponse{Berries: other}, "en"))
+	})
+}
+
+func Test_setCacheHeaders(t *testing.T) {
+	tests := []struct {
+		name             string
+		lastModified     *time.Time
+		wantCacheControl string
+		wantLastModified string
+	}{
+		{
+			name:             "given no sync should not let clients reuse the listing",
+			lastModified:     nil,
+			wantCacheControl: "public, max-age=0",
+		},
+		{
+			name:             "given a recent sync should allow a tenth of the time since it",
+			lastModified:     timePtr(time.Now().Add(-100 * time.Second)),
+			wantCacheControl: "public, max-age=10",
+		},
+		{
+			name:             "given an old sync should cap the lifetime",
+			lastModified:     timePtr(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)),
+			wantCacheControl: "public, max-age=300",
+			wantLastModified: "Tue, 02 Jan 2024 03:04:05 GMT",
+		},
+		{
+			name:             "given a sync in the future should not return a negative lifetime",
+			lastModified:     timePtr(time.Now().Add(time.Hour)),
+			wantCacheControl: "public, max-age=0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rw := httptest.NewRecorder()
+			setCacheHeaders(rw, `"sync-1-en"`, tt.lastModified)
+
+			assert.Equal(t, `"sync-1-en"`, rw.Header().Get("ETag"))
+			assert.Equal(t, tt.wantCacheControl, rw.Header().Get("Cache-Control"))
+			if tt.lastModified == nil {
+				assert.Empty(t, rw.Header().Get("Last-Modified"))
+			} else if tt.wantLastModified != "" {
+				assert.Equal(t, tt.wantLastModified, rw.Header().Get("Last-Modified"))
+			}
+		})
+	}
+}
+
+func Test_notModified(t *testing.T) {
+	lastModified := time.Date(2024, 1, 2, 3, 4, 5, 600, time.UTC)
+
+	tests := []struct {
+		name         string
+		headers      map[string]string
+		lastModified *time.Time
+		want         bool
+	}{
+		{
+			name:         "given no validators should return false",
+			lastModified: &lastModified,
+			want:         false,
+		},
+		{
+			name:    "given the current etag should return true",
+			headers: map[string]string{"If-None-Match": `"sync-1-en"`},
+			want:    true,
+		},
+		{
+			name:    "given the current etag among others should return true",
+			headers: map[string]string{"If-None-Match": `"sync-0-en", "sync-1-en"`},
+			want:    true,
+		},
+		{
+			name:    "given the weak form of the current etag should return true",
+			headers: map[string]string{"If-None-Match": `W/"sync-1-en"`},
+			want:    true,
+		},
+		{
+			name:    "given a wildcard should return true",
+			headers: map[string]string{"If-None-Match": "*"},
+			want:    true,
+		},
+		{
+			name:    "given another etag should return false",
+			headers: map[string]string{"If-None-Match": `"sync-1-fr"`},
+			want:    false,
+		},
+		{
+			name: "given a stale etag should ignore a current If-Modified-Since",
+			headers: map[string]string{
+				"If-None-Match":     `"sync-0-en"`,
+				"If-Modified-Since": lastModified.Format(http.TimeFormat),
+			},
+			lastModified: &lastModified,
+			want:         false,
+		},
+		{
+			name:         "given If-Modified-Since at the last sync should return true",
+			headers:      map[string]string{"If-Modified-Since": lastModified.Format(http.TimeFormat)},
+			lastModified: &lastModified,
+			want:         true,
+		},
+		{
+			name:         "given If-Modified-Since before the last sync should return false",
+			headers:      map[string]string{"If-Modified-Since": lastModified.Add(-time.Second).Format(http.TimeFormat)},
+			lastModified: &lastModified,
+			want:         false,
+		},
+		{
+			name:         "given an invalid If-Modified-Since should return false",
+			headers:      map[string]string{"If-Modified-Since": "yesterday"},
+			lastModified: &lastModified,
+			want:         false,
+		},
+		{
+			name:    "given If-Modified-Since without a sync should return false",
+			headers: map[string]string{"If-Modified-Since": lastModified.Format(http.TimeFormat)},
+			want:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/items", nil)
+			for key, value := range tt.headers {
+				r.Header.Set(key, value)
+			}
+
+			assert.Equal(t, tt.want, notModified(r, `"sync-1-en"`, tt.lastModified))
+		})
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"cmp"
+	"github.com/inasknh/simple-poke-app/internal/model"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// language picks the listing language from the lang query parameter, then
+// Accept-Language, falling back to model.DefaultLanguage.
+func language(r *http.Request) string {
+	if lang, ok := matchLanguage(r.URL.Query().Get("lang")); ok {
+		return lang
+	}
+
+	type preference struct {
+		tag string
+		q   float64
+	}
+	var preferences []preference
+	for _, part := range strings.Split(r.Header.Get("Accept-Language"), ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if tag != "" && q > 0 {
+			preferences = append(preferences, preference{tag: tag, q: q})
+		}
+	}
+	slices.SortStableFunc(preferences, func(a, b preference) int {
+		return cmp.Compare(b.q, a.q)
+	})
+
+	for _, p := range preferences {
+		if lang, ok := matchLanguage(p.tag); ok {
+			return lang
+		}
+	}
+
+	return model.DefaultLanguage
+}
+
+// matchLanguage maps a language tag to a PokeAPI language, trying the tag
+// itself before its primary subtag, e.g. fr-CH matches fr.
+func matchLanguage(tag string) (string, bool) {
+	if tag == "" {
+		return "", false
+	}
+
+	base, _, _ := strings.Cut(tag, "-")
+	for _, candidate := range []string{tag, base} {
+		for _, lang := range model.Languages {
+			if strings.EqualFold(lang, candidate) {
+				return lang, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// setLanguageHeaders tells clients and shared caches the listing depends on
+// the requested language.
+func setLanguageHeaders(rw http.ResponseWriter, lang string) {
+	rw.Header().Add("Vary", "Accept-Language")
+	rw.Header().Set("Content-Language", lang)
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"github.com/inasknh/simple-poke-app/internal/model"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_language(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		acceptLanguage string
+		want           string
+	}{
+		{
+			name: "given no preference should return the default language",
+			want: model.DefaultLanguage,
+		},
+		{
+			name:           "given the lang parameter should prefer it over Accept-Language",
+			query:          "?lang=de",
+			acceptLanguage: "fr",
+			want:           "de",
+		},
+		{
+			name:           "given an unknown lang parameter should fall back to Accept-Language",
+			query:          "?lang=xx",
+			acceptLanguage: "fr",
+			want:           "fr",
+		},
+		{
+			name:           "given q-values should pick the most preferred language",
+			acceptLanguage: "de;q=0.5, fr;q=0.9, ja;q=0.7",
+			want:           "fr",
+		},
+		{
+			name:           "given equal q-values should keep the header order",
+			acceptLanguage: "ja, fr",
+			want:           "ja",
+		},
+		{
+			name:           "given a region should fall back to its language",
+			acceptLanguage: "fr-CA",
+			want:           "fr",
+		},
+		{
+			name:           "given a supported region should match it exactly in any case",
+			acceptLanguage: "PT-br",
+			want:           "pt-BR",
+		},
+		{
+			name:           "given an unsupported preferred language should try the next one",
+			acceptLanguage: "nl, it;q=0.8",
+			want:           "it",
+		},
+		{
+			name:           "given a language refused with q=0 should not pick it",
+			acceptLanguage: "fr;q=0, de;q=0.1",
+			want:           "de",
+		},
+		{
+			name:           "given an invalid q-value should ignore the language",
+			acceptLanguage: "fr;q=high, es;q=0.2",
+			want:           "es",
+		},
+		{
+			name:           "given only unsupported languages should return the default language",
+			acceptLanguage: "nl-BE, *",
+			want:           model.DefaultLanguage,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/items"+tt.query, nil)
+			if tt.acceptLanguage != "" {
+				r.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+
+			assert.Equal(t, tt.want, language(r))
+		})
+	}
+}
+
+func Test_setLanguageHeaders(t *testing.T) {
+	rw := httptest.NewRecorder()
+	setLanguageHeaders(rw, "fr")
+
+	assert.Equal(t, "Accept-Language", rw.Header().Get("Vary"))
+	assert.Equal(t, "fr", rw.Header().Get("Content-Language"))
+}
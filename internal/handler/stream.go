@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/inasknh/simple-poke-app/internal/model"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// errNotModified stops a stream when the client's copy is current.
+var errNotModified = errors.New("not modified")
+
+// itemsStream encodes a listing as JSON while it is read from the database,
+// producing the same document as an encoded model.BerriesResponse.
+type itemsStream struct {
+	rw      http.ResponseWriter
+	r       *http.Request
+	lang    string
+	run     *model.SyncRun
+	started bool
+	rows    int
+}
+
+func (s *itemsStream) Begin(run *model.SyncRun) error {
+	s.run = run
+	if run != nil {
+		etag := syncETag(run.ID, s.lang)
+		setCacheHeaders(s.rw, etag, &run.FinishedAt)
+		if notModified(s.r, etag, &run.FinishedAt) {
+			return errNotModified
+		}
+	}
+
+	s.rw.Header().Set("Content-type", "application/json")
+	s.rw.WriteHeader(http.StatusOK)
+	s.started = true
+
+	_, err := io.WriteString(s.rw, `{"berries":[`)
+	return err
+}
+
+func (s *itemsStream) Write(berry model.Berry) error {
+	if s.rows > 0 {
+		if _, err := io.WriteString(s.rw, ","); err != nil {
+			return err
+		}
+	}
+	s.rows++
+
+	data, err := json.Marshal(berry)
+	if err != nil {
+		return err
+	}
+	_, err = s.rw.Write(data)
+	return err
+}
+
+// End closes the document, appending the listing's version.
+func (s *itemsStream) End() error {
+	tail := "]"
+	if s.run != nil {
+		syncedAt, err := json.Marshal(s.run.FinishedAt)
+		if err != nil {
+			return err
+		}
+		tail += `,"sync_id":` + strconv.FormatInt(s.run.ID, 10) + `,"synced_at":` + string(syncedAt)
+	}
+
+	_, err := io.WriteString(s.rw, tail+"}\n")
+	return err
+}
+
+// bypassCache reports whether the client asked for a listing not served from the cache.
+func bypassCache(r *http.Request) bool {
+	for _, directive := range strings.Split(r.Header.Get("Cache-Control"), ",") {
+		if strings.EqualFold(strings.TrimSpace(directive), "no-cache") {
+			return true
+		}
+	}
+
+	return strings.EqualFold(r.Header.Get("Pragma"), "no-cache")
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/inasknh/simple-poke-app/internal/model"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func Test_itemsStream(t *testing.T) {
+	syncedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	berries := []model.Berry{
+		{Name: "cheri", URL: "https://pokeapi.co/api/v2/berry/1/", DisplayName: "Baie Ceriz", Description: "Soigne la paralysie."},
+		{Name: "chesto", URL: "https://pokeapi.co/api/v2/berry/2/", Sprite: "/assets/0a1b"},
+	}
+
+	tests := []struct {
+		name    string
+		run     *model.SyncRun
+		berries []model.Berry
+		want    model.BerriesResponse
+	}{
+		{
+			name:    "given a synced listing should match the cached document",
+			run:     &model.SyncRun{ID: 3, FinishedAt: syncedAt},
+			berries: berries,
+			want:    model.BerriesResponse{Berries: berries, SyncID: 3, SyncedAt: &syncedAt},
+		},
+		{
+			name:    "given no sync should match the cached document without a version",
+			run:     nil,
+			berries: berries,
+			want:    model.BerriesResponse{Berries: berries},
+		},
+		{
+			name:    "given no berries should return an empty listing",
+			run:     &model.SyncRun{ID: 3, FinishedAt: syncedAt},
+			berries: nil,
+			want:    model.BerriesResponse{Berries: []model.Berry{}, SyncID: 3, SyncedAt: &syncedAt},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rw := httptest.NewRecorder()
+			stream := &itemsStream{rw: rw, r: httptest.NewRequest(http.MethodGet, "/items", nil), lang: "fr"}
+
+			assert.NoError(t, stream.Begin(tt.run))
+			for _, berry := range tt.berries {
+				assert.NoError(t, stream.Write(berry))
+			}
+			assert.NoError(t, stream.End())
+
+			assert.True(t, json.Valid(rw.Body.Bytes()))
+
+			// byte for byte the document served from the cache
+			var want bytes.Buffer
+			assert.NoError(t, json.NewEncoder(&want).Encode(tt.want))
+			assert.Equal(t, want.String(), rw.Body.String())
+		})
+	}
+}
+
+func Test_itemsStream_NotModified(t *testing.T) {
+	run := &model.SyncRun{ID: 3, FinishedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	r := httptest.NewRequest(http.MethodGet, "/items", nil)
+	r.Header.Set("If-None-Match", `"sync-3-fr"`)
+	rw := httptest.NewRecorder()
+	stream := &itemsStream{rw: rw, r: r, lang: "fr"}
+
+	assert.ErrorIs(t, stream.Begin(run), errNotModified)
+	assert.False(t, stream.started)
+	assert.Equal(t, `"sync-3-fr"`, rw.Header().Get("ETag"))
+	assert.Empty(t, rw.Body.String())
+}
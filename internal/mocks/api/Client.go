@@ -15,29 +15,47 @@ type Client struct {
 	mock.Mock
 }
 
-// GetBerries provides a mock function with given fields: ctx, request
-func (_m *Client) GetBerries(ctx context.Context, request api.BerriesRequest) (*api.BerriesResponse, error) {
-	ret := _m.Called(ctx, request)
+// GetResource provides a mock function with given fields: ctx, endpoint, idOrName, out
+func (_m *Client) GetResource(ctx context.Context, endpoint string, idOrName string, out interface{}) error {
+	ret := _m.Called(ctx, endpoint, idOrName, out)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetBerries")
+		panic("no return value specified for GetResource")
 	}
 
-	var r0 *api.BerriesResponse
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, interface{}) error); ok {
+		r0 = rf(ctx, endpoint, idOrName, out)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ListResources provides a mock function with given fields: ctx, endpoint, request
+func (_m *Client) ListResources(ctx context.Context, endpoint string, request api.ListRequest) (*api.NamedAPIResourceList, error) {
+	ret := _m.Called(ctx, endpoint, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListResources")
+	}
+
+	var r0 *api.NamedAPIResourceList
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, api.BerriesRequest) (*api.BerriesResponse, error)); ok {
-		return rf(ctx, request)
+	if rf, ok := ret.Get(0).(func(context.Context, string, api.ListRequest) (*api.NamedAPIResourceList, error)); ok {
+		return rf(ctx, endpoint, request)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, api.BerriesRequest) *api.BerriesResponse); ok {
-		r0 = rf(ctx, request)
+	if rf, ok := ret.Get(0).(func(context.Context, string, api.ListRequest) *api.NamedAPIResourceList); ok {
+		r0 = rf(ctx, endpoint, request)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*api.BerriesResponse)
+			r0 = ret.Get(0).(*api.NamedAPIResourceList)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, api.BerriesRequest) error); ok {
-		r1 = rf(ctx, request)
+	if rf, ok := ret.Get(1).(func(context.Context, string, api.ListRequest) error); ok {
+		r1 = rf(ctx, endpoint, request)
 	} else {
 		r1 = ret.Error(1)
 	}
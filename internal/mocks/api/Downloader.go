@@ -0,0 +1,60 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	api "github.com/inasknh/simple-poke-app/internal/api"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Downloader is an autogenerated mock type for the Downloader type
+type Downloader struct {
+	mock.Mock
+}
+
+// Download provides a mock function with given fields: ctx, url
+func (_m *Downloader) Download(ctx context.Context, url string) (*api.Download, error) {
+	ret := _m.Called(ctx, url)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Download")
+	}
+
+	var r0 *api.Download
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*api.Download, error)); ok {
+		return rf(ctx, url)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *api.Download); ok {
+		r0 = rf(ctx, url)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*api.Download)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, url)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewDownloader creates a new instance of Downloader. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDownloader(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Downloader {
+	mock := &Downloader{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
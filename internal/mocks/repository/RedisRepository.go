@@ -7,6 +7,8 @@ import (
 
 	model "github.com/inasknh/simple-poke-app/internal/model"
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // RedisRepository is an autogenerated mock type for the RedisRepository type
@@ -14,24 +16,163 @@ type RedisRepository struct {
 	mock.Mock
 }
 
-// GetData provides a mock function with given fields: ctx
-func (_m *RedisRepository) GetData(ctx context.Context) (*model.BerriesResponse, error) {
+// AcquireLock provides a mock function with given fields: ctx, name, ttl
+func (_m *RedisRepository) AcquireLock(ctx context.Context, name string, ttl time.Duration) (string, error) {
+	ret := _m.Called(ctx, name, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AcquireLock")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) (string, error)); ok {
+		return rf(ctx, name, ttl)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) string); ok {
+		r0 = rf(ctx, name, ttl)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Duration) error); ok {
+		r1 = rf(ctx, name, ttl)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteData provides a mock function with given fields: ctx
+func (_m *RedisRepository) DeleteData(ctx context.Context) error {
 	ret := _m.Called(ctx)
 
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteData")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteKey provides a mock function with given fields: ctx, key
+func (_m *RedisRepository) DeleteKey(ctx context.Context, key string) (bool, error) {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteKey")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return rf(ctx, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, key)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetData provides a mock function with given fields: ctx, lang
+func (_m *RedisRepository) GetData(ctx context.Context, lang string) (*model.BerriesResponse, bool, error) {
+	ret := _m.Called(ctx, lang)
+
 	if len(ret) == 0 {
 		panic("no return value specified for GetData")
 	}
 
 	var r0 *model.BerriesResponse
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.BerriesResponse, bool, error)); ok {
+		return rf(ctx, lang)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.BerriesResponse); ok {
+		r0 = rf(ctx, lang)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.BerriesResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) bool); ok {
+		r1 = rf(ctx, lang)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string) error); ok {
+		r2 = rf(ctx, lang)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetValue provides a mock function with given fields: ctx, key, out
+func (_m *RedisRepository) GetValue(ctx context.Context, key string, out interface{}) (bool, error) {
+	ret := _m.Called(ctx, key, out)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetValue")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, interface{}) (bool, error)); ok {
+		return rf(ctx, key, out)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, interface{}) bool); ok {
+		r0 = rf(ctx, key, out)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, interface{}) error); ok {
+		r1 = rf(ctx, key, out)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListKeys provides a mock function with given fields: ctx
+func (_m *RedisRepository) ListKeys(ctx context.Context) ([]model.CacheKey, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListKeys")
+	}
+
+	var r0 []model.CacheKey
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) (*model.BerriesResponse, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context) ([]model.CacheKey, error)); ok {
 		return rf(ctx)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) *model.BerriesResponse); ok {
+	if rf, ok := ret.Get(0).(func(context.Context) []model.CacheKey); ok {
 		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*model.BerriesResponse)
+			r0 = ret.Get(0).([]model.CacheKey)
 		}
 	}
 
@@ -44,17 +185,81 @@ func (_m *RedisRepository) GetData(ctx context.Context) (*model.BerriesResponse,
 	return r0, r1
 }
 
-// SetData provides a mock function with given fields: ctx, response
-func (_m *RedisRepository) SetData(ctx context.Context, response *model.BerriesResponse) error {
-	ret := _m.Called(ctx, response)
+// Purge provides a mock function with given fields: ctx
+func (_m *RedisRepository) Purge(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Purge")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReleaseLock provides a mock function with given fields: ctx, name, token
+func (_m *RedisRepository) ReleaseLock(ctx context.Context, name string, token string) error {
+	ret := _m.Called(ctx, name, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseLock")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, name, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetData provides a mock function with given fields: ctx, lang, response
+func (_m *RedisRepository) SetData(ctx context.Context, lang string, response *model.BerriesResponse) error {
+	ret := _m.Called(ctx, lang, response)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SetData")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *model.BerriesResponse) error); ok {
-		r0 = rf(ctx, response)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *model.BerriesResponse) error); ok {
+		r0 = rf(ctx, lang, response)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetValue provides a mock function with given fields: ctx, key, value
+func (_m *RedisRepository) SetValue(ctx context.Context, key string, value interface{}) error {
+	ret := _m.Called(ctx, key, value)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetValue")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, interface{}) error); ok {
+		r0 = rf(ctx, key, value)
 	} else {
 		r0 = ret.Error(0)
 	}
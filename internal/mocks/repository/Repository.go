@@ -14,6 +14,24 @@ type Repository struct {
 	mock.Mock
 }
 
+// CreateAsset provides a mock function with given fields: ctx, asset
+func (_m *Repository) CreateAsset(ctx context.Context, asset model.Asset) error {
+	ret := _m.Called(ctx, asset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateAsset")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Asset) error); ok {
+		r0 = rf(ctx, asset)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CreateBerry provides a mock function with given fields: ctx, berries
 func (_m *Repository) CreateBerry(ctx context.Context, berries []model.Berry) error {
 	ret := _m.Called(ctx, berries)
@@ -32,24 +50,360 @@ func (_m *Repository) CreateBerry(ctx context.Context, berries []model.Berry) er
 	return r0
 }
 
-// FetchBerries provides a mock function with given fields: ctx
-func (_m *Repository) FetchBerries(ctx context.Context) (*model.BerriesResponse, error) {
+// CreateEvolutionChain provides a mock function with given fields: ctx, chain
+func (_m *Repository) CreateEvolutionChain(ctx context.Context, chain model.EvolutionChain) error {
+	ret := _m.Called(ctx, chain)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateEvolutionChain")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.EvolutionChain) error); ok {
+		r0 = rf(ctx, chain)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateFirmness provides a mock function with given fields: ctx, firmness
+func (_m *Repository) CreateFirmness(ctx context.Context, firmness model.Firmness) error {
+	ret := _m.Called(ctx, firmness)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateFirmness")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Firmness) error); ok {
+		r0 = rf(ctx, firmness)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateFlavor provides a mock function with given fields: ctx, flavor
+func (_m *Repository) CreateFlavor(ctx context.Context, flavor model.Flavor) error {
+	ret := _m.Called(ctx, flavor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateFlavor")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Flavor) error); ok {
+		r0 = rf(ctx, flavor)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateItem provides a mock function with given fields: ctx, item
+func (_m *Repository) CreateItem(ctx context.Context, item model.Item) error {
+	ret := _m.Called(ctx, item)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateItem")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Item) error); ok {
+		r0 = rf(ctx, item)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateNature provides a mock function with given fields: ctx, nature
+func (_m *Repository) CreateNature(ctx context.Context, nature model.Nature) error {
+	ret := _m.Called(ctx, nature)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateNature")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Nature) error); ok {
+		r0 = rf(ctx, nature)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreatePokemon provides a mock function with given fields: ctx, pokemon
+func (_m *Repository) CreatePokemon(ctx context.Context, pokemon model.Pokemon) error {
+	ret := _m.Called(ctx, pokemon)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreatePokemon")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Pokemon) error); ok {
+		r0 = rf(ctx, pokemon)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateSyncRun provides a mock function with given fields: ctx
+func (_m *Repository) CreateSyncRun(ctx context.Context) (*model.SyncRun, error) {
 	ret := _m.Called(ctx)
 
+	if len(ret) == 0 {
+		panic("no return value specified for CreateSyncRun")
+	}
+
+	var r0 *model.SyncRun
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*model.SyncRun, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *model.SyncRun); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.SyncRun)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateType provides a mock function with given fields: ctx, t
+func (_m *Repository) CreateType(ctx context.Context, t model.Type) error {
+	ret := _m.Called(ctx, t)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateType")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Type) error); ok {
+		r0 = rf(ctx, t)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FetchAsset provides a mock function with given fields: ctx, hash
+func (_m *Repository) FetchAsset(ctx context.Context, hash string) (*model.Asset, error) {
+	ret := _m.Called(ctx, hash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchAsset")
+	}
+
+	var r0 *model.Asset
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.Asset, error)); ok {
+		return rf(ctx, hash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.Asset); ok {
+		r0 = rf(ctx, hash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Asset)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, hash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchAssetByURL provides a mock function with given fields: ctx, url
+func (_m *Repository) FetchAssetByURL(ctx context.Context, url string) (*model.Asset, error) {
+	ret := _m.Called(ctx, url)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchAssetByURL")
+	}
+
+	var r0 *model.Asset
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.Asset, error)); ok {
+		return rf(ctx, url)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.Asset); ok {
+		r0 = rf(ctx, url)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Asset)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, url)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchBerries provides a mock function with given fields: ctx, lang
+func (_m *Repository) FetchBerries(ctx context.Context, lang string) (*model.BerriesResponse, error) {
+	ret := _m.Called(ctx, lang)
+
 	if len(ret) == 0 {
 		panic("no return value specified for FetchBerries")
 	}
 
 	var r0 *model.BerriesResponse
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) (*model.BerriesResponse, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.BerriesResponse, error)); ok {
+		return rf(ctx, lang)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.BerriesResponse); ok {
+		r0 = rf(ctx, lang)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.BerriesResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, lang)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchBerry provides a mock function with given fields: ctx, name
+func (_m *Repository) FetchBerry(ctx context.Context, name string) (*model.Berry, error) {
+	ret := _m.Called(ctx, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchBerry")
+	}
+
+	var r0 *model.Berry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.Berry, error)); ok {
+		return rf(ctx, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.Berry); ok {
+		r0 = rf(ctx, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Berry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchBerryGrowths provides a mock function with given fields: ctx
+func (_m *Repository) FetchBerryGrowths(ctx context.Context) (*model.BerryGrowths, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchBerryGrowths")
+	}
+
+	var r0 *model.BerryGrowths
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*model.BerryGrowths, error)); ok {
 		return rf(ctx)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) *model.BerriesResponse); ok {
+	if rf, ok := ret.Get(0).(func(context.Context) *model.BerryGrowths); ok {
 		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*model.BerriesResponse)
+			r0 = ret.Get(0).(*model.BerryGrowths)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchEvolutionChain provides a mock function with given fields: ctx, species
+func (_m *Repository) FetchEvolutionChain(ctx context.Context, species string) (*model.EvolutionChain, error) {
+	ret := _m.Called(ctx, species)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchEvolutionChain")
+	}
+
+	var r0 *model.EvolutionChain
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.EvolutionChain, error)); ok {
+		return rf(ctx, species)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.EvolutionChain); ok {
+		r0 = rf(ctx, species)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.EvolutionChain)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, species)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchFirmnesses provides a mock function with given fields: ctx
+func (_m *Repository) FetchFirmnesses(ctx context.Context) (*model.FirmnessesResponse, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchFirmnesses")
+	}
+
+	var r0 *model.FirmnessesResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*model.FirmnessesResponse, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *model.FirmnessesResponse); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.FirmnessesResponse)
 		}
 	}
 
@@ -62,6 +416,378 @@ func (_m *Repository) FetchBerries(ctx context.Context) (*model.BerriesResponse,
 	return r0, r1
 }
 
+// FetchFlavorProfiles provides a mock function with given fields: ctx
+func (_m *Repository) FetchFlavorProfiles(ctx context.Context) (*model.FlavorProfiles, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchFlavorProfiles")
+	}
+
+	var r0 *model.FlavorProfiles
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*model.FlavorProfiles, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *model.FlavorProfiles); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.FlavorProfiles)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchFlavors provides a mock function with given fields: ctx
+func (_m *Repository) FetchFlavors(ctx context.Context) (*model.FlavorsResponse, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchFlavors")
+	}
+
+	var r0 *model.FlavorsResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*model.FlavorsResponse, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *model.FlavorsResponse); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.FlavorsResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchItem provides a mock function with given fields: ctx, name
+func (_m *Repository) FetchItem(ctx context.Context, name string) (*model.Item, error) {
+	ret := _m.Called(ctx, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchItem")
+	}
+
+	var r0 *model.Item
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.Item, error)); ok {
+		return rf(ctx, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.Item); ok {
+		r0 = rf(ctx, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Item)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchItemCatalogue provides a mock function with given fields: ctx
+func (_m *Repository) FetchItemCatalogue(ctx context.Context) (*model.ItemCatalogueResponse, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchItemCatalogue")
+	}
+
+	var r0 *model.ItemCatalogueResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*model.ItemCatalogueResponse, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *model.ItemCatalogueResponse); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.ItemCatalogueResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchLastSyncRun provides a mock function with given fields: ctx
+func (_m *Repository) FetchLastSyncRun(ctx context.Context) (*model.SyncRun, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchLastSyncRun")
+	}
+
+	var r0 *model.SyncRun
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*model.SyncRun, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *model.SyncRun); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.SyncRun)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchNature provides a mock function with given fields: ctx, name
+func (_m *Repository) FetchNature(ctx context.Context, name string) (*model.Nature, error) {
+	ret := _m.Called(ctx, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchNature")
+	}
+
+	var r0 *model.Nature
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.Nature, error)); ok {
+		return rf(ctx, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.Nature); ok {
+		r0 = rf(ctx, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Nature)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchPokemon provides a mock function with given fields: ctx, name
+func (_m *Repository) FetchPokemon(ctx context.Context, name string) (*model.Pokemon, error) {
+	ret := _m.Called(ctx, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchPokemon")
+	}
+
+	var r0 *model.Pokemon
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.Pokemon, error)); ok {
+		return rf(ctx, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.Pokemon); ok {
+		r0 = rf(ctx, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Pokemon)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchPokemonList provides a mock function with given fields: ctx
+func (_m *Repository) FetchPokemonList(ctx context.Context) (*model.PokemonListResponse, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchPokemonList")
+	}
+
+	var r0 *model.PokemonListResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*model.PokemonListResponse, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *model.PokemonListResponse); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.PokemonListResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchSearchDocuments provides a mock function with given fields: ctx
+func (_m *Repository) FetchSearchDocuments(ctx context.Context) (*model.SearchDocuments, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchSearchDocuments")
+	}
+
+	var r0 *model.SearchDocuments
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*model.SearchDocuments, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *model.SearchDocuments); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.SearchDocuments)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchTypeChart provides a mock function with given fields: ctx
+func (_m *Repository) FetchTypeChart(ctx context.Context) (*model.TypeChart, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchTypeChart")
+	}
+
+	var r0 *model.TypeChart
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*model.TypeChart, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *model.TypeChart); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.TypeChart)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LinkBerryItem provides a mock function with given fields: ctx, berry, itemID
+func (_m *Repository) LinkBerryItem(ctx context.Context, berry string, itemID int) error {
+	ret := _m.Called(ctx, berry, itemID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LinkBerryItem")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) error); ok {
+		r0 = rf(ctx, berry, itemID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetBerryGrowth provides a mock function with given fields: ctx, growth
+func (_m *Repository) SetBerryGrowth(ctx context.Context, growth model.BerryGrowth) error {
+	ret := _m.Called(ctx, growth)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetBerryGrowth")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.BerryGrowth) error); ok {
+		r0 = rf(ctx, growth)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetBerryNaturalGift provides a mock function with given fields: ctx, berry, typeID, power
+func (_m *Repository) SetBerryNaturalGift(ctx context.Context, berry string, typeID int, power int) error {
+	ret := _m.Called(ctx, berry, typeID, power)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetBerryNaturalGift")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) error); ok {
+		r0 = rf(ctx, berry, typeID, power)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StreamBerries provides a mock function with given fields: ctx, lang, fn
+func (_m *Repository) StreamBerries(ctx context.Context, lang string, fn func(model.Berry) error) error {
+	ret := _m.Called(ctx, lang, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StreamBerries")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, func(model.Berry) error) error); ok {
+		r0 = rf(ctx, lang, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewRepository creates a new instance of Repository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewRepository(t interface {
@@ -1,10 +1,446 @@
 package model
 
+import (
+	"errors"
+	"time"
+)
+
+// ErrNotFound is returned when a requested resource does not exist.
+var ErrNotFound = errors.New("not found")
+
 type Berry struct {
 	Name string `json:"name"`
 	URL  string `json:"url"`
+	// DisplayName and Description are localized through the berry's item.
+	DisplayName string `json:"display_name,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Sprite is the sprite of the berry's item.
+	Sprite string `json:"sprite,omitempty"`
 }
 
 type BerriesResponse struct {
 	Berries []Berry `json:"berries"`
+	// SyncID and SyncedAt identify the sync run the listing was read after.
+	SyncID   int64      `json:"sync_id,omitempty"`
+	SyncedAt *time.Time `json:"synced_at,omitempty"`
+}
+
+// Firmness is a berry firmness and the berries having it.
+type Firmness struct {
+	ID      int     `json:"-"`
+	Name    string  `json:"name"`
+	Berries []Berry `json:"berries"`
+}
+
+type FirmnessesResponse struct {
+	Firmnesses []Firmness `json:"firmnesses"`
+}
+
+// Flavor is a berry flavor and the berries having it, strongest first.
+type Flavor struct {
+	ID      int           `json:"-"`
+	Name    string        `json:"name"`
+	Berries []FlavorBerry `json:"berries"`
+}
+
+// FlavorBerry is a berry with the potency of a flavor in it.
+type FlavorBerry struct {
+	Berry
+	Potency int `json:"potency"`
+}
+
+type FlavorsResponse struct {
+	Flavors []Flavor `json:"flavors"`
+}
+
+// SyncRun is a successfully completed sync.
+type SyncRun struct {
+	ID         int64     `json:"id"`
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+// CacheKey describes a key in the cache namespace.
+type CacheKey struct {
+	Key string `json:"key"`
+	// TTL is the remaining time to live in seconds, -1 when the key does not expire.
+	TTL int64 `json:"ttl"`
+	// Size is the length of the stored value in bytes.
+	Size int64 `json:"size"`
+}
+
+// Pokemon is a Pokémon with its species data, types, abilities and base stats.
+type Pokemon struct {
+	ID             int              `json:"id"`
+	Name           string           `json:"name"`
+	Species        string           `json:"species"`
+	Height         int              `json:"height"`
+	Weight         int              `json:"weight"`
+	BaseExperience int              `json:"base_experience"`
+	CaptureRate    int              `json:"capture_rate"`
+	IsLegendary    bool             `json:"is_legendary"`
+	IsMythical     bool             `json:"is_mythical"`
+	Sprite         string           `json:"sprite"`
+	Types          []PokemonType    `json:"types"`
+	Abilities      []PokemonAbility `json:"abilities"`
+	Stats          []PokemonStat    `json:"stats"`
+	// Names are the localized names of the species, stored for search.
+	Names []Translation `json:"-"`
+}
+
+type PokemonType struct {
+	ID   int    `json:"-"`
+	Name string `json:"name"`
+	Slot int    `json:"slot"`
+}
+
+type PokemonAbility struct {
+	ID       int    `json:"-"`
+	Name     string `json:"name"`
+	Slot     int    `json:"slot"`
+	IsHidden bool   `json:"is_hidden"`
+}
+
+type PokemonStat struct {
+	Name     string `json:"name"`
+	BaseStat int    `json:"base_stat"`
+	Effort   int    `json:"effort"`
+}
+
+// PokemonSummary is a Pokémon as listed by /pokemon.
+type PokemonSummary struct {
+	ID    int      `json:"id"`
+	Name  string   `json:"name"`
+	Types []string `json:"types"`
+}
+
+type PokemonListResponse struct {
+	Pokemon []PokemonSummary `json:"pokemon"`
+}
+
+// Item is an entry of the item catalogue.
+type Item struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	Cost        int       `json:"cost"`
+	FlingPower  int       `json:"fling_power"`
+	FlingEffect *ItemRef  `json:"fling_effect,omitempty"`
+	Category    ItemRef   `json:"category"`
+	Attributes  []ItemRef `json:"attributes"`
+	Effect      string    `json:"effect"`
+	Sprite      string    `json:"sprite"`
+	// Berry is the berry this item is, if any.
+	Berry string `json:"berry,omitempty"`
+	// Translations are stored with the item, the catalogue serves the English slug.
+	Translations []Translation `json:"-"`
+}
+
+// Translation is the name and description of a resource in a language.
+type Translation struct {
+	Language    string
+	Name        string
+	Description string
+}
+
+// ItemRef is a category, attribute or fling effect of an item.
+type ItemRef struct {
+	ID   int    `json:"-"`
+	Name string `json:"name"`
+}
+
+// ItemSummary is an item as listed by /item.
+type ItemSummary struct {
+	ID         int      `json:"id"`
+	Name       string   `json:"name"`
+	Cost       int      `json:"cost"`
+	Category   string   `json:"category"`
+	Attributes []string `json:"attributes"`
+}
+
+type ItemCatalogueResponse struct {
+	Items []ItemSummary `json:"items"`
+}
+
+// ItemFilter narrows the item catalogue, empty fields match every item.
+type ItemFilter struct {
+	Category  string
+	Attribute string
+}
+
+// Type is a Pokémon type with the damage it deals to other types.
+type Type struct {
+	ID       int
+	Name     string
+	DamageTo []DamageRelation
+}
+
+// DamageRelation is the damage multiplier against a defending type.
+type DamageRelation struct {
+	TypeID     int
+	Type       string
+	Multiplier float64
+}
+
+// TypeChart holds everything needed to compute type effectiveness.
+type TypeChart struct {
+	Types []string `json:"types"`
+	// Multipliers maps attacking to defending types, pairs missing from it deal regular damage.
+	Multipliers  map[string]map[string]float64 `json:"multipliers"`
+	NaturalGifts []NaturalGift                 `json:"natural_gifts"`
+}
+
+// NaturalGift is the type and power of Natural Gift when holding a berry.
+type NaturalGift struct {
+	Berry string `json:"berry"`
+	Type  string `json:"type"`
+	Power int    `json:"power"`
+}
+
+// EffectivenessQuery names the defender, either a Pokémon or a type combination.
+type EffectivenessQuery struct {
+	Pokemon string
+	Types   []string
+}
+
+type TypeMultiplier struct {
+	Type       string  `json:"type"`
+	Multiplier float64 `json:"multiplier"`
+}
+
+// NaturalGiftMatchup is a berry whose natural gift is super effective against the defender.
+type NaturalGiftMatchup struct {
+	NaturalGift
+	Multiplier float64 `json:"multiplier"`
+}
+
+type EffectivenessResponse struct {
+	Defending   []string             `json:"defending"`
+	Multipliers []TypeMultiplier     `json:"multipliers"`
+	Berries     []NaturalGiftMatchup `json:"super_effective_berries"`
+}
+
+// EvolutionChain is an evolution chain as a graph of species and the ways
+// of evolving between them.
+type EvolutionChain struct {
+	ID              int                `json:"id"`
+	BabyTriggerItem string             `json:"baby_trigger_item,omitempty"`
+	Species         []EvolutionSpecies `json:"species"`
+	Evolutions      []Evolution        `json:"evolutions"`
+}
+
+type EvolutionSpecies struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	IsBaby bool   `json:"is_baby"`
+}
+
+// Evolution is one way of evolving from a species into another.
+type Evolution struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	EvolutionRequirement
+}
+
+// EvolutionRequirement is what triggers an evolution and what it needs.
+type EvolutionRequirement struct {
+	Trigger  string `json:"trigger"`
+	Item     string `json:"item,omitempty"`
+	HeldItem string `json:"held_item,omitempty"`
+	// Conditions holds any other requirement, e.g. min_level or time_of_day.
+	Conditions map[string]string `json:"conditions,omitempty"`
+}
+
+// EvolutionNode is a species of an evolution tree.
+type EvolutionNode struct {
+	Species string `json:"species"`
+	IsBaby  bool   `json:"is_baby"`
+	// Requirements are the ways of evolving into the species from its parent.
+	Requirements []EvolutionRequirement `json:"requirements,omitempty"`
+	EvolvesTo    []EvolutionNode        `json:"evolves_to"`
+}
+
+type EvolutionChainResponse struct {
+	ID              int           `json:"id"`
+	BabyTriggerItem string        `json:"baby_trigger_item,omitempty"`
+	Chain           EvolutionNode `json:"chain"`
+}
+
+type EvolutionLinksResponse struct {
+	Species     string      `json:"species"`
+	EvolvesFrom []Evolution `json:"evolves_from"`
+	EvolvesTo   []Evolution `json:"evolves_to"`
+}
+
+// DefaultLanguage is served when no requested language is known, and fills
+// in missing translations.
+const DefaultLanguage = "en"
+
+// Languages are the PokeAPI language names translations are kept in.
+var Languages = []string{
+	"ja-Hrkt", "roomaji", "ko", "zh-Hant", "fr", "de", "es", "it", "en", "cs", "ja", "zh-Hans", "pt-BR",
+}
+
+// Asset is an upstream file, such as a sprite, mirrored to the blob store.
+type Asset struct {
+	// URL is the upstream url the asset was downloaded from.
+	URL string `json:"url"`
+	// Hash is the hex SHA-256 of the content, the key of the blob.
+	Hash        string `json:"hash"`
+	ContentType string `json:"content_type"`
+}
+
+// Kinds of searchable resources.
+const (
+	SearchTypeBerry   = "berry"
+	SearchTypeItem    = "item"
+	SearchTypePokemon = "pokemon"
+)
+
+// SearchDocument is a resource as indexed for search.
+type SearchDocument struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+	// Names maps a language to the localized name of the resource.
+	Names map[string]string `json:"names,omitempty"`
+}
+
+// SearchDocuments are every searchable resource, the index is built from them.
+type SearchDocuments struct {
+	Documents []SearchDocument `json:"documents"`
+}
+
+// SearchResult is a resource matching a query, higher scores rank first.
+type SearchResult struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+	// Matched is the localized name that matched, empty when the name did.
+	Matched  string  `json:"matched,omitempty"`
+	Language string  `json:"language,omitempty"`
+	Score    float64 `json:"score"`
+}
+
+type SearchResponse struct {
+	Query   string         `json:"query"`
+	Results []SearchResult `json:"results"`
+}
+
+// Nature is a nature and the flavors liked and hated by the Pokémon having
+// it. Neutral natures have no preference.
+type Nature struct {
+	ID            int    `json:"-"`
+	Name          string `json:"name"`
+	IncreasedStat string `json:"increased_stat,omitempty"`
+	DecreasedStat string `json:"decreased_stat,omitempty"`
+	LikesFlavorID int    `json:"-"`
+	LikesFlavor   string `json:"likes_flavor,omitempty"`
+	HatesFlavorID int    `json:"-"`
+	HatesFlavor   string `json:"hates_flavor,omitempty"`
+}
+
+// FlavorProfile is a berry with the potency of each of its flavors.
+type FlavorProfile struct {
+	Berry   string         `json:"berry"`
+	Potency map[string]int `json:"potency"`
+	// Confuses is set on berries confusing a Pokémon that dislikes their flavor.
+	Confuses bool `json:"confuses"`
+}
+
+// FlavorProfiles are the flavors and the profile of every berry.
+type FlavorProfiles struct {
+	Flavors []string        `json:"flavors"`
+	Berries []FlavorProfile `json:"berries"`
+}
+
+// RecommendationQuery names the preference, either a nature or the liked
+// and disliked flavors.
+type RecommendationQuery struct {
+	Nature   string
+	Likes    string
+	Dislikes string
+}
+
+// BerryRecommendation is a berry scored by its liked minus its disliked potency.
+type BerryRecommendation struct {
+	Berry           string `json:"berry"`
+	Score           int    `json:"score"`
+	LikedPotency    int    `json:"liked_potency"`
+	DislikedPotency int    `json:"disliked_potency"`
+}
+
+type RecommendationResponse struct {
+	Nature   *Nature               `json:"nature,omitempty"`
+	Likes    string                `json:"likes,omitempty"`
+	Dislikes string                `json:"dislikes,omitempty"`
+	Berries  []BerryRecommendation `json:"berries"`
+	// Excluded are the berries that would confuse the Pokémon.
+	Excluded []string `json:"excluded"`
+}
+
+// BerryGrowth is how the tree of a berry grows.
+type BerryGrowth struct {
+	Berry string `json:"berry"`
+	// GrowthTime is the hours the tree takes to grow each of its four stages.
+	GrowthTime int `json:"growth_time"`
+	MaxHarvest int `json:"max_harvest"`
+	// Size is the size of the berry in millimeters.
+	Size int `json:"size"`
+	// SoilDryness is how fast the tree dries out the soil, higher is faster.
+	SoilDryness int `json:"soil_dryness"`
+}
+
+type BerryGrowths struct {
+	Berries []BerryGrowth `json:"berries"`
+}
+
+// PlanQuery asks to plant Berries over Plots plots, in turn, for Hours hours.
+type PlanQuery struct {
+	Berries []string
+	Plots   int
+	Hours   int
+}
+
+// Actions of a PlotEvent.
+const (
+	PlotActionPlant   = "plant"
+	PlotActionWater   = "water"
+	PlotActionHarvest = "harvest"
+)
+
+// PlotEvent is an action on a plot, Hour hours after the plan starts.
+type PlotEvent struct {
+	Hour   int    `json:"hour"`
+	Action string `json:"action"`
+	// Yield is the number of berries picked by a harvest.
+	Yield int `json:"yield,omitempty"`
+}
+
+// PlotPlan is the berry grown on a plot and its timeline.
+type PlotPlan struct {
+	Plot int `json:"plot"`
+	BerryGrowth
+	Harvests  int `json:"harvests"`
+	Yield     int `json:"yield"`
+	Waterings int `json:"waterings"`
+	// WateringInterval is the most hours between waterings keeping the soil
+	// moist, zero when it never dries out.
+	WateringInterval int         `json:"watering_interval"`
+	Timeline         []PlotEvent `json:"timeline"`
+}
+
+// ScheduledHarvest is a harvest of the plan, across every plot.
+type ScheduledHarvest struct {
+	Hour  int    `json:"hour"`
+	Plot  int    `json:"plot"`
+	Berry string `json:"berry"`
+	Yield int    `json:"yield"`
+}
+
+type PlanResponse struct {
+	Hours int        `json:"hours"`
+	Plots []PlotPlan `json:"plots"`
+	// Harvests is every harvest by hour, then plot.
+	Harvests []ScheduledHarvest `json:"harvests"`
+	// Yield is the number of berries harvested per berry.
+	Yield      map[string]int `json:"yield"`
+	TotalYield int            `json:"total_yield"`
+	Waterings  int            `json:"waterings"`
 }
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/inasknh/simple-poke-app/internal/model"
+)
+
+const (
+	upsertAsset = "INSERT INTO assets (url, hash, content_type) VALUES (?, ?, ?) " +
+		"ON DUPLICATE KEY UPDATE hash = VALUES(hash), content_type = VALUES(content_type)"
+	getAssetByURL  = "SELECT url, hash, content_type FROM assets WHERE url = ?"
+	getAssetByHash = "SELECT url, hash, content_type FROM assets WHERE hash = ? LIMIT 1"
+)
+
+func (r *repository) CreateAsset(ctx context.Context, asset model.Asset) error {
+	if _, err := r.db.ExecContext(ctx, upsertAsset, asset.URL, asset.Hash, asset.ContentType); err != nil {
+		return fmt.Errorf("failed to insert asset: %w", err)
+	}
+
+	return nil
+}
+
+func (r *repository) FetchAssetByURL(ctx context.Context, url string) (*model.Asset, error) {
+	return r.fetchAsset(ctx, getAssetByURL, url)
+}
+
+func (r *repository) FetchAsset(ctx context.Context, hash string) (*model.Asset, error) {
+	return r.fetchAsset(ctx, getAssetByHash, hash)
+}
+
+func (r *repository) fetchAsset(ctx context.Context, query string, arg string) (*model.Asset, error) {
+	var asset model.Asset
+	err := r.db.QueryRowContext(ctx, query, arg).Scan(&asset.URL, &asset.Hash, &asset.ContentType)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &asset, nil
+}
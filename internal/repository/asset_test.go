@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/inasknh/simple-poke-app/internal/model"
+	"reflect"
+	"testing"
+)
+
+func Test_repository_CreateAsset(t *testing.T) {
+	asset := model.Asset{URL: "https://sprites/1.png", Hash: "0f", ContentType: "image/png"}
+
+	tests := []struct {
+		name     string
+		wantErr  bool
+		mockCall func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name:    "given an error when upserting should return an error",
+			wantErr: true,
+			mockCall: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(upsertAsset).WillReturnError(errors.New("any error"))
+			},
+		},
+		{
+			name:    "given happy flow should upsert the asset",
+			wantErr: false,
+			mockCall: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(upsertAsset).WithArgs("https://sprites/1.png", "0f", "image/png").WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock, closeDB := newTestRepository(t)
+			defer closeDB()
+
+			tt.mockCall(mock)
+
+			if err := r.CreateAsset(context.Background(), asset); (err != nil) != tt.wantErr {
+				t.Errorf("CreateAsset() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("CreateAsset() %v", err)
+			}
+		})
+	}
+}
+
+func Test_repository_FetchAsset(t *testing.T) {
+	columns := []string{"url", "hash", "content_type"}
+
+	tests := []struct {
+		name     string
+		fetch    func(r *repository) (*model.Asset, error)
+		want     *model.Asset
+		wantErr  error
+		mockCall func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name: "given an unknown url should return ErrNotFound",
+			fetch: func(r *repository) (*model.Asset, error) {
+				return r.FetchAssetByURL(context.Background(), "https://sprites/1.png")
+			},
+			wantErr: model.ErrNotFound,
+			mockCall: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(getAssetByURL).WithArgs("https://sprites/1.png").WillReturnRows(mock.NewRows(columns))
+			},
+		},
+		{
+			name: "given a known url should return the asset",
+			fetch: func(r *repository) (*model.Asset, error) {
+				return r.FetchAssetByURL(context.Background(), "https://sprites/1.png")
+			},
+			want: &model.Asset{URL: "https://sprites/1.png", Hash: "0f", ContentType: "image/png"},
+			mockCall: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(getAssetByURL).WithArgs("https://sprites/1.png").
+					WillReturnRows(mock.NewRows(columns).AddRow("https://sprites/1.png", "0f", "image/png"))
+			},
+		},
+		{
+			name: "given an error when querying by hash should return it",
+			fetch: func(r *repository) (*model.Asset, error) {
+				return r.FetchAsset(context.Background(), "0f")
+			},
+			wantErr: errors.New("any error"),
+			mockCall: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(getAssetByHash).WithArgs("0f").WillReturnError(errors.New("any error"))
+			},
+		},
+		{
+			name: "given a known hash should return the asset",
+			fetch: func(r *repository) (*model.Asset, error) {
+				return r.FetchAsset(context.Background(), "0f")
+			},
+			want: &model.Asset{URL: "https://sprites/1.png", Hash: "0f", ContentType: "image/png"},
+			mockCall: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(getAssetByHash).WithArgs("0f").
+					WillReturnRows(mock.NewRows(columns).AddRow("https://sprites/1.png", "0f", "image/png"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock, closeDB := newTestRepository(t)
+			defer closeDB()
+
+			tt.mockCall(mock)
+
+			got, err := tt.fetch(r)
+			if (err != nil) != (tt.wantErr != nil) || (err != nil && err.Error() != tt.wantErr.Error()) {
+				t.Errorf("FetchAsset() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FetchAsset() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
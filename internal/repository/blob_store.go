@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/inasknh/simple-poke-app/internal/config"
+	"github.com/inasknh/simple-poke-app/internal/model"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// Blob store backends.
+const (
+	BlobStoreFile = "file"
+	BlobStoreNone = "none"
+)
+
+// hashPattern matches the hex SHA-256 blobs are keyed by, it also keeps
+// keys from escaping the store.
+var hashPattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// BlobStore keeps immutable blobs keyed by the hex SHA-256 of their content.
+type BlobStore interface {
+	Exists(ctx context.Context, hash string) (bool, error)
+	// Put stores data under hash.
+	Put(ctx context.Context, hash string, data []byte) error
+	// Open returns the blob stored under hash, or model.ErrNotFound.
+	Open(ctx context.Context, hash string) (io.ReadSeekCloser, error)
+}
+
+// NewBlobStore creates the BlobStore selected by config.Store, nil when
+// assets are not mirrored.
+func NewBlobStore(config config.Assets) (BlobStore, error) {
+	switch config.Store {
+	case "", BlobStoreFile:
+		if config.Dir == "" {
+			return nil, errors.New("dir is required to store assets on the filesystem")
+		}
+		return NewFileBlobStore(config.Dir), nil
+	case BlobStoreNone:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown blob store %q", config.Store)
+	}
+}
+
+type fileBlobStore struct {
+	dir string
+}
+
+// NewFileBlobStore creates a BlobStore keeping blobs under dir, fanned out
+// by the first byte of their hash.
+func NewFileBlobStore(dir string) BlobStore {
+	return &fileBlobStore{dir: dir}
+}
+
+func (s *fileBlobStore) Exists(_ context.Context, hash string) (bool, error) {
+	if !hashPattern.MatchString(hash) {
+		return false, nil
+	}
+
+	_, err := os.Stat(s.path(hash))
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (s *fileBlobStore) Put(_ context.Context, hash string, data []byte) error {
+	if !hashPattern.MatchString(hash) {
+		return fmt.Errorf("invalid blob hash %q", hash)
+	}
+
+	path := s.path(hash)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create blob directory: %w", err)
+	}
+
+	// write aside and rename so readers never see a partial blob
+	tmp, err := os.CreateTemp(filepath.Dir(path), hash+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create blob: %w", err)
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if _, err = tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write blob: %w", err)
+	}
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write blob: %w", err)
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to store blob: %w", err)
+	}
+
+	return nil
+}
+
+func (s *fileBlobStore) Open(_ context.Context, hash string) (io.ReadSeekCloser, error) {
+	if !hashPattern.MatchString(hash) {
+		return nil, model.ErrNotFound
+	}
+
+	f, err := os.Open(s.path(hash))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, model.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func (s *fileBlobStore) path(hash string) string {
+	return filepath.Join(s.dir, hash[:2], hash)
+}
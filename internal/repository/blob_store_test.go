@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"github.com/inasknh/simple-poke-app/internal/config"
+	"github.com/inasknh/simple-poke-app/internal/model"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_NewBlobStore(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  config.Assets
+		wantNil bool
+		wantErr bool
+	}{
+		{
+			name:   "given the default store with a dir should create a file store",
+			config: config.Assets{Dir: t.TempDir()},
+		},
+		{
+			name:    "given the file store without a dir should return an error",
+			config:  config.Assets{Store: BlobStoreFile},
+			wantNil: true,
+			wantErr: true,
+		},
+		{
+			name:    "given no store should disable mirroring",
+			config:  config.Assets{Store: BlobStoreNone},
+			wantNil: true,
+		},
+		{
+			name:    "given an unknown store should return an error",
+			config:  config.Assets{Store: "s3"},
+			wantNil: true,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewBlobStore(tt.config)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.wantNil, got == nil)
+		})
+	}
+}
+
+func Test_fileBlobStore(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	s := NewFileBlobStore(dir)
+	hash := strings.Repeat("ab", 32)
+
+	exists, err := s.Exists(ctx, hash)
+	assert.NoError(t, err)
+	assert.False(t, exists)
+
+	_, err = s.Open(ctx, hash)
+	assert.ErrorIs(t, err, model.ErrNotFound)
+
+	assert.NoError(t, s.Put(ctx, hash, []byte("sprite")))
+	assert.FileExists(t, filepath.Join(dir, "ab", hash))
+
+	exists, err = s.Exists(ctx, hash)
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	blob, err := s.Open(ctx, hash)
+	assert.NoError(t, err)
+	data, err := io.ReadAll(blob)
+	assert.NoError(t, err)
+	assert.Equal(t, "sprite", string(data))
+	assert.NoError(t, blob.Close())
+
+	// keys are never resolved outside the store
+	assert.Error(t, s.Put(ctx, "../escape", []byte("sprite")))
+	_, err = s.Open(ctx, "../"+hash)
+	assert.ErrorIs(t, err, model.ErrNotFound)
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// cacheSchemaVersion is stamped on every cached value. Bump it whenever a
+// cached model changes incompatibly so entries written by older deploys are
+// ignored instead of failing to decode.
+const cacheSchemaVersion byte = 1
+
+// Compression algorithms for cached payloads.
+const (
+	CompressionNone = "none"
+	CompressionGzip = "gzip"
+)
+
+const (
+	encodingRaw byte = iota
+	encodingGzip
+)
+
+// cacheMagic prefixes every value written by encodeEntry.
+var cacheMagic = []byte("pk")
+
+// entryHeaderSize is magic, version, encoding and the soft expiry in unix nanoseconds.
+const entryHeaderSize = 2 + 1 + 1 + 8
+
+// cacheCodec encodes cached values into a small versioned envelope,
+// compressing payloads of at least threshold bytes.
+type cacheCodec struct {
+	compression string
+	threshold   int
+}
+
+func (c cacheCodec) encode(v interface{}, softExpiresAt time.Time) ([]byte, error) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	encoding := encodingRaw
+	if c.compression == CompressionGzip && len(payload) >= c.threshold {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		if _, err = zw.Write(payload); err != nil {
+			return nil, err
+		}
+		if err = zw.Close(); err != nil {
+			return nil, err
+		}
+		payload = buf.Bytes()
+		encoding = encodingGzip
+	}
+
+	data := make([]byte, entryHeaderSize, entryHeaderSize+len(payload))
+	copy(data, cacheMagic)
+	data[2] = cacheSchemaVersion
+	data[3] = encoding
+	binary.BigEndian.PutUint64(data[4:entryHeaderSize], uint64(softExpiresAt.UnixNano()))
+
+	return append(data, payload...), nil
+}
+
+// decode unmarshals data into out and returns its soft expiry. ok is false
+// for values written with another schema version or before the envelope
+// existed; they must be treated as a miss.
+func (c cacheCodec) decode(data []byte, out interface{}) (softExpiresAt time.Time, ok bool, err error) {
+	if len(data) < entryHeaderSize || !bytes.Equal(data[:2], cacheMagic) || data[2] != cacheSchemaVersion {
+		return time.Time{}, false, nil
+	}
+
+	softExpiresAt = time.Unix(0, int64(binary.BigEndian.Uint64(data[4:entryHeaderSize])))
+	payload := data[entryHeaderSize:]
+
+	switch data[3] {
+	case encodingRaw:
+	case encodingGzip:
+		zr, err := gzip.NewReader(bytes.NewReader(payload))
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("%w: %v", ErrCorruptEntry, err)
+		}
+		payload, err = io.ReadAll(zr)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("%w: %v", ErrCorruptEntry, err)
+		}
+	default:
+		return time.Time{}, false, fmt.Errorf("%w: unknown encoding %d", ErrCorruptEntry, data[3])
+	}
+
+	if err = json.Unmarshal(payload, out); err != nil {
+		return time.Time{}, false, fmt.Errorf("%w: %v", ErrCorruptEntry, err)
+	}
+
+	return softExpiresAt, true, nil
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"github.com/inasknh/simple-poke-app/internal/model"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_cacheCodec_RoundTrip(t *testing.T) {
+	softExpiresAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	small := &model.BerriesResponse{Berries: []model.Berry{
+		{
+			Name: "1",
+			URL:  "1",
+		},
+	}}
+	large := &model.BerriesResponse{}
+	for i := 0; i < 100; i++ {
+		large.Berries = append(large.Berries, model.Berry{
+			Name: "cheri",
+			URL:  "https://pokeapi.co/api/v2/berry/1/",
+		})
+	}
+
+	tests := []struct {
+		name         string
+		codec        cacheCodec
+		value        *model.BerriesResponse
+		wantEncoding byte
+	}{
+		{
+			name:         "given no compression should store raw payload",
+			codec:        cacheCodec{compression: CompressionNone},
+			value:        large,
+			wantEncoding: encodingRaw,
+		},
+		{
+			name:         "given gzip and payload above threshold should compress",
+			codec:        cacheCodec{compression: CompressionGzip, threshold: 1024},
+			value:        large,
+			wantEncoding: encodingGzip,
+		},
+		{
+			name:         "given gzip and payload below threshold should store raw payload",
+			codec:        cacheCodec{compression: CompressionGzip, threshold: 1024},
+			value:        small,
+			wantEncoding: encodingRaw,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.codec.encode(tt.value, softExpiresAt)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantEncoding, data[3])
+
+			var got model.BerriesResponse
+			gotSoft, ok, err := tt.codec.decode(data, &got)
+			assert.NoError(t, err)
+			assert.True(t, ok)
+			assert.True(t, softExpiresAt.Equal(gotSoft))
+			assert.Equal(t, tt.value, &got)
+		})
+	}
+}
+
+func Test_cacheCodec_CompressionReducesSize(t *testing.T) {
+	large := &model.BerriesResponse{}
+	for i := 0; i < 100; i++ {
+		large.Berries = append(large.Berries, model.Berry{
+			Name: strings.Repeat("cheri", 4),
+			URL:  "https://pokeapi.co/api/v2/berry/1/",
+		})
+	}
+
+	raw, err := cacheCodec{}.encode(large, time.Now())
+	assert.NoError(t, err)
+	compressed, err := cacheCodec{compression: CompressionGzip}.encode(large, time.Now())
+	assert.NoError(t, err)
+
+	assert.Less(t, len(compressed), len(raw)/4)
+}
+
+func Test_cacheCodec_Decode_Invalid(t *testing.T) {
+	valid, _ := cacheCodec{compression: CompressionGzip}.encode(&model.BerriesResponse{}, time.Now())
+
+	unknownEncoding := append([]byte{}, valid...)
+	unknownEncoding[3] = 9
+
+	truncatedGzip := valid[:len(valid)-4]
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantOK  bool
+		wantErr error
+	}{
+		{
+			name:   "given value shorter than the header should be a miss",
+			data:   []byte("pk"),
+			wantOK: false,
+		},
+		{
+			name:   "given value without magic should be a miss",
+			data:   []byte(`{"berries":[{"name":"1","url":"1"}]}`),
+			wantOK: false,
+		},
+		{
+			name:    "given unknown encoding should be corrupt",
+			data:    unknownEncoding,
+			wantErr: ErrCorruptEntry,
+		},
+		{
+			name:    "given truncated gzip payload should be corrupt",
+			data:    truncatedGzip,
+			wantErr: ErrCorruptEntry,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got model.BerriesResponse
+			_, ok, err := cacheCodec{}.decode(tt.data, &got)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantOK, ok)
+		})
+	}
+}
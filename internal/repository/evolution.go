@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/inasknh/simple-poke-app/internal/model"
+	"maps"
+	"slices"
+)
+
+const (
+	upsertEvolutionChain = "INSERT INTO evolution_chains (id, baby_trigger_item) VALUES (?, ?) " +
+		"ON DUPLICATE KEY UPDATE baby_trigger_item = VALUES(baby_trigger_item)"
+	deleteEvolutionEdges   = "DELETE FROM evolution_edges WHERE chain_id = ?"
+	upsertEvolutionSpecies = "INSERT INTO evolution_species (id, name, chain_id, is_baby) VALUES (?, ?, ?, ?) " +
+		"ON DUPLICATE KEY UPDATE name = VALUES(name), chain_id = VALUES(chain_id), is_baby = VALUES(is_baby)"
+	insertEvolutionEdge = "INSERT INTO evolution_edges (chain_id, from_species_id, to_species_id, `trigger`, item, held_item) " +
+		"VALUES (?, ?, ?, ?, ?, ?)"
+	insertEvolutionCondition = "INSERT INTO evolution_conditions (edge_id, name, value) VALUES (?, ?, ?)"
+
+	getEvolutionChain = "SELECT c.id, c.baby_trigger_item FROM evolution_species s " +
+		"JOIN evolution_chains c ON c.id = s.chain_id WHERE s.name = ? LIMIT 1"
+	getEvolutionSpecies = "SELECT id, name, is_baby FROM evolution_species WHERE chain_id = ? ORDER BY id"
+	getEvolutionEdges   = "SELECT e.id, f.name, t.name, e.`trigger`, e.item, e.held_item, c.name, c.value FROM evolution_edges e " +
+		"JOIN evolution_species f ON f.id = e.from_species_id JOIN evolution_species t ON t.id = e.to_species_id " +
+		"LEFT JOIN evolution_conditions c ON c.edge_id = e.id " +
+		"WHERE e.chain_id = ? ORDER BY e.id, c.name"
+)
+
+func (r *repository) CreateEvolutionChain(ctx context.Context, chain model.EvolutionChain) error {
+	return r.withTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, upsertEvolutionChain, chain.ID, nullString(chain.BabyTriggerItem)); err != nil {
+			return fmt.Errorf("failed to insert evolution chain: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, deleteEvolutionEdges, chain.ID); err != nil {
+			return fmt.Errorf("failed to clear evolutions: %w", err)
+		}
+
+		ids := make(map[string]int, len(chain.Species))
+		for _, species := range chain.Species {
+			if _, err := tx.ExecContext(ctx, upsertEvolutionSpecies, species.ID, species.Name, chain.ID, species.IsBaby); err != nil {
+				return fmt.Errorf("failed to insert evolution species: %w", err)
+			}
+			ids[species.Name] = species.ID
+		}
+
+		for _, evolution := range chain.Evolutions {
+			res, err := tx.ExecContext(ctx, insertEvolutionEdge,
+				chain.ID,
+				ids[evolution.From],
+				ids[evolution.To],
+				evolution.Trigger,
+				nullString(evolution.Item),
+				nullString(evolution.HeldItem),
+			)
+			if err != nil {
+				return fmt.Errorf("failed to insert evolution: %w", err)
+			}
+			edgeID, err := res.LastInsertId()
+			if err != nil {
+				return fmt.Errorf("failed to insert evolution: %w", err)
+			}
+
+			for _, name := range slices.Sorted(maps.Keys(evolution.Conditions)) {
+				if _, err = tx.ExecContext(ctx, insertEvolutionCondition, edgeID, name, evolution.Conditions[name]); err != nil {
+					return fmt.Errorf("failed to insert evolution condition: %w", err)
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r *repository) FetchEvolutionChain(ctx context.Context, species string) (*model.EvolutionChain, error) {
+	var chain model.EvolutionChain
+	var babyTriggerItem sql.NullString
+	err := r.db.QueryRowContext(ctx, getEvolutionChain, species).Scan(&chain.ID, &babyTriggerItem)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrNotFound
+		}
+		return nil, err
+	}
+	chain.BabyTriggerItem = babyTriggerItem.String
+
+	chain.Species = []model.EvolutionSpecies{}
+	err = r.queryEach(ctx, getEvolutionSpecies, []interface{}{chain.ID}, func(rows *sql.Rows) error {
+		var s model.EvolutionSpecies
+		if err := rows.Scan(&s.ID, &s.Name, &s.IsBaby); err != nil {
+			return err
+		}
+		chain.Species = append(chain.Species, s)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	chain.Evolutions = []model.Evolution{}
+	lastEdge := 0
+	err = r.queryEach(ctx, getEvolutionEdges, []interface{}{chain.ID}, func(rows *sql.Rows) error {
+		var edgeID int
+		var evolution model.Evolution
+		var item, heldItem, condition, value sql.NullString
+		err := rows.Scan(&edgeID, &evolution.From, &evolution.To, &evolution.Trigger, &item, &heldItem, &condition, &value)
+		if err != nil {
+			return err
+		}
+
+		// rows are ordered by edge, a new id starts the next evolution
+		if edgeID != lastEdge {
+			evolution.Item = item.String
+			evolution.HeldItem = heldItem.String
+			chain.Evolutions = append(chain.Evolutions, evolution)
+			lastEdge = edgeID
+		}
+		if condition.Valid {
+			last := &chain.Evolutions[len(chain.Evolutions)-1]
+			if last.Conditions == nil {
+				last.Conditions = map[string]string{}
+			}
+			last.Conditions[condition.String] = value.String
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &chain, nil
+}
+
+// nullString stores empty strings as NULL.
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
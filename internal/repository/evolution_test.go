@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/inasknh/simple-poke-app/internal/model"
+	"reflect"
+	"testing"
+)
+
+func Test_repository_CreateEvolutionChain(t *testing.T) {
+	chain := model.EvolutionChain{
+		ID:      1,
+		Species: []model.EvolutionSpecies{{ID: 1, Name: "bulbasaur"}, {ID: 2, Name: "ivysaur"}},
+		Evolutions: []model.Evolution{
+			{
+				From: "bulbasaur",
+				To:   "ivysaur",
+				EvolutionRequirement: model.EvolutionRequirement{
+					Trigger:    "level-up",
+					Conditions: map[string]string{"time_of_day": "day", "min_level": "16"},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		wantErr  bool
+		mockCall func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name:    "given an error when upserting the chain should rollback and return an error",
+			wantErr: true,
+			mockCall: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(upsertEvolutionChain).WillReturnError(errors.New("any error"))
+				mock.ExpectRollback()
+			},
+		},
+		{
+			name:    "given happy flow should replace the evolutions and commit",
+			wantErr: false,
+			mockCall: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(upsertEvolutionChain).WithArgs(1, nil).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(deleteEvolutionEdges).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(upsertEvolutionSpecies).WithArgs(1, "bulbasaur", 1, false).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(upsertEvolutionSpecies).WithArgs(2, "ivysaur", 1, false).WillReturnResult(sqlmock.NewResult(2, 1))
+				mock.ExpectExec(insertEvolutionEdge).WithArgs(1, 1, 2, "level-up", nil, nil).WillReturnResult(sqlmock.NewResult(7, 1))
+				mock.ExpectExec(insertEvolutionCondition).WithArgs(7, "min_level", "16").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(insertEvolutionCondition).WithArgs(7, "time_of_day", "day").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock, closeDB := newTestRepository(t)
+			defer closeDB()
+
+			tt.mockCall(mock)
+
+			if err := r.CreateEvolutionChain(context.Background(), chain); (err != nil) != tt.wantErr {
+				t.Errorf("CreateEvolutionChain() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("CreateEvolutionChain() %v", err)
+			}
+		})
+	}
+}
+
+func Test_repository_FetchEvolutionChain(t *testing.T) {
+	tests := []struct {
+		name     string
+		want     *model.EvolutionChain
+		wantErr  error
+		mockCall func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name:    "given no rows should return nil and ErrNotFound",
+			want:    nil,
+			wantErr: model.ErrNotFound,
+			mockCall: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(getEvolutionChain).WithArgs("eevee").WillReturnRows(mock.NewRows([]string{"id", "baby_trigger_item"}))
+			},
+		},
+		{
+			name: "given a chain should group the conditions by evolution",
+			want: &model.EvolutionChain{
+				ID:      67,
+				Species: []model.EvolutionSpecies{{ID: 133, Name: "eevee"}, {ID: 134, Name: "vaporeon"}, {ID: 196, Name: "espeon"}},
+				Evolutions: []model.Evolution{
+					{From: "eevee", To: "vaporeon", EvolutionRequirement: model.EvolutionRequirement{Trigger: "use-item", Item: "water-stone"}},
+					{From: "eevee", To: "espeon", EvolutionRequirement: model.EvolutionRequirement{
+						Trigger:    "level-up",
+						Conditions: map[string]string{"min_happiness": "160", "time_of_day": "day"},
+					}},
+				},
+			},
+			mockCall: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(getEvolutionChain).WithArgs("eevee").WillReturnRows(mock.NewRows([]string{"id", "baby_trigger_item"}).
+					AddRow(67, nil))
+				mock.ExpectQuery(getEvolutionSpecies).WithArgs(67).WillReturnRows(mock.NewRows([]string{"id", "name", "is_baby"}).
+					AddRow(133, "eevee", false).
+					AddRow(134, "vaporeon", false).
+					AddRow(196, "espeon", false))
+				mock.ExpectQuery(getEvolutionEdges).WithArgs(67).WillReturnRows(
+					mock.NewRows([]string{"id", "from", "to", "trigger", "item", "held_item", "condition", "value"}).
+						AddRow(1, "eevee", "vaporeon", "use-item", "water-stone", nil, nil, nil).
+						AddRow(2, "eevee", "espeon", "level-up", nil, nil, "min_happiness", "160").
+						AddRow(2, "eevee", "espeon", "level-up", nil, nil, "time_of_day", "day"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock, closeDB := newTestRepository(t)
+			defer closeDB()
+
+			tt.mockCall(mock)
+
+			got, err := r.FetchEvolutionChain(context.Background(), "eevee")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("FetchEvolutionChain() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FetchEvolutionChain() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
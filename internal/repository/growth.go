@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/inasknh/simple-poke-app/internal/model"
+)
+
+const (
+	setBerryGrowth = "UPDATE berries SET growth_time = ?, max_harvest = ?, size = ?, soil_dryness = ? WHERE name = ?"
+
+	getBerryGrowths = "SELECT name, growth_time, max_harvest, size, soil_dryness FROM berries " +
+		"WHERE growth_time IS NOT NULL ORDER BY id"
+)
+
+func (r *repository) SetBerryGrowth(ctx context.Context, growth model.BerryGrowth) error {
+	_, err := r.db.ExecContext(ctx, setBerryGrowth,
+		growth.GrowthTime,
+		growth.MaxHarvest,
+		growth.Size,
+		growth.SoilDryness,
+		growth.Berry,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set berry growth: %w", err)
+	}
+
+	return nil
+}
+
+func (r *repository) FetchBerryGrowths(ctx context.Context) (*model.BerryGrowths, error) {
+	res := &model.BerryGrowths{Berries: []model.BerryGrowth{}}
+	err := r.queryEach(ctx, getBerryGrowths, nil, func(rows *sql.Rows) error {
+		var growth model.BerryGrowth
+		if err := rows.Scan(&growth.Berry, &growth.GrowthTime, &growth.MaxHarvest, &growth.Size, &growth.SoilDryness); err != nil {
+			return err
+		}
+		res.Berries = append(res.Berries, growth)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/inasknh/simple-poke-app/internal/model"
+	"reflect"
+	"testing"
+)
+
+func Test_repository_SetBerryGrowth(t *testing.T) {
+	cheri := model.BerryGrowth{Berry: "cheri", GrowthTime: 3, MaxHarvest: 5, Size: 20, SoilDryness: 15}
+
+	tests := []struct {
+		name     string
+		wantErr  bool
+		mockCall func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name:    "given an error when updating the berry should return an error",
+			wantErr: true,
+			mockCall: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(setBerryGrowth).WithArgs(3, 5, 20, 15, "cheri").WillReturnError(errors.New("any error"))
+			},
+		},
+		{
+			name: "given happy flow should update the berry",
+			mockCall: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(setBerryGrowth).WithArgs(3, 5, 20, 15, "cheri").WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock, closeDB := newTestRepository(t)
+			defer closeDB()
+
+			tt.mockCall(mock)
+
+			if err := r.SetBerryGrowth(context.Background(), cheri); (err != nil) != tt.wantErr {
+				t.Errorf("SetBerryGrowth() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("SetBerryGrowth() %v", err)
+			}
+		})
+	}
+}
+
+func Test_repository_FetchBerryGrowths(t *testing.T) {
+	columns := []string{"name", "growth_time", "max_harvest", "size", "soil_dryness"}
+
+	tests := []struct {
+		name     string
+		want     *model.BerryGrowths
+		wantErr  bool
+		mockCall func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name:    "given an error when querying should return an error",
+			wantErr: true,
+			mockCall: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(getBerryGrowths).WillReturnError(errors.New("any error"))
+			},
+		},
+		{
+			name: "given no berry synced with its growth should return an empty list",
+			want: &model.BerryGrowths{Berries: []model.BerryGrowth{}},
+			mockCall: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(getBerryGrowths).WillReturnRows(mock.NewRows(columns))
+			},
+		},
+		{
+			name: "given synced berries should return their growth",
+			want: &model.BerryGrowths{Berries: []model.BerryGrowth{
+				{Berry: "cheri", GrowthTime: 3, MaxHarvest: 5, Size: 20, SoilDryness: 15},
+				{Berry: "lum", GrowthTime: 12, MaxHarvest: 5, Size: 34, SoilDryness: 8},
+			}},
+			mockCall: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(getBerryGrowths).WillReturnRows(mock.NewRows(columns).
+					AddRow("cheri", 3, 5, 20, 15).
+					AddRow("lum", 12, 5, 34, 8))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock, closeDB := newTestRepository(t)
+			defer closeDB()
+
+			tt.mockCall(mock)
+
+			got, err := r.FetchBerryGrowths(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("FetchBerryGrowths() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FetchBerryGrowths() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/inasknh/simple-poke-app/internal/model"
+)
+
+const (
+	upsertItemCategory  = "INSERT INTO item_categories (id, name) VALUES (?, ?) ON DUPLICATE KEY UPDATE name = VALUES(name)"
+	upsertItemAttribute = "INSERT INTO item_attributes (id, name) VALUES (?, ?) ON DUPLICATE KEY UPDATE name = VALUES(name)"
+	upsertFlingEffect   = "INSERT INTO fling_effects (id, name) VALUES (?, ?) ON DUPLICATE KEY UPDATE name = VALUES(name)"
+	upsertItem          = "INSERT INTO items (id, name, cost, fling_power, fling_effect_id, category_id, effect, sprite) " +
+		"VALUES (?, ?, ?, ?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE name = VALUES(name), cost = VALUES(cost), " +
+		"fling_power = VALUES(fling_power), fling_effect_id = VALUES(fling_effect_id), category_id = VALUES(category_id), " +
+		"effect = VALUES(effect), sprite = VALUES(sprite)"
+	deleteItemAttributes   = "DELETE FROM item_attribute_map WHERE item_id = ?"
+	insertItemAttribute    = "INSERT INTO item_attribute_map (item_id, attribute_id) VALUES (?, ?)"
+	linkBerryItem          = "UPDATE berries SET item_id = ? WHERE name = ?"
+	deleteItemTranslations = "DELETE FROM item_translations WHERE item_id = ?"
+	insertItemTranslation  = "INSERT INTO item_translations (item_id, language, name, description) VALUES (?, ?, ?, ?)"
+
+	getItemCatalogue = "SELECT i.id, i.name, i.cost, c.name, a.name FROM items i " +
+		"JOIN item_categories c ON c.id = i.category_id " +
+		"LEFT JOIN item_attribute_map m ON m.item_id = i.id LEFT JOIN item_attributes a ON a.id = m.attribute_id " +
+		"ORDER BY i.id, a.id"
+	getItem = "SELECT i.id, i.name, i.cost, i.fling_power, f.id, f.name, c.id, c.name, i.effect, i.sprite, b.name FROM items i " +
+		"JOIN item_categories c ON c.id = i.category_id " +
+		"LEFT JOIN fling_effects f ON f.id = i.fling_effect_id " +
+		"LEFT JOIN berries b ON b.item_id = i.id " +
+		"WHERE i.name = ? LIMIT 1"
+	getItemAttributes = "SELECT a.id, a.name FROM item_attribute_map m JOIN item_attributes a ON a.id = m.attribute_id " +
+		"WHERE m.item_id = ? ORDER BY a.id"
+)
+
+func (r *repository) CreateItem(ctx context.Context, item model.Item) error {
+	return r.withTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, upsertItemCategory, item.Category.ID, item.Category.Name); err != nil {
+			return fmt.Errorf("failed to insert item category: %w", err)
+		}
+
+		var flingEffectID sql.NullInt64
+		if item.FlingEffect != nil {
+			if _, err := tx.ExecContext(ctx, upsertFlingEffect, item.FlingEffect.ID, item.FlingEffect.Name); err != nil {
+				return fmt.Errorf("failed to insert fling effect: %w", err)
+			}
+			flingEffectID = sql.NullInt64{Int64: int64(item.FlingEffect.ID), Valid: true}
+		}
+
+		_, err := tx.ExecContext(ctx, upsertItem,
+			item.ID,
+			item.Name,
+			item.Cost,
+			item.FlingPower,
+			flingEffectID,
+			item.Category.ID,
+			item.Effect,
+			item.Sprite,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert item: %w", err)
+		}
+
+		if _, err = tx.ExecContext(ctx, deleteItemAttributes, item.ID); err != nil {
+			return fmt.Errorf("failed to clear item attributes: %w", err)
+		}
+		for _, attribute := range item.Attributes {
+			if _, err = tx.ExecContext(ctx, upsertItemAttribute, attribute.ID, attribute.Name); err != nil {
+				return fmt.Errorf("failed to insert item attribute: %w", err)
+			}
+			if _, err = tx.ExecContext(ctx, insertItemAttribute, item.ID, attribute.ID); err != nil {
+				return fmt.Errorf("failed to link item attribute: %w", err)
+			}
+		}
+
+		if _, err = tx.ExecContext(ctx, deleteItemTranslations, item.ID); err != nil {
+			return fmt.Errorf("failed to clear item translations: %w", err)
+		}
+		for _, translation := range item.Translations {
+			_, err = tx.ExecContext(ctx, insertItemTranslation, item.ID, translation.Language, translation.Name, translation.Description)
+			if err != nil {
+				return fmt.Errorf("failed to insert item translation: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r *repository) LinkBerryItem(ctx context.Context, berry string, itemID int) error {
+	if _, err := r.db.ExecContext(ctx, linkBerryItem, itemID, berry); err != nil {
+		return fmt.Errorf("failed to link berry to item: %w", err)
+	}
+
+	return nil
+}
+
+func (r *repository) FetchItemCatalogue(ctx context.Context) (*model.ItemCatalogueResponse, error) {
+	res := []model.ItemSummary{}
+	err := r.queryEach(ctx, getItemCatalogue, nil, func(rows *sql.Rows) error {
+		var item model.ItemSummary
+		var attribute sql.NullString
+		if err := rows.Scan(&item.ID, &item.Name, &item.Cost, &item.Category, &attribute); err != nil {
+			return err
+		}
+
+		// rows are ordered by item, a new id starts the next one
+		if len(res) == 0 || res[len(res)-1].ID != item.ID {
+			item.Attributes = []string{}
+			res = append(res, item)
+		}
+		if attribute.Valid {
+			last := &res[len(res)-1]
+			last.Attributes = append(last.Attributes, attribute.String)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.ItemCatalogueResponse{Items: res}, nil
+}
+
+func (r *repository) FetchItem(ctx context.Context, name string) (*model.Item, error) {
+	var item model.Item
+	var flingEffectID sql.NullInt64
+	var flingEffect, berry sql.NullString
+	err := r.db.QueryRowContext(ctx, getItem, name).Scan(
+		&item.ID,
+		&item.Name,
+		&item.Cost,
+		&item.FlingPower,
+		&flingEffectID,
+		&flingEffect,
+		&item.Category.ID,
+		&item.Category.Name,
+		&item.Effect,
+		&item.Sprite,
+		&berry,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrNotFound
+		}
+		return nil, err
+	}
+
+	if flingEffect.Valid {
+		item.FlingEffect = &model.ItemRef{ID: int(flingEffectID.Int64), Name: flingEffect.String}
+	}
+	item.Berry = berry.String
+
+	item.Attributes = []model.ItemRef{}
+	err = r.queryEach(ctx, getItemAttributes, []interface{}{item.ID}, func(rows *sql.Rows) error {
+		var attribute model.ItemRef
+		if err := rows.Scan(&attribute.ID, &attribute.Name); err != nil {
+			return err
+		}
+		item.Attributes = append(item.Attributes, attribute)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
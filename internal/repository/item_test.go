@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/inasknh/simple-poke-app/internal/model"
+	"reflect"
+	"testing"
+)
+
+func Test_repository_CreateItem(t *testing.T) {
+	item := model.Item{
+		ID:          126,
+		Name:        "cheri-berry",
+		Cost:        20,
+		FlingPower:  10,
+		FlingEffect: &model.ItemRef{ID: 3, Name: "berry-effect"},
+		Category:    model.ItemRef{ID: 3, Name: "medicine"},
+		Attributes:  []model.ItemRef{{ID: 5, Name: "holdable"}},
+		Effect:      "Cures paralysis.",
+		Sprite:      "cheri-berry.png",
+		Translations: []model.Translation{
+			{Language: "fr", Name: "Baie Ceriz", Description: "Soigne la paralysie."},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		wantErr  bool
+		mockCall func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name:    "given an error when upserting the category should rollback and return an error",
+			wantErr: true,
+			mockCall: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(upsertItemCategory).WillReturnError(errors.New("any error"))
+				mock.ExpectRollback()
+			},
+		},
+		{
+			name:    "given happy flow should replace the attributes and translations and commit",
+			wantErr: false,
+			mockCall: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(upsertItemCategory).WithArgs(3, "medicine").WillReturnResult(sqlmock.NewResult(3, 1))
+				mock.ExpectExec(upsertFlingEffect).WithArgs(3, "berry-effect").WillReturnResult(sqlmock.NewResult(3, 1))
+				mock.ExpectExec(upsertItem).
+					WithArgs(126, "cheri-berry", 20, 10, int64(3), 3, "Cures paralysis.", "cheri-berry.png").
+					WillReturnResult(sqlmock.NewResult(126, 1))
+				mock.ExpectExec(deleteItemAttributes).WithArgs(126).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(upsertItemAttribute).WithArgs(5, "holdable").WillReturnResult(sqlmock.NewResult(5, 1))
+				mock.ExpectExec(insertItemAttribute).WithArgs(126, 5).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(deleteItemTranslations).WithArgs(126).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(insertItemTranslation).
+					WithArgs(126, "fr", "Baie Ceriz", "Soigne la paralysie.").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock, closeDB := newTestRepository(t)
+			defer closeDB()
+
+			tt.mockCall(mock)
+
+			if err := r.CreateItem(context.Background(), item); (err != nil) != tt.wantErr {
+				t.Errorf("CreateItem() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("CreateItem() %v", err)
+			}
+		})
+	}
+}
+
+func Test_repository_FetchItemCatalogue(t *testing.T) {
+	r, mock, closeDB := newTestRepository(t)
+	defer closeDB()
+
+	mock.ExpectQuery(getItemCatalogue).WillReturnRows(mock.NewRows([]string{"id", "name", "cost", "category", "attribute"}).
+		AddRow(17, "potion", 300, "healing", "countable").
+		AddRow(17, "potion", 300, "healing", "holdable").
+		AddRow(999, "unknown", 0, "misc", nil))
+
+	got, err := r.FetchItemCatalogue(context.Background())
+	if err != nil {
+		t.Errorf("FetchItemCatalogue() error = %v", err)
+		return
+	}
+	want := &model.ItemCatalogueResponse{Items: []model.ItemSummary{
+		{ID: 17, Name: "potion", Cost: 300, Category: "healing", Attributes: []string{"countable", "holdable"}},
+		{ID: 999, Name: "unknown", Category: "misc", Attributes: []string{}},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FetchItemCatalogue() got = %v, want %v", got, want)
+	}
+}
+
+func Test_repository_FetchItem(t *testing.T) {
+	columns := []string{"id", "name", "cost", "fling_power", "fling_effect_id", "fling_effect", "category_id", "category", "effect", "sprite", "berry"}
+
+	tests := []struct {
+		name     string
+		want     *model.Item
+		wantErr  error
+		mockCall func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name:    "given no rows should return nil and ErrNotFound",
+			want:    nil,
+			wantErr: model.ErrNotFound,
+			mockCall: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(getItem).WithArgs("cheri-berry").WillReturnRows(mock.NewRows(columns))
+			},
+		},
+		{
+			name: "given a berry item should return it with its berry and attributes",
+			want: &model.Item{
+				ID:          126,
+				Name:        "cheri-berry",
+				Cost:        20,
+				FlingPower:  10,
+				FlingEffect: &model.ItemRef{ID: 3, Name: "berry-effect"},
+				Category:    model.ItemRef{ID: 3, Name: "medicine"},
+				Attributes:  []model.ItemRef{{ID: 5, Name: "holdable"}},
+				Effect:      "Cures paralysis.",
+				Sprite:      "cheri-berry.png",
+				Berry:       "cheri",
+			},
+			mockCall: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(getItem).WithArgs("cheri-berry").WillReturnRows(mock.NewRows(columns).
+					AddRow(126, "cheri-berry", 20, 10, 3, "berry-effect", 3, "medicine", "Cures paralysis.", "cheri-berry.png", "cheri"))
+				mock.ExpectQuery(getItemAttributes).WithArgs(126).WillReturnRows(mock.NewRows([]string{"id", "name"}).
+					AddRow(5, "holdable"))
+			},
+		},
+		{
+			name: "given an item without fling effect nor berry should leave them empty",
+			want: &model.Item{
+				ID:         1,
+				Name:       "master-ball",
+				Category:   model.ItemRef{ID: 34, Name: "standard-balls"},
+				Attributes: []model.ItemRef{},
+			},
+			mockCall: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(getItem).WithArgs("cheri-berry").WillReturnRows(mock.NewRows(columns).
+					AddRow(1, "master-ball", 0, 0, nil, nil, 34, "standard-balls", "", "", nil))
+				mock.ExpectQuery(getItemAttributes).WithArgs(1).WillReturnRows(mock.NewRows([]string{"id", "name"}))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock, closeDB := newTestRepository(t)
+			defer closeDB()
+
+			tt.mockCall(mock)
+
+			got, err := r.FetchItem(context.Background(), "cheri-berry")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("FetchItem() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FetchItem() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/go-redis/redis/v7"
+	"github.com/inasknh/simple-poke-app/internal/cache"
+	"github.com/inasknh/simple-poke-app/internal/config"
+	"github.com/inasknh/simple-poke-app/internal/model"
+	"log"
+	"time"
+)
+
+// invalidationChannel is the Redis pub/sub channel, under the configured
+// prefix, replicas use to evict each other's in-process caches.
+const invalidationChannel = "cache:invalidate"
+
+// localRedisRepository keeps recently read listings in an in-process LRU in
+// front of another RedisRepository. Writes and invalidations are broadcast
+// so every replica drops its local copy.
+type localRedisRepository struct {
+	RedisRepository
+	cache   redis.UniversalClient
+	local   *cache.LRU[string, *model.BerriesResponse]
+	id      string
+	channel string
+}
+
+// NewLocalRedisRepository wraps next with an in-process cache sized by
+// config.LocalSize and listens for invalidations until ctx is done.
+func NewLocalRedisRepository(ctx context.Context, next RedisRepository, rdb redis.UniversalClient, config config.Cache) RedisRepository {
+	r := &localRedisRepository{
+		RedisRepository: next,
+		cache:           rdb,
+		local:           cache.NewLRU[string, *model.BerriesResponse](config.LocalSize, time.Duration(config.LocalTTL)*time.Second),
+		id:              replicaID(),
+		channel:         config.Prefix + invalidationChannel,
+	}
+
+	pubsub := rdb.Subscribe(r.channel)
+	go r.listen(ctx, pubsub)
+
+	return r
+}
+
+func (r *localRedisRepository) GetData(ctx context.Context, lang string) (*model.BerriesResponse, bool, error) {
+	if res, ok := r.local.Get(itemsKey(lang)); ok {
+		return res, false, nil
+	}
+
+	res, stale, err := r.RedisRepository.GetData(ctx, lang)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// stale entries are not kept locally so the refreshed value is picked up
+	if res != nil && !stale {
+		r.local.Set(itemsKey(lang), res)
+	}
+
+	return res, stale, nil
+}
+
+func (r *localRedisRepository) SetData(ctx context.Context, lang string, response *model.BerriesResponse) error {
+	if err := r.RedisRepository.SetData(ctx, lang, response); err != nil {
+		return err
+	}
+
+	r.publish()
+	r.local.Set(itemsKey(lang), response)
+
+	return nil
+}
+
+func (r *localRedisRepository) DeleteData(ctx context.Context) error {
+	r.local.Purge()
+	if err := r.RedisRepository.DeleteData(ctx); err != nil {
+		return err
+	}
+
+	r.publish()
+
+	return nil
+}
+
+// DeleteKey evicts only key locally, listings are kept under their cache
+// key and every other key is never held in the local cache.
+func (r *localRedisRepository) DeleteKey(ctx context.Context, key string) (bool, error) {
+	r.local.Delete(key)
+	deleted, err := r.RedisRepository.DeleteKey(ctx, key)
+	if err != nil {
+		return false, err
+	}
+
+	r.publish()
+
+	return deleted, nil
+}
+
+func (r *localRedisRepository) Purge(ctx context.Context) (int, error) {
+	r.local.Purge()
+	purged, err := r.RedisRepository.Purge(ctx)
+	if err != nil {
+		return purged, err
+	}
+
+	r.publish()
+
+	return purged, nil
+}
+
+// publish tells the other replicas to drop their local copies. A failure
+// only delays their refresh until the local TTL, so it is logged and ignored.
+func (r *localRedisRepository) publish() {
+	if err := r.cache.Publish(r.channel, r.id).Err(); err != nil {
+		log.Printf("failed to publish cache invalidation: %v", err)
+	}
+}
+
+func (r *localRedisRepository) listen(ctx context.Context, pubsub *redis.PubSub) {
+	defer func() {
+		_ = pubsub.Close()
+	}()
+
+	ch := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			r.handleInvalidation(msg.Payload)
+		}
+	}
+}
+
+// handleInvalidation purges the local cache unless the message was sent by this replica.
+func (r *localRedisRepository) handleInvalidation(sender string) {
+	if sender == r.id {
+		return
+	}
+	r.local.Purge()
+}
+
+func replicaID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redismock/v7"
+	"github.com/inasknh/simple-poke-app/internal/cache"
+	mocks "github.com/inasknh/simple-poke-app/internal/mocks/repository"
+	"github.com/inasknh/simple-poke-app/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func newTestLocalRedisRepository(next RedisRepository) (*localRedisRepository, redismock.ClientMock) {
+	rd, rmock := redismock.NewClientMock()
+	return &localRedisRepository{
+		RedisRepository: next,
+		cache:           rd,
+		local:           cache.NewLRU[string, *model.BerriesResponse](4, time.Minute),
+		id:              "replica-a",
+		channel:         invalidationChannel,
+	}, rmock
+}
+
+func Test_localRedisRepository_GetData(t *testing.T) {
+	expected := &model.BerriesResponse{Berries: []model.Berry{
+		{
+			Name: "1",
+			URL:  "1",
+		},
+	}}
+
+	t.Run("given fresh entry in redis should serve the next read locally", func(t *testing.T) {
+		next := &mocks.RedisRepository{}
+		next.On("GetData", mock.Anything, "en").Return(expected, false, nil).Once()
+		repo, _ := newTestLocalRedisRepository(next)
+
+		for i := 0; i < 3; i++ {
+			got, stale, err := repo.GetData(context.Background(), "en")
+			assert.NoError(t, err)
+			assert.False(t, stale)
+			assert.Equal(t, expected, got)
+		}
+		next.AssertNumberOfCalls(t, "GetData", 1)
+	})
+
+	t.Run("given stale entry in redis should not keep it locally", func(t *testing.T) {
+		next := &mocks.RedisRepository{}
+		next.On("GetData", mock.Anything, "en").Return(expected, true, nil)
+		repo, _ := newTestLocalRedisRepository(next)
+
+		for i := 0; i < 2; i++ {
+			got, stale, err := repo.GetData(context.Background(), "en")
+			assert.NoError(t, err)
+			assert.True(t, stale)
+			assert.Equal(t, expected, got)
+		}
+		next.AssertNumberOfCalls(t, "GetData", 2)
+	})
+
+	t.Run("given an error from redis should return the error", func(t *testing.T) {
+		next := &mocks.RedisRepository{}
+		next.On("GetData", mock.Anything, "en").Return(nil, false, errors.New("an error"))
+		repo, _ := newTestLocalRedisRepository(next)
+
+		got, _, err := repo.GetData(context.Background(), "en")
+		assert.Error(t, err)
+		assert.Nil(t, got)
+	})
+}
+
+func Test_localRedisRepository_SetDataAndDeleteData(t *testing.T) {
+	expected := &model.BerriesResponse{Berries: []model.Berry{
+		{
+			Name: "1",
+			URL:  "1",
+		},
+	}}
+
+	next := &mocks.RedisRepository{}
+	next.On("SetData", mock.Anything, "en", expected).Return(nil)
+	next.On("DeleteData", mock.Anything).Return(nil)
+	next.On("GetData", mock.Anything, "en").Return(nil, false, nil)
+	repo, rmock := newTestLocalRedisRepository(next)
+
+	rmock.ExpectPublish(invalidationChannel, "replica-a").SetVal(1)
+	err := repo.SetData(context.Background(), "en", expected)
+	assert.NoError(t, err)
+
+	got, _, err := repo.GetData(context.Background(), "en")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, got)
+
+	rmock.ExpectPublish(invalidationChannel, "replica-a").SetVal(1)
+	err = repo.DeleteData(context.Background())
+	assert.NoError(t, err)
+
+	got, _, err = repo.GetData(context.Background(), "en")
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+	assert.NoError(t, rmock.ExpectationsWereMet())
+}
+
+func Test_localRedisRepository_handleInvalidation(t *testing.T) {
+	repo, _ := newTestLocalRedisRepository(&mocks.RedisRepository{})
+	repo.local.Set("items:en", &model.BerriesResponse{})
+
+	// own messages keep the local copy
+	repo.handleInvalidation("replica-a")
+	assert.Equal(t, 1, repo.local.Len())
+
+	repo.handleInvalidation("replica-b")
+	assert.Equal(t, 0, repo.local.Len())
+}
+
+func Test_localRedisRepository_Purge(t *testing.T) {
+	next := &mocks.RedisRepository{}
+	next.On("DeleteKey", mock.Anything, "items:en").Return(true, nil)
+	next.On("DeleteKey", mock.Anything, "berry:cheri").Return(true, nil)
+	next.On("Purge", mock.Anything).Return(3, nil)
+	repo, rmock := newTestLocalRedisRepository(next)
+
+	repo.local.Set("items:en", &model.BerriesResponse{})
+	repo.local.Set("items:fr", &model.BerriesResponse{})
+	rmock.ExpectPublish(invalidationChannel, "replica-a").SetVal(1)
+	deleted, err := repo.DeleteKey(context.Background(), "items:en")
+	assert.NoError(t, err)
+	assert.True(t, deleted)
+	_, ok := repo.local.Get("items:en")
+	assert.False(t, ok)
+	_, ok = repo.local.Get("items:fr")
+	assert.True(t, ok)
+
+	// keys other than listings are not held locally
+	rmock.ExpectPublish(invalidationChannel, "replica-a").SetVal(1)
+	deleted, err = repo.DeleteKey(context.Background(), "berry:cheri")
+	assert.NoError(t, err)
+	assert.True(t, deleted)
+	assert.Equal(t, 1, repo.local.Len())
+
+	repo.local.Set("items:en", &model.BerriesResponse{})
+	rmock.ExpectPublish(invalidationChannel, "replica-a").SetVal(1)
+	purged, err := repo.Purge(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 3, purged)
+	assert.Equal(t, 0, repo.local.Len())
+	assert.NoError(t, rmock.ExpectationsWereMet())
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/inasknh/simple-poke-app/internal/model"
+)
+
+// pickyHealingCategory is the item category of the berries confusing a
+// Pokémon that dislikes their flavor.
+const pickyHealingCategory = "picky-healing"
+
+const (
+	upsertNature = "INSERT INTO natures (id, name, increased_stat, decreased_stat, likes_flavor_id, hates_flavor_id) " +
+		"VALUES (?, ?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE name = VALUES(name), increased_stat = VALUES(increased_stat), " +
+		"decreased_stat = VALUES(decreased_stat), likes_flavor_id = VALUES(likes_flavor_id), hates_flavor_id = VALUES(hates_flavor_id)"
+
+	getNature = "SELECT n.id, n.name, n.increased_stat, n.decreased_stat, l.id, l.name, h.id, h.name FROM natures n " +
+		"LEFT JOIN flavors l ON l.id = n.likes_flavor_id LEFT JOIN flavors h ON h.id = n.hates_flavor_id WHERE n.name = ?"
+	getFlavorNames    = "SELECT name FROM flavors ORDER BY id"
+	getFlavorProfiles = "SELECT b.name, f.name, bf.potency, c.name FROM berries b " +
+		"LEFT JOIN berry_flavors bf ON bf.berry_id = b.id LEFT JOIN flavors f ON f.id = bf.flavor_id " +
+		"LEFT JOIN items i ON i.id = b.item_id LEFT JOIN item_categories c ON c.id = i.category_id " +
+		"ORDER BY b.id, f.id"
+)
+
+func (r *repository) CreateNature(ctx context.Context, nature model.Nature) error {
+	return r.withTx(ctx, func(tx *sql.Tx) error {
+		// the flavors may not be synced yet
+		for _, flavor := range []model.Flavor{{ID: nature.LikesFlavorID, Name: nature.LikesFlavor}, {ID: nature.HatesFlavorID, Name: nature.HatesFlavor}} {
+			if flavor.ID == 0 {
+				continue
+			}
+			if _, err := tx.ExecContext(ctx, upsertFlavor, flavor.ID, flavor.Name); err != nil {
+				return fmt.Errorf("failed to insert flavor: %w", err)
+			}
+		}
+
+		_, err := tx.ExecContext(ctx, upsertNature,
+			nature.ID,
+			nature.Name,
+			nullString(nature.IncreasedStat),
+			nullString(nature.DecreasedStat),
+			nullInt(nature.LikesFlavorID),
+			nullInt(nature.HatesFlavorID),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert nature: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (r *repository) FetchNature(ctx context.Context, name string) (*model.Nature, error) {
+	var nature model.Nature
+	var increased, decreased, likes, hates sql.NullString
+	var likesID, hatesID sql.NullInt64
+	err := r.db.QueryRowContext(ctx, getNature, name).Scan(
+		&nature.ID,
+		&nature.Name,
+		&increased,
+		&decreased,
+		&likesID,
+		&likes,
+		&hatesID,
+		&hates,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrNotFound
+		}
+		return nil, err
+	}
+
+	nature.IncreasedStat = increased.String
+	nature.DecreasedStat = decreased.String
+	nature.LikesFlavorID = int(likesID.Int64)
+	nature.LikesFlavor = likes.String
+	nature.HatesFlavorID = int(hatesID.Int64)
+	nature.HatesFlavor = hates.String
+
+	return &nature, nil
+}
+
+func (r *repository) FetchFlavorProfiles(ctx context.Context) (*model.FlavorProfiles, error) {
+	res := &model.FlavorProfiles{Flavors: []string{}, Berries: []model.FlavorProfile{}}
+	err := r.queryEach(ctx, getFlavorNames, nil, func(rows *sql.Rows) error {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return err
+		}
+		res.Flavors = append(res.Flavors, name)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.queryEach(ctx, getFlavorProfiles, nil, func(rows *sql.Rows) error {
+		var berry string
+		var flavor, category sql.NullString
+		var potency sql.NullInt64
+		if err := rows.Scan(&berry, &flavor, &potency, &category); err != nil {
+			return err
+		}
+
+		// rows are ordered by berry, a new name starts the next one
+		if len(res.Berries) == 0 || res.Berries[len(res.Berries)-1].Berry != berry {
+			res.Berries = append(res.Berries, model.FlavorProfile{
+				Berry:    berry,
+				Potency:  map[string]int{},
+				Confuses: category.String == pickyHealingCategory,
+			})
+		}
+		if flavor.Valid {
+			res.Berries[len(res.Berries)-1].Potency[flavor.String] = int(potency.Int64)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func nullInt(n int) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(n), Valid: n != 0}
+}
@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/inasknh/simple-poke-app/internal/model"
+	"reflect"
+	"testing"
+)
+
+func Test_repository_CreateNature(t *testing.T) {
+	tests := []struct {
+		name     string
+		nature   model.Nature
+		wantErr  bool
+		mockCall func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name: "given an error when upserting a flavor should rollback and return an error",
+			nature: model.Nature{
+				ID: 11, Name: "adamant", IncreasedStat: "attack", DecreasedStat: "special-attack",
+				LikesFlavorID: 1, LikesFlavor: "spicy", HatesFlavorID: 2, HatesFlavor: "dry",
+			},
+			wantErr: true,
+			mockCall: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(upsertFlavor).WithArgs(1, "spicy").WillReturnError(errors.New("any error"))
+				mock.ExpectRollback()
+			},
+		},
+		{
+			name: "given a nature with preferences should upsert its flavors then itself",
+			nature: model.Nature{
+				ID: 11, Name: "adamant", IncreasedStat: "attack", DecreasedStat: "special-attack",
+				LikesFlavorID: 1, LikesFlavor: "spicy", HatesFlavorID: 2, HatesFlavor: "dry",
+			},
+			mockCall: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(upsertFlavor).WithArgs(1, "spicy").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(upsertFlavor).WithArgs(2, "dry").WillReturnResult(sqlmock.NewResult(2, 1))
+				mock.ExpectExec(upsertNature).WithArgs(11, "adamant", "attack", "special-attack", 1, 2).WillReturnResult(sqlmock.NewResult(11, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:   "given a neutral nature should store it without preferences",
+			nature: model.Nature{ID: 1, Name: "hardy"},
+			mockCall: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(upsertNature).WithArgs(1, "hardy", nil, nil, nil, nil).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock, closeDB := newTestRepository(t)
+			defer closeDB()
+
+			tt.mockCall(mock)
+
+			if err := r.CreateNature(context.Background(), tt.nature); (err != nil) != tt.wantErr {
+				t.Errorf("CreateNature() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("CreateNature() %v", err)
+			}
+		})
+	}
+}
+
+func Test_repository_FetchNature(t *testing.T) {
+	columns := []string{"id", "name", "increased_stat", "decreased_stat", "likes_id", "likes", "hates_id", "hates"}
+
+	tests := []struct {
+		name     string
+		want     *model.Nature
+		wantErr  error
+		mockCall func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name:    "given no rows should return ErrNotFound",
+			wantErr: model.ErrNotFound,
+			mockCall: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(getNature).WithArgs("adamant").WillReturnRows(mock.NewRows(columns))
+			},
+		},
+		{
+			name: "given a nature with preferences should return them",
+			want: &model.Nature{
+				ID: 11, Name: "adamant", IncreasedStat: "attack", DecreasedStat: "special-attack",
+				LikesFlavorID: 1, LikesFlavor: "spicy", HatesFlavorID: 2, HatesFlavor: "dry",
+			},
+			mockCall: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(getNature).WithArgs("adamant").
+					WillReturnRows(mock.NewRows(columns).AddRow(11, "adamant", "attack", "special-attack", 1, "spicy", 2, "dry"))
+			},
+		},
+		{
+			name: "given a neutral nature should return it without preferences",
+			want: &model.Nature{ID: 1, Name: "adamant"},
+			mockCall: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(getNature).WithArgs("adamant").
+					WillReturnRows(mock.NewRows(columns).AddRow(1, "adamant", nil, nil, nil, nil, nil, nil))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock, closeDB := newTestRepository(t)
+			defer closeDB()
+
+			tt.mockCall(mock)
+
+			got, err := r.FetchNature(context.Background(), "adamant")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("FetchNature() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FetchNature() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_repository_FetchFlavorProfiles(t *testing.T) {
+	r, mock, closeDB := newTestRepository(t)
+	defer closeDB()
+
+	mock.ExpectQuery(getFlavorNames).WillReturnRows(mock.NewRows([]string{"name"}).AddRow("spicy").AddRow("dry"))
+	mock.ExpectQuery(getFlavorProfiles).WillReturnRows(mock.NewRows([]string{"berry", "flavor", "potency", "category"}).
+		AddRow("cheri", "spicy", 10, "medicine").
+		AddRow("figy", "spicy", 15, "picky-healing").
+		AddRow("leppa", "spicy", 10, nil).
+		AddRow("leppa", "dry", 10, nil).
+		AddRow("enigma", nil, nil, nil))
+
+	got, err := r.FetchFlavorProfiles(context.Background())
+	if err != nil {
+		t.Fatalf("FetchFlavorProfiles() error = %v", err)
+	}
+
+	want := &model.FlavorProfiles{
+		Flavors: []string{"spicy", "dry"},
+		Berries: []model.FlavorProfile{
+			{Berry: "cheri", Potency: map[string]int{"spicy": 10}},
+			{Berry: "figy", Potency: map[string]int{"spicy": 15}, Confuses: true},
+			{Berry: "leppa", Potency: map[string]int{"spicy": 10, "dry": 10}},
+			{Berry: "enigma", Potency: map[string]int{}},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FetchFlavorProfiles() got = %v, want %v", got, want)
+	}
+
+	mock.ExpectQuery(getFlavorNames).WillReturnError(errors.New("any error"))
+	if _, err = r.FetchFlavorProfiles(context.Background()); err == nil {
+		t.Errorf("FetchFlavorProfiles() expected an error")
+	}
+}
@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/inasknh/simple-poke-app/internal/model"
+)
+
+const (
+	upsertPokemon = "INSERT INTO pokemon (id, name, species, height, weight, base_experience, capture_rate, is_legendary, is_mythical, sprite) " +
+		"VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE name = VALUES(name), species = VALUES(species), " +
+		"height = VALUES(height), weight = VALUES(weight), base_experience = VALUES(base_experience), " +
+		"capture_rate = VALUES(capture_rate), is_legendary = VALUES(is_legendary), is_mythical = VALUES(is_mythical), sprite = VALUES(sprite)"
+	upsertType             = "INSERT INTO types (id, name) VALUES (?, ?) ON DUPLICATE KEY UPDATE name = VALUES(name)"
+	upsertAbility          = "INSERT INTO abilities (id, name) VALUES (?, ?) ON DUPLICATE KEY UPDATE name = VALUES(name)"
+	deletePokemonTypes     = "DELETE FROM pokemon_types WHERE pokemon_id = ?"
+	insertPokemonType      = "INSERT INTO pokemon_types (pokemon_id, type_id, slot) VALUES (?, ?, ?)"
+	deletePokemonAbilities = "DELETE FROM pokemon_abilities WHERE pokemon_id = ?"
+	insertPokemonAbility   = "INSERT INTO pokemon_abilities (pokemon_id, ability_id, slot, is_hidden) VALUES (?, ?, ?, ?)"
+	deletePokemonStats     = "DELETE FROM pokemon_stats WHERE pokemon_id = ?"
+	insertPokemonStat      = "INSERT INTO pokemon_stats (pokemon_id, stat, slot, base_stat, effort) VALUES (?, ?, ?, ?, ?)"
+	deletePokemonNames     = "DELETE FROM pokemon_names WHERE pokemon_id = ?"
+	insertPokemonName      = "INSERT INTO pokemon_names (pokemon_id, language, name) VALUES (?, ?, ?)"
+
+	getPokemonList = "SELECT p.id, p.name, t.name FROM pokemon p " +
+		"LEFT JOIN pokemon_types pt ON pt.pokemon_id = p.id LEFT JOIN types t ON t.id = pt.type_id ORDER BY p.id, pt.slot"
+	getPokemon = "SELECT id, name, species, height, weight, base_experience, capture_rate, is_legendary, is_mythical, sprite " +
+		"FROM pokemon WHERE name = ? LIMIT 1"
+	getPokemonTypes = "SELECT t.id, t.name, pt.slot FROM pokemon_types pt JOIN types t ON t.id = pt.type_id " +
+		"WHERE pt.pokemon_id = ? ORDER BY pt.slot"
+	getPokemonAbilities = "SELECT a.id, a.name, pa.slot, pa.is_hidden FROM pokemon_abilities pa JOIN abilities a ON a.id = pa.ability_id " +
+		"WHERE pa.pokemon_id = ? ORDER BY pa.slot"
+	getPokemonStats = "SELECT stat, base_stat, effort FROM pokemon_stats WHERE pokemon_id = ? ORDER BY slot"
+)
+
+func (r *repository) CreatePokemon(ctx context.Context, pokemon model.Pokemon) error {
+	return r.withTx(ctx, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, upsertPokemon,
+			pokemon.ID,
+			pokemon.Name,
+			pokemon.Species,
+			pokemon.Height,
+			pokemon.Weight,
+			pokemon.BaseExperience,
+			pokemon.CaptureRate,
+			pokemon.IsLegendary,
+			pokemon.IsMythical,
+			pokemon.Sprite,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert pokemon: %w", err)
+		}
+
+		for _, query := range []string{deletePokemonTypes, deletePokemonAbilities, deletePokemonStats, deletePokemonNames} {
+			if _, err = tx.ExecContext(ctx, query, pokemon.ID); err != nil {
+				return fmt.Errorf("failed to clear pokemon links: %w", err)
+			}
+		}
+
+		for _, t := range pokemon.Types {
+			if _, err = tx.ExecContext(ctx, upsertType, t.ID, t.Name); err != nil {
+				return fmt.Errorf("failed to insert type: %w", err)
+			}
+			if _, err = tx.ExecContext(ctx, insertPokemonType, pokemon.ID, t.ID, t.Slot); err != nil {
+				return fmt.Errorf("failed to link type: %w", err)
+			}
+		}
+
+		for _, a := range pokemon.Abilities {
+			if _, err = tx.ExecContext(ctx, upsertAbility, a.ID, a.Name); err != nil {
+				return fmt.Errorf("failed to insert ability: %w", err)
+			}
+			if _, err = tx.ExecContext(ctx, insertPokemonAbility, pokemon.ID, a.ID, a.Slot, a.IsHidden); err != nil {
+				return fmt.Errorf("failed to link ability: %w", err)
+			}
+		}
+
+		for i, stat := range pokemon.Stats {
+			if _, err = tx.ExecContext(ctx, insertPokemonStat, pokemon.ID, stat.Name, i+1, stat.BaseStat, stat.Effort); err != nil {
+				return fmt.Errorf("failed to insert stat: %w", err)
+			}
+		}
+
+		for _, name := range pokemon.Names {
+			if _, err = tx.ExecContext(ctx, insertPokemonName, pokemon.ID, name.Language, name.Name); err != nil {
+				return fmt.Errorf("failed to insert pokemon name: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r *repository) FetchPokemonList(ctx context.Context) (*model.PokemonListResponse, error) {
+	rows, err := r.db.QueryContext(ctx, getPokemonList)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := []model.PokemonSummary{}
+	for rows.Next() {
+		var p model.PokemonSummary
+		var typeName sql.NullString
+		if err = rows.Scan(&p.ID, &p.Name, &typeName); err != nil {
+			return nil, err
+		}
+
+		// rows are ordered by Pokémon, a new id starts the next one
+		if len(res) == 0 || res[len(res)-1].ID != p.ID {
+			p.Types = []string{}
+			res = append(res, p)
+		}
+		if typeName.Valid {
+			last := &res[len(res)-1]
+			last.Types = append(last.Types, typeName.String)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &model.PokemonListResponse{Pokemon: res}, nil
+}
+
+func (r *repository) FetchPokemon(ctx context.Context, name string) (*model.Pokemon, error) {
+	var p model.Pokemon
+	err := r.db.QueryRowContext(ctx, getPokemon, name).Scan(
+		&p.ID,
+		&p.Name,
+		&p.Species,
+		&p.Height,
+		&p.Weight,
+		&p.BaseExperience,
+		&p.CaptureRate,
+		&p.IsLegendary,
+		&p.IsMythical,
+		&p.Sprite,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrNotFound
+		}
+		return nil, err
+	}
+
+	p.Types = []model.PokemonType{}
+	err = r.queryEach(ctx, getPokemonTypes, []interface{}{p.ID}, func(rows *sql.Rows) error {
+		var t model.PokemonType
+		if err := rows.Scan(&t.ID, &t.Name, &t.Slot); err != nil {
+			return err
+		}
+		p.Types = append(p.Types, t)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	p.Abilities = []model.PokemonAbility{}
+	err = r.queryEach(ctx, getPokemonAbilities, []interface{}{p.ID}, func(rows *sql.Rows) error {
+		var a model.PokemonAbility
+		if err := rows.Scan(&a.ID, &a.Name, &a.Slot, &a.IsHidden); err != nil {
+			return err
+		}
+		p.Abilities = append(p.Abilities, a)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	p.Stats = []model.PokemonStat{}
+	err = r.queryEach(ctx, getPokemonStats, []interface{}{p.ID}, func(rows *sql.Rows) error {
+		var s model.PokemonStat
+		if err := rows.Scan(&s.Name, &s.BaseStat, &s.Effort); err != nil {
+			return err
+		}
+		p.Stats = append(p.Stats, s)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// queryEach runs query and calls scan for every row.
+func (r *repository) queryEach(ctx context.Context, query string, args []interface{}, scan func(rows *sql.Rows) error) error {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err = scan(rows); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/inasknh/simple-poke-app/internal/model"
+	"reflect"
+	"testing"
+)
+
+func newTestRepository(t *testing.T) (*repository, sqlmock.Sqlmock, func()) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	return &repository{db: db}, mock, func() {
+		_ = db.Close()
+	}
+}
+
+func Test_repository_CreatePokemon(t *testing.T) {
+	pokemon := model.Pokemon{
+		ID:      1,
+		Name:    "bulbasaur",
+		Species: "bulbasaur",
+		Sprite:  "1.png",
+		Types: []model.PokemonType{
+			{
+				ID:   12,
+				Name: "grass",
+				Slot: 1,
+			},
+		},
+		Abilities: []model.PokemonAbility{
+			{
+				ID:       34,
+				Name:     "chlorophyll",
+				Slot:     3,
+				IsHidden: true,
+			},
+		},
+		Stats: []model.PokemonStat{
+			{
+				Name:     "hp",
+				BaseStat: 45,
+			},
+		},
+		Names: []model.Translation{{Language: "fr", Name: "Bulbizarre"}},
+	}
+
+	tests := []struct {
+		name     string
+		wantErr  bool
+		mockCall func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name:    "given an error when upserting the pokemon should rollback and return an error",
+			wantErr: true,
+			mockCall: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(upsertPokemon).WillReturnError(errors.New("any error"))
+				mock.ExpectRollback()
+			},
+		},
+		{
+			name:    "given happy flow should replace the links and commit",
+			wantErr: false,
+			mockCall: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(upsertPokemon).
+					WithArgs(1, "bulbasaur", "bulbasaur", 0, 0, 0, 0, false, false, "1.png").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(deletePokemonTypes).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(deletePokemonAbilities).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(deletePokemonStats).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(deletePokemonNames).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(upsertType).WithArgs(12, "grass").WillReturnResult(sqlmock.NewResult(12, 1))
+				mock.ExpectExec(insertPokemonType).WithArgs(1, 12, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(upsertAbility).WithArgs(34, "chlorophyll").WillReturnResult(sqlmock.NewResult(34, 1))
+				mock.ExpectExec(insertPokemonAbility).WithArgs(1, 34, 3, true).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(insertPokemonStat).WithArgs(1, "hp", 1, 45, 0).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(insertPokemonName).WithArgs(1, "fr", "Bulbizarre").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock, closeDB := newTestRepository(t)
+			defer closeDB()
+
+			tt.mockCall(mock)
+
+			if err := r.CreatePokemon(context.Background(), pokemon); (err != nil) != tt.wantErr {
+				t.Errorf("CreatePokemon() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("CreatePokemon() %v", err)
+			}
+		})
+	}
+}
+
+func Test_repository_FetchPokemonList(t *testing.T) {
+	r, mock, closeDB := newTestRepository(t)
+	defer closeDB()
+
+	mock.ExpectQuery(getPokemonList).WillReturnRows(mock.NewRows([]string{"id", "name", "type"}).
+		AddRow(1, "bulbasaur", "grass").
+		AddRow(1, "bulbasaur", "poison").
+		AddRow(4, "charmander", "fire").
+		AddRow(7, "squirtle", nil))
+
+	got, err := r.FetchPokemonList(context.Background())
+	if err != nil {
+		t.Errorf("FetchPokemonList() error = %v", err)
+		return
+	}
+	want := &model.PokemonListResponse{Pokemon: []model.PokemonSummary{
+		{ID: 1, Name: "bulbasaur", Types: []string{"grass", "poison"}},
+		{ID: 4, Name: "charmander", Types: []string{"fire"}},
+		{ID: 7, Name: "squirtle", Types: []string{}},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FetchPokemonList() got = %v, want %v", got, want)
+	}
+}
+
+func Test_repository_FetchPokemon(t *testing.T) {
+	columns := []string{"id", "name", "species", "height", "weight", "base_experience", "capture_rate", "is_legendary", "is_mythical", "sprite"}
+
+	tests := []struct {
+		name     string
+		want     *model.Pokemon
+		wantErr  error
+		mockCall func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name:    "given no rows should return nil and ErrNotFound",
+			want:    nil,
+			wantErr: model.ErrNotFound,
+			mockCall: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(getPokemon).WithArgs("bulbasaur").WillReturnRows(mock.NewRows(columns))
+			},
+		},
+		{
+			name: "given happy flow should return pokemon with its links",
+			want: &model.Pokemon{
+				ID:             1,
+				Name:           "bulbasaur",
+				Species:        "bulbasaur",
+				Height:         7,
+				Weight:         69,
+				BaseExperience: 64,
+				CaptureRate:    45,
+				Sprite:         "1.png",
+				Types:          []model.PokemonType{{ID: 12, Name: "grass", Slot: 1}},
+				Abilities:      []model.PokemonAbility{{ID: 65, Name: "overgrow", Slot: 1}},
+				Stats:          []model.PokemonStat{{Name: "hp", BaseStat: 45}},
+			},
+			wantErr: nil,
+			mockCall: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(getPokemon).WithArgs("bulbasaur").WillReturnRows(mock.NewRows(columns).
+					AddRow(1, "bulbasaur", "bulbasaur", 7, 69, 64, 45, false, false, "1.png"))
+				mock.ExpectQuery(getPokemonTypes).WithArgs(1).WillReturnRows(mock.NewRows([]string{"id", "name", "slot"}).AddRow(12, "grass", 1))
+				mock.ExpectQuery(getPokemonAbilities).WithArgs(1).WillReturnRows(mock.NewRows([]string{"id", "name", "slot", "is_hidden"}).AddRow(65, "overgrow", 1, false))
+				mock.ExpectQuery(getPokemonStats).WithArgs(1).WillReturnRows(mock.NewRows([]string{"stat", "base_stat", "effort"}).AddRow("hp", 45, 0))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock, closeDB := newTestRepository(t)
+			defer closeDB()
+
+			tt.mockCall(mock)
+
+			got, err := r.FetchPokemon(context.Background(), "bulbasaur")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("FetchPokemon() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FetchPokemon() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,55 +2,367 @@ package repository
 
 import (
 	"context"
-	"encoding/json"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"github.com/go-redis/redis/v7"
 	"github.com/inasknh/simple-poke-app/internal/config"
 	"github.com/inasknh/simple-poke-app/internal/model"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// itemsKeyPrefix holds the cached /items listing of every language.
+const itemsKeyPrefix = "items:"
+
 type redisRepository struct {
-	cache  *redis.Client
+	cache  redis.UniversalClient
 	config config.Configurations
+	codec  cacheCodec
+	now    func() time.Time
 }
 
-func NewRedisRepository(cache *redis.Client, config config.Configurations) RedisRepository {
-	return &redisRepository{cache: cache, config: config}
+func NewRedisRepository(cache redis.UniversalClient, config config.Configurations) RedisRepository {
+	return &redisRepository{
+		cache:  cache,
+		config: config,
+		codec: cacheCodec{
+			compression: config.Cache.Compression,
+			threshold:   config.Cache.CompressionThreshold,
+		},
+		now: time.Now,
+	}
 }
 
+// ErrCorruptEntry is returned when a cached value cannot be decoded.
+var ErrCorruptEntry = errors.New("corrupt cache entry")
+
+// ErrNoPrefix is returned when walking the cache namespace without a prefix,
+// which would walk every key of a shared Redis.
+var ErrNoPrefix = errors.New("cache prefix is not configured")
+
+// scanCount is the number of keys requested per SCAN call.
+const scanCount = 100
+
+// releaseLockScript deletes a lock only when it is still held by the caller's token.
+const releaseLockScript = `if redis.call("get", KEYS[1]) == ARGV[1] then return redis.call("del", KEYS[1]) else return 0 end`
+
 type RedisRepository interface {
-	GetData(ctx context.Context) (*model.BerriesResponse, error)
-	SetData(ctx context.Context, response *model.BerriesResponse) error
+	// GetData returns the listing cached in lang and whether it is past its soft TTL.
+	GetData(ctx context.Context, lang string) (*model.BerriesResponse, bool, error)
+	SetData(ctx context.Context, lang string, response *model.BerriesResponse) error
+	// DeleteData invalidates the cached listing in every language.
+	DeleteData(ctx context.Context) error
+	// GetValue decodes the value cached under key into out and reports whether it was found.
+	GetValue(ctx context.Context, key string, out interface{}) (bool, error)
+	// SetValue caches value under key for the configured TTL.
+	SetValue(ctx context.Context, key string, value interface{}) error
+	// AcquireLock tries to take the named lock for ttl. It returns the lock
+	// token, or an empty token when another holder already has the lock.
+	AcquireLock(ctx context.Context, name string, ttl time.Duration) (string, error)
+	ReleaseLock(ctx context.Context, name string, token string) error
+	// ListKeys describes every key under the configured namespace, or
+	// returns ErrNoPrefix when there is none.
+	ListKeys(ctx context.Context) ([]model.CacheKey, error)
+	// DeleteKey removes key from the namespace and reports whether it existed.
+	DeleteKey(ctx context.Context, key string) (bool, error)
+	// Purge removes every key under the namespace and returns how many were
+	// removed, or returns ErrNoPrefix when there is no namespace.
+	Purge(ctx context.Context) (int, error)
 }
 
-func (r *redisRepository) GetData(ctx context.Context) (*model.BerriesResponse, error) {
-	res, err := r.cache.Get("items").Bytes()
+func (r *redisRepository) GetData(ctx context.Context, lang string) (*model.BerriesResponse, bool, error) {
+	rdb, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	res, err := rdb.Get(r.key(itemsKey(lang))).Bytes()
 	if err != nil {
 		if err == redis.Nil {
-			return nil, nil
+			return nil, false, nil
 		}
-		return nil, err
+		return nil, false, err
 	}
 
+	// the Redis TTL is the hard expiry, past the soft expiry the entry is stale
 	var berries model.BerriesResponse
-	err = json.Unmarshal(res, &berries)
+	softExpiresAt, ok, err := r.codec.decode(res, &berries)
 	if err != nil {
-		return nil, err
+		return nil, false, err
+	}
+	if !ok {
+		return nil, false, nil
 	}
 
-	return &berries, nil
+	return &berries, r.now().After(softExpiresAt), nil
 }
 
-func (r *redisRepository) SetData(ctx context.Context, response *model.BerriesResponse) error {
-	data, err := json.Marshal(response)
+func (r *redisRepository) SetData(ctx context.Context, lang string, response *model.BerriesResponse) error {
+	hardTTL := time.Duration(r.config.App.TTL) * time.Minute
+	softTTL := time.Duration(r.config.App.SoftTTL) * time.Minute
+	if softTTL <= 0 || softTTL > hardTTL {
+		softTTL = hardTTL
+	}
+
+	data, err := r.codec.encode(response, r.now().Add(softTTL))
 	if err != nil {
 		return err
 	}
 
-	_, err = r.cache.Set("items", data, time.Duration(r.config.App.TTL)*time.Minute).Result()
+	rdb, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	_, err = rdb.Set(r.key(itemsKey(lang)), data, hardTTL).Result()
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+func (r *redisRepository) DeleteData(ctx context.Context) error {
+	return r.scan(ctx, itemsKey("*"), func(node redis.UniversalClient, names []string) error {
+		_, err := r.del(ctx, node, names)
+		return err
+	})
+}
+
+func (r *redisRepository) GetValue(ctx context.Context, key string, out interface{}) (bool, error) {
+	rdb, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	res, err := rdb.Get(r.key(key)).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return false, nil
+		}
+		return false, err
+	}
+
+	_, ok, err := r.codec.decode(res, out)
+	if err != nil {
+		return false, err
+	}
+
+	return ok, nil
+}
+
+func (r *redisRepository) SetValue(ctx context.Context, key string, value interface{}) error {
+	ttl := time.Duration(r.config.App.TTL) * time.Minute
+	data, err := r.codec.encode(value, r.now().Add(ttl))
+	if err != nil {
+		return err
+	}
+
+	rdb, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	return rdb.Set(r.key(key), data, ttl).Err()
+}
+
+func (r *redisRepository) AcquireLock(ctx context.Context, name string, ttl time.Duration) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(b)
+
+	rdb, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	ok, err := rdb.SetNX(r.key(lockKey(name)), token, ttl).Result()
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", nil
+	}
+
+	return token, nil
+}
+
+func (r *redisRepository) ReleaseLock(ctx context.Context, name string, token string) error {
+	rdb, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	return rdb.Eval(releaseLockScript, []string{r.key(lockKey(name))}, token).Err()
+}
+
+func (r *redisRepository) ListKeys(ctx context.Context) ([]model.CacheKey, error) {
+	if r.config.Cache.Prefix == "" {
+		return nil, ErrNoPrefix
+	}
+
+	var mu sync.Mutex
+	keys := make([]model.CacheKey, 0)
+	err := r.scan(ctx, "*", func(node redis.UniversalClient, names []string) error {
+		for _, name := range names {
+			var ttl time.Duration
+			err := r.call(ctx, node, func(rdb redis.Cmdable) (err error) {
+				ttl, err = rdb.TTL(name).Result()
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			// the key expired or was removed since it was scanned
+			if ttl == -2 {
+				continue
+			}
+			var size int64
+			err = r.call(ctx, node, func(rdb redis.Cmdable) (err error) {
+				size, err = rdb.StrLen(name).Result()
+				return err
+			})
+			if err != nil {
+				return err
+			}
+
+			seconds := int64(-1)
+			if ttl >= 0 {
+				seconds = int64(ttl / time.Second)
+			}
+			mu.Lock()
+			keys = append(keys, model.CacheKey{
+				Key:  strings.TrimPrefix(name, r.config.Cache.Prefix),
+				TTL:  seconds,
+				Size: size,
+			})
+			mu.Unlock()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func (r *redisRepository) DeleteKey(ctx context.Context, key string) (bool, error) {
+	rdb, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	n, err := rdb.Del(r.key(key)).Result()
+	if err != nil {
+		return false, err
+	}
+
+	return n > 0, nil
+}
+
+func (r *redisRepository) Purge(ctx context.Context) (int, error) {
+	if r.config.Cache.Prefix == "" {
+		return 0, ErrNoPrefix
+	}
+
+	var purged int64
+	err := r.scan(ctx, "*", func(node redis.UniversalClient, names []string) error {
+		n, err := r.del(ctx, node, names)
+		atomic.AddInt64(&purged, n)
+		return err
+	})
+
+	return int(atomic.LoadInt64(&purged)), err
+}
+
+// scan calls fn with every batch of keys under the namespace matching
+// pattern and the node holding them. Only the SCAN itself is bounded by
+// the timeout, fn bounds each of its calls with call. In cluster mode every
+// master is scanned concurrently.
+func (r *redisRepository) scan(ctx context.Context, pattern string, fn func(node redis.UniversalClient, names []string) error) error {
+	if cluster, ok := r.cache.(*redis.ClusterClient); ok {
+		return cluster.ForEachMaster(func(node *redis.Client) error {
+			return r.scanNode(ctx, node, pattern, fn)
+		})
+	}
+
+	return r.scanNode(ctx, r.cache, pattern, fn)
+}
+
+func (r *redisRepository) scanNode(ctx context.Context, node redis.UniversalClient, pattern string, fn func(node redis.UniversalClient, names []string) error) error {
+	var cursor uint64
+	for {
+		var names []string
+		var next uint64
+		err := r.call(ctx, node, func(rdb redis.Cmdable) (err error) {
+			names, next, err = rdb.Scan(cursor, r.key(pattern), scanCount).Result()
+			return err
+		})
+		if err == nil && len(names) > 0 {
+			err = fn(node, names)
+		}
+		if err != nil {
+			return err
+		}
+
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+// key places name under the configured namespace prefix.
+func (r *redisRepository) key(name string) string {
+	return r.config.Cache.Prefix + name
+}
+
+// withTimeout bounds a Redis call by config.Cache.Timeout so an unreachable
+// Redis degrades reads instead of stalling them.
+func (r *redisRepository) withTimeout(ctx context.Context) (redis.Cmdable, context.CancelFunc) {
+	return withTimeout(ctx, r.cache, r.config.Cache.Timeout)
+}
+
+// call runs fn against node under its own config.Cache.Timeout.
+func (r *redisRepository) call(ctx context.Context, node redis.UniversalClient, fn func(rdb redis.Cmdable) error) error {
+	rdb, cancel := withTimeout(ctx, node, r.config.Cache.Timeout)
+	defer cancel()
+
+	return fn(rdb)
+}
+
+// withTimeout binds rdb to ctx, bounded by timeout milliseconds when positive.
+func withTimeout(ctx context.Context, rdb redis.UniversalClient, timeout int) (redis.Cmdable, context.CancelFunc) {
+	cancel := context.CancelFunc(func() {})
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(timeout)*time.Millisecond)
+	}
+
+	switch c := rdb.(type) {
+	case *redis.Client:
+		return c.WithContext(ctx), cancel
+	case *redis.ClusterClient:
+		return c.WithContext(ctx), cancel
+	default:
+		return rdb, cancel
+	}
+}
+
+// del removes names from node and returns how many existed. Keys are
+// deleted one by one as they may hash to different cluster slots.
+func (r *redisRepository) del(ctx context.Context, node redis.UniversalClient, names []string) (int64, error) {
+	var deleted int64
+	for _, name := range names {
+		var n int64
+		err := r.call(ctx, node, func(rdb redis.Cmdable) (err error) {
+			n, err = rdb.Del(name).Result()
+			return err
+		})
+		if err != nil {
+			return deleted, err
+		}
+		deleted += n
+	}
+
+	return deleted, nil
+}
+
+func itemsKey(lang string) string {
+	return itemsKeyPrefix + lang
+}
+
+func lockKey(name string) string {
+	return "lock:" + name
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"github.com/go-redis/redis/v7"
 	"github.com/go-redis/redismock/v7"
 	"github.com/inasknh/simple-poke-app/internal/config"
 	"github.com/inasknh/simple-poke-app/internal/model"
@@ -16,8 +17,9 @@ func Test_redisRepository_GetData(t *testing.T) {
 	rd, mock := redismock.NewClientMock()
 	defer rd.Close()
 
-	cfg := config.Configurations{}
-	repo := NewRedisRepository(rd, cfg)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	codec := cacheCodec{}
+	repo := &redisRepository{cache: rd, config: config.Configurations{}, codec: codec, now: func() time.Time { return now }}
 
 	ctx := context.Background()
 	expected := &model.BerriesResponse{
@@ -29,13 +31,60 @@ func Test_redisRepository_GetData(t *testing.T) {
 		},
 	}
 
-	data, _ := json.Marshal(expected)
+	fresh, _ := codec.encode(expected, now.Add(time.Minute))
+	stale, _ := codec.encode(expected, now.Add(-time.Minute))
+	compressed, _ := cacheCodec{compression: CompressionGzip}.encode(expected, now.Add(time.Minute))
+	legacy, _ := json.Marshal(expected)
+	otherVersion := append([]byte{}, fresh...)
+	otherVersion[2] = cacheSchemaVersion + 1
 
-	mock.ExpectGet("items").SetVal(string(data))
+	tests := []struct {
+		name      string
+		value     []byte
+		want      *model.BerriesResponse
+		wantStale bool
+	}{
+		{
+			name:      "given entry before its soft expiry should return fresh response",
+			value:     fresh,
+			want:      expected,
+			wantStale: false,
+		},
+		{
+			name:      "given entry past its soft expiry should return stale response",
+			value:     stale,
+			want:      expected,
+			wantStale: true,
+		},
+		{
+			name:      "given compressed entry should return decompressed response",
+			value:     compressed,
+			want:      expected,
+			wantStale: false,
+		},
+		{
+			name:      "given entry written without envelope should return nil",
+			value:     legacy,
+			want:      nil,
+			wantStale: false,
+		},
+		{
+			name:      "given entry written with another schema version should return nil",
+			value:     otherVersion,
+			want:      nil,
+			wantStale: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectGet("items:en").SetVal(string(tt.value))
 
-	result, err := repo.GetData(ctx)
-	assert.NoError(t, err)
-	assert.Equal(t, expected, result)
+			result, stale, err := repo.GetData(ctx, "en")
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, result)
+			assert.Equal(t, tt.wantStale, stale)
+		})
+	}
 }
 
 func Test_redisRepository_GetData_NotFound(t *testing.T) {
@@ -47,9 +96,9 @@ func Test_redisRepository_GetData_NotFound(t *testing.T) {
 
 	ctx := context.Background()
 
-	mock.ExpectGet("items").RedisNil()
+	mock.ExpectGet("items:en").RedisNil()
 
-	result, err := repo.GetData(ctx)
+	result, _, err := repo.GetData(ctx, "en")
 	assert.NoError(t, err)
 	assert.Nil(t, result)
 }
@@ -63,26 +112,18 @@ func Test_redisRepository_GetData_UnmarshalError(t *testing.T) {
 
 	ctx := context.Background()
 
-	mock.ExpectGet("items").SetVal("invalid-json")
+	header, _ := cacheCodec{}.encode(nil, time.Now())
+	mock.ExpectGet("items:en").SetVal(string(header[:entryHeaderSize]) + "invalid-json")
 
-	result, err := repo.GetData(ctx)
+	result, _, err := repo.GetData(ctx, "en")
 	assert.Error(t, err)
 	assert.Nil(t, result)
+	assert.ErrorIs(t, err, ErrCorruptEntry)
 	assert.Contains(t, err.Error(), "invalid character")
 }
 
 func Test_redisRepository_SetData(t *testing.T) {
-	rd, mock := redismock.NewClientMock()
-	defer rd.Close()
-
-	cfg := config.Configurations{
-		App: config.AppConfiguration{
-			TTL: 5,
-		},
-	}
-	repo := NewRedisRepository(rd, cfg)
-
-	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 	expected := &model.BerriesResponse{Berries: []model.Berry{
 		{
 			Name: "1",
@@ -90,39 +131,303 @@ func Test_redisRepository_SetData(t *testing.T) {
 		},
 	}}
 
-	data, _ := json.Marshal(expected)
+	tests := []struct {
+		name       string
+		app        config.AppConfiguration
+		wantSoft   time.Time
+		wantHard   time.Duration
+		setErr     error
+		wantErrMsg string
+	}{
+		{
+			name:     "given soft ttl should write envelope expiring softly before the hard ttl",
+			app:      config.AppConfiguration{TTL: 5, SoftTTL: 2},
+			wantSoft: now.Add(2 * time.Minute),
+			wantHard: 5 * time.Minute,
+		},
+		{
+			name:     "given no soft ttl should expire softly at the hard ttl",
+			app:      config.AppConfiguration{TTL: 5},
+			wantSoft: now.Add(5 * time.Minute),
+			wantHard: 5 * time.Minute,
+		},
+		{
+			name:     "given soft ttl longer than hard ttl should expire softly at the hard ttl",
+			app:      config.AppConfiguration{TTL: 5, SoftTTL: 10},
+			wantSoft: now.Add(5 * time.Minute),
+			wantHard: 5 * time.Minute,
+		},
+		{
+			name:       "given an error when set should return an error",
+			app:        config.AppConfiguration{TTL: 5},
+			wantSoft:   now.Add(5 * time.Minute),
+			wantHard:   5 * time.Minute,
+			setErr:     errors.New("an error"),
+			wantErrMsg: "an error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rd, mock := redismock.NewClientMock()
+			defer rd.Close()
+
+			repo := &redisRepository{
+				cache:  rd,
+				config: config.Configurations{App: tt.app},
+				now:    func() time.Time { return now },
+			}
+
+			data, _ := repo.codec.encode(expected, tt.wantSoft)
+			expect := mock.ExpectSet("items:fr", data, tt.wantHard)
+			if tt.setErr != nil {
+				expect.SetErr(tt.setErr)
+			} else {
+				expect.SetVal("OK")
+			}
 
-	mock.ExpectSet("items", data, time.Duration(5)*time.Minute).SetVal("OK")
-	err := repo.SetData(ctx, expected)
+			err := repo.SetData(context.Background(), "fr", expected)
+			if tt.wantErrMsg != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErrMsg)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_redisRepository_AcquireLock(t *testing.T) {
+	rd, mock := redismock.NewClientMock()
+	defer rd.Close()
+
+	repo := NewRedisRepository(rd, config.Configurations{})
+	ctx := context.Background()
+
+	mock.Regexp().ExpectSetNX("lock:items", `^[0-9a-f]{32}$`, 5*time.Second).SetVal(true)
+	token, err := repo.AcquireLock(ctx, "items", 5*time.Second)
 	assert.NoError(t, err)
+	assert.Len(t, token, 32)
 
+	mock.Regexp().ExpectSetNX("lock:items", `^[0-9a-f]{32}$`, 5*time.Second).SetVal(false)
+	token, err = repo.AcquireLock(ctx, "items", 5*time.Second)
+	assert.NoError(t, err)
+	assert.Empty(t, token)
+
+	mock.Regexp().ExpectSetNX("lock:items", `^[0-9a-f]{32}$`, 5*time.Second).SetErr(errors.New("an error"))
+	token, err = repo.AcquireLock(ctx, "items", 5*time.Second)
+	assert.Error(t, err)
+	assert.Empty(t, token)
 }
 
-func Test_redisRepository_SetData_Failure(t *testing.T) {
+func Test_redisRepository_ReleaseLock(t *testing.T) {
 	rd, mock := redismock.NewClientMock()
 	defer rd.Close()
 
-	cfg := config.Configurations{
-		App: config.AppConfiguration{
-			TTL: 5,
-		},
-	}
-	repo := NewRedisRepository(rd, cfg)
+	repo := NewRedisRepository(rd, config.Configurations{})
+
+	mock.ExpectEval(releaseLockScript, []string{"lock:items"}, "token").SetVal(int64(1))
+	err := repo.ReleaseLock(context.Background(), "items", "token")
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
 
+func Test_redisRepository_DeleteData(t *testing.T) {
+	rd, mock := redismock.NewClientMock()
+	defer rd.Close()
+
+	repo := NewRedisRepository(rd, config.Configurations{})
+
+	mock.ExpectScan(0, "items:*", scanCount).SetVal([]string{"items:en", "items:fr"}, 0)
+	mock.ExpectDel("items:en").SetVal(1)
+	mock.ExpectDel("items:fr").SetVal(1)
+	err := repo.DeleteData(context.Background())
+	assert.NoError(t, err)
+
+	mock.ExpectScan(0, "items:*", scanCount).SetVal([]string{"items:en"}, 0)
+	mock.ExpectDel("items:en").SetErr(errors.New("an error"))
+	err = repo.DeleteData(context.Background())
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_redisRepository_GetValueAndSetValue(t *testing.T) {
+	rd, mock := redismock.NewClientMock()
+	defer rd.Close()
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	cfg := config.Configurations{App: config.AppConfiguration{TTL: 10}}
+	repo := &redisRepository{cache: rd, config: cfg, codec: cacheCodec{}, now: func() time.Time { return now }}
 	ctx := context.Background()
-	expected := &model.BerriesResponse{Berries: []model.Berry{
+
+	expected := &model.Berry{Name: "cheri", URL: "1"}
+	data, _ := cacheCodec{}.encode(expected, now.Add(10*time.Minute))
+
+	mock.ExpectSet("berry:cheri", data, 10*time.Minute).SetVal("OK")
+	err := repo.SetValue(ctx, "berry:cheri", expected)
+	assert.NoError(t, err)
+
+	mock.ExpectGet("berry:cheri").SetVal(string(data))
+	var got model.Berry
+	found, err := repo.GetValue(ctx, "berry:cheri", &got)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, *expected, got)
+
+	mock.ExpectGet("berry:oran").RedisNil()
+	found, err = repo.GetValue(ctx, "berry:oran", &got)
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	mock.ExpectGet("berry:oran").SetErr(errors.New("an error"))
+	found, err = repo.GetValue(ctx, "berry:oran", &got)
+	assert.Error(t, err)
+	assert.False(t, found)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_redisRepository_Prefix(t *testing.T) {
+	rd, mock := redismock.NewClientMock()
+	defer rd.Close()
+
+	repo := NewRedisRepository(rd, config.Configurations{Cache: config.Cache{Prefix: "test:"}})
+
+	mock.ExpectScan(0, "test:items:*", scanCount).SetVal([]string{"test:items:en"}, 0)
+	mock.ExpectDel("test:items:en").SetVal(1)
+	err := repo.DeleteData(context.Background())
+	assert.NoError(t, err)
+
+	mock.Regexp().ExpectSetNX("test:lock:items", `^[0-9a-f]{32}$`, 5*time.Second).SetVal(true)
+	_, err = repo.AcquireLock(context.Background(), "items", 5*time.Second)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_redisRepository_ListKeys(t *testing.T) {
+	rd, mock := redismock.NewClientMock()
+	defer rd.Close()
+
+	repo := NewRedisRepository(rd, config.Configurations{Cache: config.Cache{Prefix: "test:"}})
+
+	mock.ExpectScan(0, "test:*", scanCount).SetVal([]string{"test:items", "test:berry:cheri"}, 7)
+	mock.ExpectTTL("test:items").SetVal(90 * time.Second)
+	mock.ExpectStrLen("test:items").SetVal(120)
+	mock.ExpectTTL("test:berry:cheri").SetVal(-2)
+	mock.ExpectScan(7, "test:*", scanCount).SetVal([]string{"test:lock:items"}, 0)
+	mock.ExpectTTL("test:lock:items").SetVal(-1)
+	mock.ExpectStrLen("test:lock:items").SetVal(32)
+
+	keys, err := repo.ListKeys(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []model.CacheKey{
 		{
-			Name: "1",
-			URL:  "1",
+			Key:  "items",
+			TTL:  90,
+			Size: 120,
 		},
-	}}
-
-	data, _ := json.Marshal(expected)
+		{
+			Key:  "lock:items",
+			TTL:  -1,
+			Size: 32,
+		},
+	}, keys)
+	assert.NoError(t, mock.ExpectationsWereMet())
 
-	mock.ExpectSet("items", data, time.Duration(5)*time.Minute).
-		SetErr(errors.New("an error"))
-	err := repo.SetData(ctx, expected)
+	mock.ExpectScan(0, "test:*", scanCount).SetErr(errors.New("an error"))
+	keys, err = repo.ListKeys(context.Background())
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "an error")
+	assert.Nil(t, keys)
+}
+
+// slowHook delays every command, failing it once its context is done.
+type slowHook time.Duration
+
+func (h slowHook) BeforeProcess(ctx context.Context, _ redis.Cmder) (context.Context, error) {
+	time.Sleep(time.Duration(h))
+	return ctx, ctx.Err()
+}
+
+func (h slowHook) AfterProcess(context.Context, redis.Cmder) error {
+	return nil
+}
+
+func (h slowHook) BeforeProcessPipeline(ctx context.Context, _ []redis.Cmder) (context.Context, error) {
+	return ctx, nil
+}
+
+func (h slowHook) AfterProcessPipeline(context.Context, []redis.Cmder) error {
+	return nil
+}
+
+func Test_redisRepository_ScanTimeout(t *testing.T) {
+	rd, mock := redismock.NewClientMock()
+	defer rd.Close()
+	// each command fits the timeout, a whole batch does not
+	rd.AddHook(slowHook(20 * time.Millisecond))
+
+	repo := NewRedisRepository(rd, config.Configurations{Cache: config.Cache{Prefix: "test:", Timeout: 50}})
+	ctx := context.Background()
+
+	mock.ExpectScan(0, "test:*", scanCount).SetVal([]string{"test:items:en", "test:items:fr"}, 0)
+	mock.ExpectTTL("test:items:en").SetVal(-1)
+	mock.ExpectStrLen("test:items:en").SetVal(10)
+	mock.ExpectTTL("test:items:fr").SetVal(-1)
+	mock.ExpectStrLen("test:items:fr").SetVal(10)
+	keys, err := repo.ListKeys(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, keys, 2)
+
+	mock.ExpectScan(0, "test:*", scanCount).SetVal([]string{"test:items:en", "test:items:fr", "test:berry:cheri"}, 0)
+	mock.ExpectDel("test:items:en").SetVal(1)
+	mock.ExpectDel("test:items:fr").SetVal(1)
+	mock.ExpectDel("test:berry:cheri").SetVal(1)
+	purged, err := repo.Purge(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, purged)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_redisRepository_DeleteKeyAndPurge(t *testing.T) {
+	rd, mock := redismock.NewClientMock()
+	defer rd.Close()
+
+	repo := NewRedisRepository(rd, config.Configurations{Cache: config.Cache{Prefix: "test:"}})
+	ctx := context.Background()
+
+	mock.ExpectDel("test:berry:cheri").SetVal(1)
+	deleted, err := repo.DeleteKey(ctx, "berry:cheri")
+	assert.NoError(t, err)
+	assert.True(t, deleted)
+
+	mock.ExpectDel("test:berry:oran").SetVal(0)
+	deleted, err = repo.DeleteKey(ctx, "berry:oran")
+	assert.NoError(t, err)
+	assert.False(t, deleted)
+
+	mock.ExpectScan(0, "test:*", scanCount).SetVal([]string{"test:items", "test:berry:cheri"}, 3)
+	mock.ExpectDel("test:items").SetVal(1)
+	mock.ExpectDel("test:berry:cheri").SetVal(1)
+	mock.ExpectScan(3, "test:*", scanCount).SetVal([]string{}, 0)
+	purged, err := repo.Purge(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, purged)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_redisRepository_NoPrefix(t *testing.T) {
+	rd, mock := redismock.NewClientMock()
+	defer rd.Close()
+
+	repo := NewRedisRepository(rd, config.Configurations{})
+	ctx := context.Background()
+
+	// neither walks nor deletes the keys of a shared Redis
+	keys, err := repo.ListKeys(ctx)
+	assert.ErrorIs(t, err, ErrNoPrefix)
+	assert.Nil(t, keys)
 
+	purged, err := repo.Purge(ctx)
+	assert.ErrorIs(t, err, ErrNoPrefix)
+	assert.Equal(t, 0, purged)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -3,12 +3,36 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"github.com/inasknh/simple-poke-app/internal/model"
+	"strings"
 )
 
 const (
-	getAllBerries = "SELECT name, url FROM berries"
+	// berries are translated through their item, falling back to the default
+	// language, in a stable order so the listing and its hash do not change
+	// between rebuilds
+	getAllBerries = "SELECT b.name, b.url, COALESCE(t.name, d.name, ''), COALESCE(t.description, d.description, ''), COALESCE(i.sprite, '') " +
+		"FROM berries b LEFT JOIN items i ON i.id = b.item_id " +
+		"LEFT JOIN item_translations t ON t.item_id = b.item_id AND t.language = ? " +
+		"LEFT JOIN item_translations d ON d.item_id = b.item_id AND d.language = ? " +
+		"ORDER BY b.id"
+	getBerry = "SELECT b.name, b.url, COALESCE(d.name, ''), COALESCE(d.description, ''), COALESCE(i.sprite, '') " +
+		"FROM berries b LEFT JOIN items i ON i.id = b.item_id " +
+		"LEFT JOIN item_translations d ON d.item_id = b.item_id AND d.language = ? WHERE b.name = ? LIMIT 1"
+	insertSyncRun = "INSERT INTO sync_runs () VALUES ()"
+	getSyncRun    = "SELECT id, finished_at FROM sync_runs WHERE id = ?"
+	getLastSync   = "SELECT id, finished_at FROM sync_runs ORDER BY id DESC LIMIT 1"
+
+	upsertFirmness    = "INSERT INTO firmnesses (id, name) VALUES (?, ?) ON DUPLICATE KEY UPDATE name = VALUES(name)"
+	upsertFlavor      = "INSERT INTO flavors (id, name) VALUES (?, ?) ON DUPLICATE KEY UPDATE name = VALUES(name)"
+	upsertBerryFlavor = "INSERT INTO berry_flavors (berry_id, flavor_id, potency) SELECT id, ?, ? FROM berries WHERE name = ? " +
+		"ON DUPLICATE KEY UPDATE potency = VALUES(potency)"
+	getFirmnesses = "SELECT f.id, f.name, b.name, b.url FROM firmnesses f LEFT JOIN berries b ON b.firmness_id = f.id ORDER BY f.id, b.id"
+	getFlavors    = "SELECT f.id, f.name, b.name, b.url, bf.potency FROM flavors f " +
+		"LEFT JOIN berry_flavors bf ON bf.flavor_id = f.id LEFT JOIN berries b ON b.id = bf.berry_id " +
+		"ORDER BY f.id, bf.potency DESC, b.id"
 )
 
 type repository struct {
@@ -22,7 +46,63 @@ func NewRepository(db *sql.DB) Repository {
 
 type Repository interface {
 	CreateBerry(ctx context.Context, berries []model.Berry) error
-	FetchBerries(ctx context.Context) (*model.BerriesResponse, error)
+	// FetchBerries returns the listing localized in lang.
+	FetchBerries(ctx context.Context, lang string) (*model.BerriesResponse, error)
+	// FetchBerry returns the berry named name in the default language, as
+	// listed by FetchBerries, or model.ErrNotFound.
+	FetchBerry(ctx context.Context, name string) (*model.Berry, error)
+	// StreamBerries calls fn with every berry localized in lang as it is read,
+	// without holding the listing in memory.
+	StreamBerries(ctx context.Context, lang string, fn func(berry model.Berry) error) error
+	// CreateSyncRun records a successful sync, bumping the dataset version.
+	CreateSyncRun(ctx context.Context) (*model.SyncRun, error)
+	// FetchLastSyncRun returns the latest sync run, or nil before the first sync.
+	FetchLastSyncRun(ctx context.Context) (*model.SyncRun, error)
+	// CreateFirmness upserts a firmness and links the named berries to it.
+	CreateFirmness(ctx context.Context, firmness model.Firmness) error
+	// CreateFlavor upserts a flavor and the potency of it in the named berries.
+	CreateFlavor(ctx context.Context, flavor model.Flavor) error
+	FetchFirmnesses(ctx context.Context) (*model.FirmnessesResponse, error)
+	FetchFlavors(ctx context.Context) (*model.FlavorsResponse, error)
+	// CreatePokemon upserts a Pokémon, replacing its types, abilities and stats.
+	CreatePokemon(ctx context.Context, pokemon model.Pokemon) error
+	FetchPokemonList(ctx context.Context) (*model.PokemonListResponse, error)
+	// FetchPokemon returns the Pokémon named name, or model.ErrNotFound.
+	FetchPokemon(ctx context.Context, name string) (*model.Pokemon, error)
+	// CreateItem upserts an item with its category, fling effect, attributes and translations.
+	CreateItem(ctx context.Context, item model.Item) error
+	// LinkBerryItem records that the named berry is the item itemID.
+	LinkBerryItem(ctx context.Context, berry string, itemID int) error
+	FetchItemCatalogue(ctx context.Context) (*model.ItemCatalogueResponse, error)
+	// FetchItem returns the item named name, or model.ErrNotFound.
+	FetchItem(ctx context.Context, name string) (*model.Item, error)
+	// CreateType upserts a type, replacing the damage it deals to other types.
+	CreateType(ctx context.Context, t model.Type) error
+	// SetBerryNaturalGift records the natural gift type and power of the named berry.
+	SetBerryNaturalGift(ctx context.Context, berry string, typeID int, power int) error
+	FetchTypeChart(ctx context.Context) (*model.TypeChart, error)
+	// SetBerryGrowth records how the tree of the named berry grows.
+	SetBerryGrowth(ctx context.Context, growth model.BerryGrowth) error
+	// FetchBerryGrowths returns the growth of every berry synced with it.
+	FetchBerryGrowths(ctx context.Context) (*model.BerryGrowths, error)
+	// CreateEvolutionChain upserts a chain with its species, replacing its evolutions.
+	CreateEvolutionChain(ctx context.Context, chain model.EvolutionChain) error
+	// FetchEvolutionChain returns the chain of the named species, or model.ErrNotFound.
+	FetchEvolutionChain(ctx context.Context, species string) (*model.EvolutionChain, error)
+	// CreateAsset records the blob an upstream url was mirrored to.
+	CreateAsset(ctx context.Context, asset model.Asset) error
+	// FetchAssetByURL returns the asset mirrored from url, or model.ErrNotFound.
+	FetchAssetByURL(ctx context.Context, url string) (*model.Asset, error)
+	// FetchAsset returns an asset stored under hash, or model.ErrNotFound.
+	FetchAsset(ctx context.Context, hash string) (*model.Asset, error)
+	// FetchSearchDocuments returns every berry, item and Pokémon with their localized names.
+	FetchSearchDocuments(ctx context.Context) (*model.SearchDocuments, error)
+	// CreateNature upserts a nature with the flavors it likes and hates.
+	CreateNature(ctx context.Context, nature model.Nature) error
+	// FetchNature returns the nature named name, or model.ErrNotFound.
+	FetchNature(ctx context.Context, name string) (*model.Nature, error)
+	// FetchFlavorProfiles returns every flavor and the flavor potencies of every berry.
+	FetchFlavorProfiles(ctx context.Context) (*model.FlavorProfiles, error)
 }
 
 func (r *repository) CreateBerry(ctx context.Context, berries []model.Berry) error {
@@ -41,6 +121,8 @@ func (r *repository) CreateBerry(ctx context.Context, berries []model.Berry) err
 		query += "(?, ?)"
 		vals = append(vals, u.Name, u.URL)
 	}
+	// berries are unique by name, a resync refreshes their url
+	query += " ON DUPLICATE KEY UPDATE url = VALUES(url)"
 
 	// Execute query
 	_, err := r.db.ExecContext(ctx, query, vals...)
@@ -51,26 +133,234 @@ func (r *repository) CreateBerry(ctx context.Context, berries []model.Berry) err
 	return nil
 }
 
-func (r *repository) FetchBerries(ctx context.Context) (*model.BerriesResponse, error) {
-	rows, err := r.db.QueryContext(ctx, getAllBerries)
+func (r *repository) FetchBerries(ctx context.Context, lang string) (*model.BerriesResponse, error) {
+	// the version is read first so a concurrent sync can only make the rows newer
+	run, err := r.FetchLastSyncRun(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	res := []model.Berry{}
+	err = r.StreamBerries(ctx, lang, func(berry model.Berry) error {
+		res = append(res, berry)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &model.BerriesResponse{Berries: res}
+	if run != nil {
+		response.SyncID = run.ID
+		response.SyncedAt = &run.FinishedAt
+	}
+
+	return response, nil
+}
+
+func (r *repository) StreamBerries(ctx context.Context, lang string, fn func(berry model.Berry) error) error {
+	rows, err := r.db.QueryContext(ctx, getAllBerries, lang, model.DefaultLanguage)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
 	for rows.Next() {
 		var b model.Berry
 		err = rows.Scan(
 			&b.Name,
 			&b.URL,
+			&b.DisplayName,
+			&b.Description,
+			&b.Sprite,
 		)
-
 		if err != nil {
+			return err
+		}
+
+		if err = fn(b); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func (r *repository) FetchBerry(ctx context.Context, name string) (*model.Berry, error) {
+	var b model.Berry
+	err := r.db.QueryRowContext(ctx, getBerry, model.DefaultLanguage, name).Scan(
+		&b.Name,
+		&b.URL,
+		&b.DisplayName,
+		&b.Description,
+		&b.Sprite,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &b, nil
+}
+
+func (r *repository) CreateSyncRun(ctx context.Context) (*model.SyncRun, error) {
+	res, err := r.db.ExecContext(ctx, insertSyncRun)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record sync run: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	var run model.SyncRun
+	err = r.db.QueryRowContext(ctx, getSyncRun, id).Scan(
+		&run.ID,
+		&run.FinishedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &run, nil
+}
+
+func (r *repository) FetchLastSyncRun(ctx context.Context) (*model.SyncRun, error) {
+	var run model.SyncRun
+	err := r.db.QueryRowContext(ctx, getLastSync).Scan(
+		&run.ID,
+		&run.FinishedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &run, nil
+}
+
+func (r *repository) CreateFirmness(ctx context.Context, firmness model.Firmness) error {
+	return r.withTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, upsertFirmness, firmness.ID, firmness.Name); err != nil {
+			return fmt.Errorf("failed to insert firmness: %w", err)
+		}
+		if len(firmness.Berries) == 0 {
+			return nil
+		}
+
+		query := "UPDATE berries SET firmness_id = ? WHERE name IN (?" + strings.Repeat(", ?", len(firmness.Berries)-1) + ")"
+		vals := []interface{}{firmness.ID}
+		for _, berry := range firmness.Berries {
+			vals = append(vals, berry.Name)
+		}
+		if _, err := tx.ExecContext(ctx, query, vals...); err != nil {
+			return fmt.Errorf("failed to link berries to firmness: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (r *repository) CreateFlavor(ctx context.Context, flavor model.Flavor) error {
+	return r.withTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, upsertFlavor, flavor.ID, flavor.Name); err != nil {
+			return fmt.Errorf("failed to insert flavor: %w", err)
+		}
+
+		for _, berry := range flavor.Berries {
+			if _, err := tx.ExecContext(ctx, upsertBerryFlavor, flavor.ID, berry.Potency, berry.Name); err != nil {
+				return fmt.Errorf("failed to link berry to flavor: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r *repository) FetchFirmnesses(ctx context.Context) (*model.FirmnessesResponse, error) {
+	rows, err := r.db.QueryContext(ctx, getFirmnesses)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := []model.Firmness{}
+	for rows.Next() {
+		var f model.Firmness
+		var name, url sql.NullString
+		if err = rows.Scan(&f.ID, &f.Name, &name, &url); err != nil {
+			return nil, err
+		}
+
+		// rows are ordered by firmness, a new id starts the next group
+		if len(res) == 0 || res[len(res)-1].ID != f.ID {
+			f.Berries = []model.Berry{}
+			res = append(res, f)
+		}
+		if name.Valid {
+			last := &res[len(res)-1]
+			last.Berries = append(last.Berries, model.Berry{Name: name.String, URL: url.String})
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &model.FirmnessesResponse{Firmnesses: res}, nil
+}
+
+func (r *repository) FetchFlavors(ctx context.Context) (*model.FlavorsResponse, error) {
+	rows, err := r.db.QueryContext(ctx, getFlavors)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := []model.Flavor{}
+	for rows.Next() {
+		var f model.Flavor
+		var name, url sql.NullString
+		var potency sql.NullInt64
+		if err = rows.Scan(&f.ID, &f.Name, &name, &url, &potency); err != nil {
 			return nil, err
 		}
 
-		res = append(res, b)
+		// rows are ordered by flavor, a new id starts the next group
+		if len(res) == 0 || res[len(res)-1].ID != f.ID {
+			f.Berries = []model.FlavorBerry{}
+			res = append(res, f)
+		}
+		if name.Valid {
+			last := &res[len(res)-1]
+			last.Berries = append(last.Berries, model.FlavorBerry{
+				Berry:   model.Berry{Name: name.String, URL: url.String},
+				Potency: int(potency.Int64),
+			})
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &model.FlavorsResponse{Flavors: res}, nil
+}
+
+// withTx runs fn in a transaction, committing when it succeeds.
+func (r *repository) withTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err = fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
 	}
 
-	return &model.BerriesResponse{Berries: res}, nil
+	return tx.Commit()
 }
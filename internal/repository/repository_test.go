@@ -9,6 +9,7 @@ import (
 	"reflect"
 	"regexp"
 	"testing"
+	"time"
 )
 
 func Test_repository_CreateBerry(t *testing.T) {
@@ -50,7 +51,7 @@ func Test_repository_CreateBerry(t *testing.T) {
 			},
 			wantErr: true,
 			mockCall: func(mock sqlmock.Sqlmock) {
-				query := "INSERT INTO berries (name, url) VALUES (?, ?),(?, ?)"
+				query := "INSERT INTO berries (name, url) VALUES (?, ?),(?, ?) ON DUPLICATE KEY UPDATE url = VALUES(url)"
 				mock.
 					ExpectExec(regexp.QuoteMeta(query)).WithArgs(
 					"1",
@@ -78,7 +79,7 @@ func Test_repository_CreateBerry(t *testing.T) {
 			},
 			wantErr: true,
 			mockCall: func(mock sqlmock.Sqlmock) {
-				query := "INSERT INTO berries (name, url) VALUES (?, ?),(?, ?)"
+				query := "INSERT INTO berries (name, url) VALUES (?, ?),(?, ?) ON DUPLICATE KEY UPDATE url = VALUES(url)"
 				mock.
 					ExpectExec(regexp.QuoteMeta(query)).WithArgs(
 					"1",
@@ -114,6 +115,7 @@ func Test_repository_CreateBerry(t *testing.T) {
 }
 
 func Test_repository_FetchBerries(t *testing.T) {
+	syncedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 	type args struct {
 		ctx context.Context
 	}
@@ -132,9 +134,42 @@ func Test_repository_FetchBerries(t *testing.T) {
 			want:    nil,
 			wantErr: true,
 			mockCall: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(getLastSync).WillReturnRows(mock.NewRows([]string{"id", "finished_at"}))
 				mock.ExpectQuery(getAllBerries).WillReturnError(errors.New("any error"))
 			},
 		},
+		{
+			name: "given an error when fetching the last sync run should return nil and an error",
+			args: args{
+				ctx: context.Background(),
+			},
+			want:    nil,
+			wantErr: true,
+			mockCall: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(getLastSync).WillReturnError(errors.New("any error"))
+			},
+		},
+		{
+			name: "given a previous sync run should return response with its version",
+			args: args{
+				ctx: context.Background(),
+			},
+			want: &model.BerriesResponse{
+				Berries: []model.Berry{
+					{
+						Name: "1",
+						URL:  "1",
+					},
+				},
+				SyncID:   7,
+				SyncedAt: &syncedAt,
+			},
+			wantErr: false,
+			mockCall: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(getLastSync).WillReturnRows(mock.NewRows([]string{"id", "finished_at"}).AddRow(7, syncedAt))
+				mock.ExpectQuery(getAllBerries).WillReturnRows(mock.NewRows([]string{"name", "url", "display_name", "description", "sprite"}).AddRow("1", "1", "", "", ""))
+			},
+		},
 		{
 			name: "given happy flow should return response and no error",
 			args: args{
@@ -142,8 +177,11 @@ func Test_repository_FetchBerries(t *testing.T) {
 			},
 			want: &model.BerriesResponse{Berries: []model.Berry{
 				{
-					Name: "1",
-					URL:  "1",
+					Name:        "1",
+					URL:         "1",
+					DisplayName: "Baie Ceriz",
+					Description: "Soigne la paralysie.",
+					Sprite:      "/assets/0f",
 				},
 			}},
 			wantErr: false,
@@ -151,12 +189,19 @@ func Test_repository_FetchBerries(t *testing.T) {
 				columns := []string{
 					"name",
 					"url",
+					"display_name",
+					"description",
+					"sprite",
 				}
 				mockRes := mock.NewRows(columns).AddRow(
 					"1",
 					"1",
+					"Baie Ceriz",
+					"Soigne la paralysie.",
+					"/assets/0f",
 				)
-				mock.ExpectQuery(getAllBerries).WillReturnRows(mockRes)
+				mock.ExpectQuery(getLastSync).WillReturnRows(mock.NewRows([]string{"id", "finished_at"}))
+				mock.ExpectQuery(getAllBerries).WithArgs("fr", "en").WillReturnRows(mockRes)
 			},
 		},
 	}
@@ -175,7 +220,7 @@ func Test_repository_FetchBerries(t *testing.T) {
 			r := &repository{
 				db: db,
 			}
-			got, err := r.FetchBerries(tt.args.ctx)
+			got, err := r.FetchBerries(tt.args.ctx, "fr")
 			if (err != nil) != tt.wantErr {
 				t.Errorf("FetchBerries() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -186,3 +231,341 @@ func Test_repository_FetchBerries(t *testing.T) {
 		})
 	}
 }
+
+func Test_repository_FetchBerry(t *testing.T) {
+	tests := []struct {
+		name     string
+		want     *model.Berry
+		wantErr  error
+		mockCall func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name:    "given an error when execute query should return nil and an error",
+			want:    nil,
+			wantErr: errors.New("any error"),
+			mockCall: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(getBerry).WithArgs("en", "cheri").WillReturnError(errors.New("any error"))
+			},
+		},
+		{
+			name:    "given no rows should return nil and ErrNotFound",
+			want:    nil,
+			wantErr: model.ErrNotFound,
+			mockCall: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(getBerry).WithArgs("en", "cheri").WillReturnRows(mock.NewRows([]string{"name", "url", "display_name", "description", "sprite"}))
+			},
+		},
+		{
+			name:    "given happy flow should return the berry as listed and no error",
+			want:    &model.Berry{Name: "cheri", URL: "1", DisplayName: "Cheri Berry", Description: "Cures paralysis.", Sprite: "/assets/0f"},
+			wantErr: nil,
+			mockCall: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(getBerry).WithArgs("en", "cheri").WillReturnRows(mock.NewRows([]string{"name", "url", "display_name", "description", "sprite"}).
+					AddRow("cheri", "1", "Cheri Berry", "Cures paralysis.", "/assets/0f"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer func(db *sql.DB) {
+				_ = db.Close()
+			}(db)
+
+			tt.mockCall(mock)
+
+			r := &repository{
+				db: db,
+			}
+			got, err := r.FetchBerry(context.Background(), "cheri")
+			if (err != nil) != (tt.wantErr != nil) || (err != nil && err.Error() != tt.wantErr.Error()) {
+				t.Errorf("FetchBerry() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FetchBerry() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_repository_CreateSyncRun(t *testing.T) {
+	finishedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		want     *model.SyncRun
+		wantErr  bool
+		mockCall func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name:    "given an error when insert should return nil and an error",
+			want:    nil,
+			wantErr: true,
+			mockCall: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(insertSyncRun).WillReturnError(errors.New("any error"))
+			},
+		},
+		{
+			name:    "given happy flow should return the recorded run",
+			want:    &model.SyncRun{ID: 3, FinishedAt: finishedAt},
+			wantErr: false,
+			mockCall: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(insertSyncRun).WillReturnResult(sqlmock.NewResult(3, 1))
+				mock.ExpectQuery(getSyncRun).WithArgs(int64(3)).WillReturnRows(mock.NewRows([]string{"id", "finished_at"}).AddRow(3, finishedAt))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer func(db *sql.DB) {
+				_ = db.Close()
+			}(db)
+
+			tt.mockCall(mock)
+
+			r := &repository{
+				db: db,
+			}
+			got, err := r.CreateSyncRun(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateSyncRun() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CreateSyncRun() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_repository_StreamBerries(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	r := &repository{
+		db: db,
+	}
+
+	mock.ExpectQuery(getAllBerries).WillReturnRows(mock.NewRows([]string{"name", "url", "display_name", "description", "sprite"}).AddRow("1", "1", "", "", "").AddRow("2", "2", "", "", ""))
+	var got []model.Berry
+	err = r.StreamBerries(context.Background(), "en", func(berry model.Berry) error {
+		got = append(got, berry)
+		return nil
+	})
+	if err != nil {
+		t.Errorf("StreamBerries() error = %v", err)
+	}
+	if want := []model.Berry{{Name: "1", URL: "1"}, {Name: "2", URL: "2"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("StreamBerries() got = %v, want %v", got, want)
+	}
+
+	// an error from fn stops reading
+	mock.ExpectQuery(getAllBerries).WillReturnRows(mock.NewRows([]string{"name", "url", "display_name", "description", "sprite"}).AddRow("1", "1", "", "", "").AddRow("2", "2", "", "", ""))
+	calls := 0
+	err = r.StreamBerries(context.Background(), "en", func(berry model.Berry) error {
+		calls++
+		return errors.New("any error")
+	})
+	if err == nil || calls != 1 {
+		t.Errorf("StreamBerries() error = %v, calls = %d", err, calls)
+	}
+}
+
+func Test_repository_CreateFirmness(t *testing.T) {
+	tests := []struct {
+		name     string
+		firmness model.Firmness
+		wantErr  bool
+		mockCall func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name:     "given an error when upserting the firmness should rollback and return an error",
+			firmness: model.Firmness{ID: 2, Name: "soft"},
+			wantErr:  true,
+			mockCall: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(upsertFirmness).WithArgs(2, "soft").WillReturnError(errors.New("any error"))
+				mock.ExpectRollback()
+			},
+		},
+		{
+			name: "given berries should link them to the firmness",
+			firmness: model.Firmness{ID: 2, Name: "soft", Berries: []model.Berry{
+				{
+					Name: "cheri",
+				},
+				{
+					Name: "figy",
+				},
+			}},
+			wantErr: false,
+			mockCall: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(upsertFirmness).WithArgs(2, "soft").WillReturnResult(sqlmock.NewResult(2, 1))
+				mock.ExpectExec("UPDATE berries SET firmness_id = ? WHERE name IN (?, ?)").
+					WithArgs(2, "cheri", "figy").
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectCommit()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer func(db *sql.DB) {
+				_ = db.Close()
+			}(db)
+
+			tt.mockCall(mock)
+
+			r := &repository{
+				db: db,
+			}
+			if err := r.CreateFirmness(context.Background(), tt.firmness); (err != nil) != tt.wantErr {
+				t.Errorf("CreateFirmness() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("CreateFirmness() %v", err)
+			}
+		})
+	}
+}
+
+func Test_repository_CreateFlavor(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(upsertFlavor).WithArgs(1, "spicy").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(upsertBerryFlavor).WithArgs(1, 10, "cheri").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(upsertBerryFlavor).WithArgs(1, 15, "figy").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	r := &repository{
+		db: db,
+	}
+	err = r.CreateFlavor(context.Background(), model.Flavor{ID: 1, Name: "spicy", Berries: []model.FlavorBerry{
+		{
+			Berry:   model.Berry{Name: "cheri"},
+			Potency: 10,
+		},
+		{
+			Berry:   model.Berry{Name: "figy"},
+			Potency: 15,
+		},
+	}})
+	if err != nil {
+		t.Errorf("CreateFlavor() error = %v", err)
+	}
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("CreateFlavor() %v", err)
+	}
+}
+
+func Test_repository_FetchFirmnesses(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	mock.ExpectQuery(getFirmnesses).WillReturnRows(mock.NewRows([]string{"id", "name", "name", "url"}).
+		AddRow(1, "very-soft", "pecha", "3").
+		AddRow(2, "soft", "cheri", "1").
+		AddRow(2, "soft", "figy", "11").
+		AddRow(3, "hard", nil, nil))
+
+	r := &repository{
+		db: db,
+	}
+	got, err := r.FetchFirmnesses(context.Background())
+	if err != nil {
+		t.Errorf("FetchFirmnesses() error = %v", err)
+		return
+	}
+	want := &model.FirmnessesResponse{Firmnesses: []model.Firmness{
+		{
+			ID:      1,
+			Name:    "very-soft",
+			Berries: []model.Berry{{Name: "pecha", URL: "3"}},
+		},
+		{
+			ID:      2,
+			Name:    "soft",
+			Berries: []model.Berry{{Name: "cheri", URL: "1"}, {Name: "figy", URL: "11"}},
+		},
+		{
+			ID:      3,
+			Name:    "hard",
+			Berries: []model.Berry{},
+		},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FetchFirmnesses() got = %v, want %v", got, want)
+	}
+}
+
+func Test_repository_FetchFlavors(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	mock.ExpectQuery(getFlavors).WillReturnRows(mock.NewRows([]string{"id", "name", "name", "url", "potency"}).
+		AddRow(1, "spicy", "figy", "11", 15).
+		AddRow(1, "spicy", "cheri", "1", 10).
+		AddRow(2, "dry", nil, nil, nil))
+
+	r := &repository{
+		db: db,
+	}
+	got, err := r.FetchFlavors(context.Background())
+	if err != nil {
+		t.Errorf("FetchFlavors() error = %v", err)
+		return
+	}
+	want := &model.FlavorsResponse{Flavors: []model.Flavor{
+		{
+			ID:   1,
+			Name: "spicy",
+			Berries: []model.FlavorBerry{
+				{Berry: model.Berry{Name: "figy", URL: "11"}, Potency: 15},
+				{Berry: model.Berry{Name: "cheri", URL: "1"}, Potency: 10},
+			},
+		},
+		{
+			ID:      2,
+			Name:    "dry",
+			Berries: []model.FlavorBerry{},
+		},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FetchFlavors() got = %v, want %v", got, want)
+	}
+}
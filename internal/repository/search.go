@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"github.com/inasknh/simple-poke-app/internal/model"
+)
+
+// getSearchDocuments lists every resource with its localized names, rows
+// of a resource are contiguous. Berries are translated through their item,
+// Pokémon through their species.
+const getSearchDocuments = "SELECT 'berry', b.id, b.name, t.language, t.name FROM berries b " +
+	"LEFT JOIN item_translations t ON t.item_id = b.item_id " +
+	"UNION ALL SELECT 'item', i.id, i.name, t.language, t.name FROM items i " +
+	"LEFT JOIN item_translations t ON t.item_id = i.id " +
+	"UNION ALL SELECT 'pokemon', p.id, p.name, n.language, n.name FROM pokemon p " +
+	"LEFT JOIN pokemon_names n ON n.pokemon_id = p.id " +
+	"ORDER BY 1, 2"
+
+func (r *repository) FetchSearchDocuments(ctx context.Context) (*model.SearchDocuments, error) {
+	res := []model.SearchDocument{}
+	lastID := 0
+	err := r.queryEach(ctx, getSearchDocuments, nil, func(rows *sql.Rows) error {
+		var doc model.SearchDocument
+		var id int
+		var language, name sql.NullString
+		if err := rows.Scan(&doc.Type, &id, &doc.Name, &language, &name); err != nil {
+			return err
+		}
+
+		// a new type or id starts the next resource
+		if len(res) == 0 || res[len(res)-1].Type != doc.Type || lastID != id {
+			res = append(res, doc)
+			lastID = id
+		}
+		if language.Valid && name.Valid {
+			last := &res[len(res)-1]
+			if last.Names == nil {
+				last.Names = map[string]string{}
+			}
+			last.Names[language.String] = name.String
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.SearchDocuments{Documents: res}, nil
+}
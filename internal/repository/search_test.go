@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/inasknh/simple-poke-app/internal/model"
+	"reflect"
+	"testing"
+)
+
+func Test_repository_FetchSearchDocuments(t *testing.T) {
+	r, mock, closeDB := newTestRepository(t)
+	defer closeDB()
+
+	columns := []string{"type", "id", "name", "language", "localized"}
+	mock.ExpectQuery(getSearchDocuments).WillReturnRows(mock.NewRows(columns).
+		AddRow("berry", 1, "cheri", "en", "Cheri Berry").
+		AddRow("berry", 1, "cheri", "fr", "Baie Ceriz").
+		AddRow("berry", 2, "chesto", nil, nil).
+		AddRow("item", 1, "master-ball", "en", "Master Ball").
+		AddRow("pokemon", 1, "bulbasaur", "fr", "Bulbizarre").
+		AddRow("pokemon", 7, "squirtle", nil, nil))
+
+	got, err := r.FetchSearchDocuments(context.Background())
+	if err != nil {
+		t.Fatalf("FetchSearchDocuments() error = %v", err)
+	}
+
+	want := &model.SearchDocuments{Documents: []model.SearchDocument{
+		{Type: model.SearchTypeBerry, Name: "cheri", Names: map[string]string{"en": "Cheri Berry", "fr": "Baie Ceriz"}},
+		{Type: model.SearchTypeBerry, Name: "chesto"},
+		{Type: model.SearchTypeItem, Name: "master-ball", Names: map[string]string{"en": "Master Ball"}},
+		{Type: model.SearchTypePokemon, Name: "bulbasaur", Names: map[string]string{"fr": "Bulbizarre"}},
+		{Type: model.SearchTypePokemon, Name: "squirtle"},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FetchSearchDocuments() got = %v, want %v", got, want)
+	}
+
+	mock.ExpectQuery(getSearchDocuments).WillReturnError(errors.New("any error"))
+	if _, err = r.FetchSearchDocuments(context.Background()); err == nil {
+		t.Errorf("FetchSearchDocuments() expected an error")
+	}
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/inasknh/simple-poke-app/internal/model"
+)
+
+const (
+	deleteDamageRelations = "DELETE FROM type_damage_relations WHERE attacking_type_id = ?"
+	insertDamageRelation  = "INSERT INTO type_damage_relations (attacking_type_id, defending_type_id, multiplier) VALUES (?, ?, ?)"
+	setBerryNaturalGift   = "UPDATE berries SET natural_gift_type_id = ?, natural_gift_power = ? WHERE name = ?"
+
+	getTypes           = "SELECT name FROM types ORDER BY id"
+	getDamageRelations = "SELECT a.name, d.name, r.multiplier FROM type_damage_relations r " +
+		"JOIN types a ON a.id = r.attacking_type_id JOIN types d ON d.id = r.defending_type_id"
+	getNaturalGifts = "SELECT b.name, t.name, b.natural_gift_power FROM berries b " +
+		"JOIN types t ON t.id = b.natural_gift_type_id ORDER BY b.id"
+)
+
+func (r *repository) CreateType(ctx context.Context, t model.Type) error {
+	return r.withTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, upsertType, t.ID, t.Name); err != nil {
+			return fmt.Errorf("failed to insert type: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, deleteDamageRelations, t.ID); err != nil {
+			return fmt.Errorf("failed to clear damage relations: %w", err)
+		}
+
+		for _, relation := range t.DamageTo {
+			// the defending type may not be synced yet
+			if _, err := tx.ExecContext(ctx, upsertType, relation.TypeID, relation.Type); err != nil {
+				return fmt.Errorf("failed to insert type: %w", err)
+			}
+			if _, err := tx.ExecContext(ctx, insertDamageRelation, t.ID, relation.TypeID, relation.Multiplier); err != nil {
+				return fmt.Errorf("failed to insert damage relation: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r *repository) SetBerryNaturalGift(ctx context.Context, berry string, typeID int, power int) error {
+	if _, err := r.db.ExecContext(ctx, setBerryNaturalGift, typeID, power, berry); err != nil {
+		return fmt.Errorf("failed to set berry natural gift: %w", err)
+	}
+
+	return nil
+}
+
+func (r *repository) FetchTypeChart(ctx context.Context) (*model.TypeChart, error) {
+	chart := &model.TypeChart{
+		Types:        []string{},
+		Multipliers:  map[string]map[string]float64{},
+		NaturalGifts: []model.NaturalGift{},
+	}
+
+	err := r.queryEach(ctx, getTypes, nil, func(rows *sql.Rows) error {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return err
+		}
+		chart.Types = append(chart.Types, name)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.queryEach(ctx, getDamageRelations, nil, func(rows *sql.Rows) error {
+		var attacking, defending string
+		var multiplier float64
+		if err := rows.Scan(&attacking, &defending, &multiplier); err != nil {
+			return err
+		}
+		if chart.Multipliers[attacking] == nil {
+			chart.Multipliers[attacking] = map[string]float64{}
+		}
+		chart.Multipliers[attacking][defending] = multiplier
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.queryEach(ctx, getNaturalGifts, nil, func(rows *sql.Rows) error {
+		var gift model.NaturalGift
+		if err := rows.Scan(&gift.Berry, &gift.Type, &gift.Power); err != nil {
+			return err
+		}
+		chart.NaturalGifts = append(chart.NaturalGifts, gift)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return chart, nil
+}
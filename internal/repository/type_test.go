@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/inasknh/simple-poke-app/internal/model"
+	"reflect"
+	"testing"
+)
+
+func Test_repository_CreateType(t *testing.T) {
+	fire := model.Type{
+		ID:   10,
+		Name: "fire",
+		DamageTo: []model.DamageRelation{
+			{TypeID: 12, Type: "grass", Multiplier: 2},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		wantErr  bool
+		mockCall func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name:    "given an error when inserting a relation should rollback and return an error",
+			wantErr: true,
+			mockCall: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(upsertType).WithArgs(10, "fire").WillReturnResult(sqlmock.NewResult(10, 1))
+				mock.ExpectExec(deleteDamageRelations).WithArgs(10).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(upsertType).WithArgs(12, "grass").WillReturnResult(sqlmock.NewResult(12, 1))
+				mock.ExpectExec(insertDamageRelation).WillReturnError(errors.New("any error"))
+				mock.ExpectRollback()
+			},
+		},
+		{
+			name:    "given happy flow should replace the relations and commit",
+			wantErr: false,
+			mockCall: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(upsertType).WithArgs(10, "fire").WillReturnResult(sqlmock.NewResult(10, 1))
+				mock.ExpectExec(deleteDamageRelations).WithArgs(10).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(upsertType).WithArgs(12, "grass").WillReturnResult(sqlmock.NewResult(12, 1))
+				mock.ExpectExec(insertDamageRelation).WithArgs(10, 12, 2.0).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, mock, closeDB := newTestRepository(t)
+			defer closeDB()
+
+			tt.mockCall(mock)
+
+			if err := r.CreateType(context.Background(), fire); (err != nil) != tt.wantErr {
+				t.Errorf("CreateType() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("CreateType() %v", err)
+			}
+		})
+	}
+}
+
+func Test_repository_FetchTypeChart(t *testing.T) {
+	r, mock, closeDB := newTestRepository(t)
+	defer closeDB()
+
+	mock.ExpectQuery(getTypes).WillReturnRows(mock.NewRows([]string{"name"}).AddRow("fire").AddRow("grass"))
+	mock.ExpectQuery(getDamageRelations).WillReturnRows(mock.NewRows([]string{"attacking", "defending", "multiplier"}).
+		AddRow("fire", "grass", "2.0").
+		AddRow("fire", "fire", "0.5").
+		AddRow("grass", "fire", "0.5"))
+	mock.ExpectQuery(getNaturalGifts).WillReturnRows(mock.NewRows([]string{"berry", "type", "power"}).
+		AddRow("cheri", "fire", 60))
+
+	got, err := r.FetchTypeChart(context.Background())
+	if err != nil {
+		t.Errorf("FetchTypeChart() error = %v", err)
+		return
+	}
+	want := &model.TypeChart{
+		Types: []string{"fire", "grass"},
+		Multipliers: map[string]map[string]float64{
+			"fire":  {"grass": 2, "fire": 0.5},
+			"grass": {"fire": 0.5},
+		},
+		NaturalGifts: []model.NaturalGift{{Berry: "cheri", Type: "fire", Power: 60}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FetchTypeChart() got = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,266 @@
+// Package search provides an in-memory inverted index over the names of
+// synced resources, with prefix and typo-tolerant matching.
+package search
+
+import (
+	"github.com/inasknh/simple-poke-app/internal/model"
+	"golang.org/x/text/unicode/norm"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Scores of a query term against an indexed term.
+const (
+	exactScore  = 1.0
+	prefixScore = 0.75
+	fuzzyScore  = 0.5
+	// coverageWeight rewards names made of few terms besides the matched ones.
+	coverageWeight = 0.25
+)
+
+// name is one name of a document, the slug has no language.
+type name struct {
+	language string
+	text     string
+	terms    int
+}
+
+// bucket groups the terms that can be typos of each other: a typo keeps the
+// first letter and the length within the tolerated edits.
+type bucket struct {
+	first  rune
+	length int
+}
+
+// posting locates a term in a name of a document.
+type posting struct {
+	doc  int
+	name int
+}
+
+// Index is an immutable inverted index of document names, safe for
+// concurrent searches.
+type Index struct {
+	docs     []model.SearchDocument
+	names    [][]name
+	postings map[string][]posting
+	// terms is every indexed term in order, for prefix lookups.
+	terms []string
+	// buckets are the fuzzy lookup candidates.
+	buckets map[bucket][]string
+}
+
+// NewIndex indexes the slug and every localized name of docs.
+func NewIndex(docs []model.SearchDocument) *Index {
+	idx := &Index{
+		docs:     docs,
+		names:    make([][]name, len(docs)),
+		postings: map[string][]posting{},
+		buckets:  map[bucket][]string{},
+	}
+
+	for d, doc := range docs {
+		idx.add(d, "", doc.Name)
+
+		// languages are walked in order so ties resolve the same way every time
+		languages := make([]string, 0, len(doc.Names))
+		for language := range doc.Names {
+			languages = append(languages, language)
+		}
+		sort.Strings(languages)
+		for _, language := range languages {
+			idx.add(d, language, doc.Names[language])
+		}
+	}
+
+	idx.terms = make([]string, 0, len(idx.postings))
+	for term := range idx.postings {
+		idx.terms = append(idx.terms, term)
+	}
+	sort.Strings(idx.terms)
+	for _, term := range idx.terms {
+		runes := []rune(term)
+		key := bucket{first: runes[0], length: len(runes)}
+		idx.buckets[key] = append(idx.buckets[key], term)
+	}
+
+	return idx
+}
+
+func (idx *Index) add(d int, language, text string) {
+	terms := tokenize(text)
+	if len(terms) == 0 {
+		return
+	}
+
+	n := len(idx.names[d])
+	idx.names[d] = append(idx.names[d], name{language: language, text: text, terms: len(terms)})
+	for _, term := range terms {
+		list := idx.postings[term]
+		// a term repeated in a name is posted once
+		if len(list) > 0 && list[len(list)-1] == (posting{doc: d, name: n}) {
+			continue
+		}
+		idx.postings[term] = append(list, posting{doc: d, name: n})
+	}
+}
+
+// Search returns up to limit documents having a name that matches every
+// term of query exactly, as a prefix or within a few typos, best first.
+func (idx *Index) Search(query string, limit int) []model.SearchResult {
+	queryTerms := tokenize(query)
+	if len(queryTerms) == 0 || limit <= 0 {
+		return []model.SearchResult{}
+	}
+
+	// per name, the summed best score of each query term and how many matched
+	scores := map[posting]float64{}
+	matched := map[posting]int{}
+	for _, queryTerm := range queryTerms {
+		for p, score := range idx.match(queryTerm) {
+			scores[p] += score
+			matched[p]++
+		}
+	}
+
+	best := map[int]model.SearchResult{}
+	for p, count := range matched {
+		if count < len(queryTerms) {
+			continue
+		}
+
+		n := idx.names[p.doc][p.name]
+		score := scores[p]/float64(len(queryTerms)) + coverageWeight*float64(len(queryTerms))/float64(max(n.terms, len(queryTerms)))
+		score = math.Round(score*100) / 100
+
+		current, ok := best[p.doc]
+		if ok && (current.Score > score || (current.Score == score && current.Language <= n.language)) {
+			continue
+		}
+
+		result := model.SearchResult{
+			Type:  idx.docs[p.doc].Type,
+			Name:  idx.docs[p.doc].Name,
+			Score: score,
+		}
+		if n.language != "" {
+			result.Matched = n.text
+			result.Language = n.language
+		}
+		best[p.doc] = result
+	}
+
+	res := make([]model.SearchResult, 0, len(best))
+	for _, result := range best {
+		res = append(res, result)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Score != res[j].Score {
+			return res[i].Score > res[j].Score
+		}
+		if res[i].Type != res[j].Type {
+			return res[i].Type < res[j].Type
+		}
+		return res[i].Name < res[j].Name
+	})
+	if len(res) > limit {
+		res = res[:limit]
+	}
+
+	return res
+}
+
+// match returns the best score of queryTerm in every name containing a
+// matching term.
+func (idx *Index) match(queryTerm string) map[posting]float64 {
+	res := map[posting]float64{}
+	hit := func(term string, score float64) {
+		for _, p := range idx.postings[term] {
+			res[p] = max(res[p], score)
+		}
+	}
+
+	// terms sharing the prefix are contiguous, the exact term comes first
+	for i := sort.SearchStrings(idx.terms, queryTerm); i < len(idx.terms) && strings.HasPrefix(idx.terms[i], queryTerm); i++ {
+		if idx.terms[i] == queryTerm {
+			hit(idx.terms[i], exactScore)
+		} else {
+			hit(idx.terms[i], prefixScore)
+		}
+	}
+
+	query := []rune(queryTerm)
+	edits := maxEdits(len(query))
+	if edits == 0 {
+		return res
+	}
+	for length := len(query) - edits; length <= len(query)+edits; length++ {
+		for _, term := range idx.buckets[bucket{first: query[0], length: length}] {
+			if d := distance(query, []rune(term)); d > 0 && d <= edits {
+				hit(term, fuzzyScore/float64(d))
+			}
+		}
+	}
+
+	return res
+}
+
+// maxEdits is how many typos a query term of length runes tolerates, short
+// terms must be spelled right.
+func maxEdits(length int) int {
+	switch {
+	case length < 4:
+		return 0
+	case length < 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// distance is the optimal string alignment distance between a and b:
+// insertions, deletions, substitutions and transpositions of adjacent runes.
+func distance(a, b []rune) int {
+	rows := make([][]int, len(a)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(b)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			rows[i][j] = min(rows[i-1][j]+1, rows[i][j-1]+1, rows[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				rows[i][j] = min(rows[i][j], rows[i-2][j-2]+1)
+			}
+		}
+	}
+
+	return rows[len(a)][len(b)]
+}
+
+// tokenize lower-cases text, strips diacritics and splits it on anything
+// but letters and digits, so "Pokémon" and "master-ball" match "pokemon"
+// and "master ball".
+func tokenize(text string) []string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(text) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+
+	return strings.FieldsFunc(b.String(), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
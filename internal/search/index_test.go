@@ -0,0 +1,140 @@
+package search
+
+import (
+	"github.com/inasknh/simple-poke-app/internal/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var docs = []model.SearchDocument{
+	{Type: model.SearchTypeBerry, Name: "cheri", Names: map[string]string{"en": "Cheri Berry", "fr": "Baie Ceriz", "ja": "クラボのみ"}},
+	{Type: model.SearchTypeItem, Name: "cheri-berry", Names: map[string]string{"en": "Cheri Berry", "fr": "Baie Ceriz", "ja": "クラボのみ"}},
+	{Type: model.SearchTypeItem, Name: "master-ball", Names: map[string]string{"en": "Master Ball", "fr": "Master Ball"}},
+	{Type: model.SearchTypeItem, Name: "potion", Names: map[string]string{"en": "Potion", "fr": "Potion"}},
+	{Type: model.SearchTypeItem, Name: "super-potion", Names: map[string]string{"en": "Super Potion", "fr": "Super Potion"}},
+	{Type: model.SearchTypePokemon, Name: "bulbasaur"},
+	{Type: model.SearchTypePokemon, Name: "charmander", Names: map[string]string{"en": "Charmander", "fr": "Salamèche", "ja": "ヒトカゲ"}},
+	{Type: model.SearchTypePokemon, Name: "charmeleon"},
+}
+
+func TestIndex_Search(t *testing.T) {
+	index := NewIndex(docs)
+
+	tests := []struct {
+		name  string
+		query string
+		limit int
+		want  []model.SearchResult
+	}{
+		{
+			name:  "given an exact name should rank the shortest name first",
+			query: "potion",
+			limit: 10,
+			want: []model.SearchResult{
+				{Type: model.SearchTypeItem, Name: "potion", Score: 1.25},
+				{Type: model.SearchTypeItem, Name: "super-potion", Score: 1.13},
+			},
+		},
+		{
+			name:  "given a prefix should match every name starting with it",
+			query: "charm",
+			limit: 10,
+			want: []model.SearchResult{
+				{Type: model.SearchTypePokemon, Name: "charmander", Score: 1},
+				{Type: model.SearchTypePokemon, Name: "charmeleon", Score: 1},
+			},
+		},
+		{
+			name:  "given a typo should match within an edit",
+			query: "bulbsaur",
+			limit: 10,
+			want: []model.SearchResult{
+				{Type: model.SearchTypePokemon, Name: "bulbasaur", Score: 0.75},
+			},
+		},
+		{
+			name:  "given a typo in the first letter should not match",
+			query: "vulbasaur",
+			limit: 10,
+			want:  []model.SearchResult{},
+		},
+		{
+			name:  "given a localized name with diacritics should match it in any case",
+			query: "BAIE CÉRIZ",
+			limit: 10,
+			want: []model.SearchResult{
+				{Type: model.SearchTypeBerry, Name: "cheri", Matched: "Baie Ceriz", Language: "fr", Score: 1.25},
+				{Type: model.SearchTypeItem, Name: "cheri-berry", Matched: "Baie Ceriz", Language: "fr", Score: 1.25},
+			},
+		},
+		{
+			name:  "given the localized name of a Pokémon should match it",
+			query: "salameche",
+			limit: 10,
+			want: []model.SearchResult{
+				{Type: model.SearchTypePokemon, Name: "charmander", Matched: "Salamèche", Language: "fr", Score: 1.25},
+			},
+		},
+		{
+			name:  "given a localized prefix without spaces should match it",
+			query: "クラボ",
+			limit: 10,
+			want: []model.SearchResult{
+				{Type: model.SearchTypeBerry, Name: "cheri", Matched: "クラボのみ", Language: "ja", Score: 1},
+				{Type: model.SearchTypeItem, Name: "cheri-berry", Matched: "クラボのみ", Language: "ja", Score: 1},
+			},
+		},
+		{
+			name:  "given several terms should match names having all of them",
+			query: "ball master",
+			limit: 10,
+			want: []model.SearchResult{
+				{Type: model.SearchTypeItem, Name: "master-ball", Score: 1.25},
+			},
+		},
+		{
+			name:  "given a limit should return the best results only",
+			query: "charm",
+			limit: 1,
+			want: []model.SearchResult{
+				{Type: model.SearchTypePokemon, Name: "charmander", Score: 1},
+			},
+		},
+		{
+			name:  "given a short query with a typo should not match",
+			query: "pto",
+			limit: 10,
+			want:  []model.SearchResult{},
+		},
+		{
+			name:  "given a query without terms should return nothing",
+			query: " - ",
+			limit: 10,
+			want:  []model.SearchResult{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, index.Search(tt.query, tt.limit))
+		})
+	}
+}
+
+func Test_distance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "potion", b: "potion", want: 0},
+		{a: "potoin", b: "potion", want: 1},
+		{a: "poton", b: "potion", want: 1},
+		{a: "pation", b: "potion", want: 1},
+		{a: "bulbsaur", b: "bulbasaur", want: 1},
+		{a: "charmander", b: "charmeleon", want: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			assert.Equal(t, tt.want, distance([]rune(tt.a), []rune(tt.b)))
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/inasknh/simple-poke-app/internal/model"
+	"io"
+	"log"
+	"net/http"
+)
+
+const (
+	assetKeyPrefix = "asset:"
+	// opaqueAssetType is served for assets recorded with another type than
+	// imageTypes.
+	opaqueAssetType = "application/octet-stream"
+)
+
+// imageTypes are the types assets are mirrored as, sniffed from their
+// content rather than trusted from upstream.
+var imageTypes = map[string]bool{
+	"image/gif":  true,
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/webp": true,
+}
+
+// mirrorAsset copies the file at url to the blob store and returns the url
+// it is served from. The upstream url is kept when it cannot be mirrored.
+func (s *service) mirrorAsset(ctx context.Context, url string) string {
+	if s.assets == nil || url == "" {
+		return url
+	}
+
+	hash, err := s.storeAsset(ctx, url)
+	if err != nil {
+		log.Printf("failed to mirror asset %s: %v", url, err)
+		return url
+	}
+
+	return s.assets.BaseURL + hash
+}
+
+// storeAsset returns the hash of the blob holding url, downloading it
+// unless it was already mirrored.
+func (s *service) storeAsset(ctx context.Context, url string) (string, error) {
+	asset, err := s.dbRepository.FetchAssetByURL(ctx, url)
+	switch {
+	case err == nil:
+		stored, err := s.assets.Store.Exists(ctx, asset.Hash)
+		if err != nil {
+			return "", err
+		}
+		if stored {
+			return asset.Hash, nil
+		}
+	case !errors.Is(err, model.ErrNotFound):
+		return "", err
+	}
+
+	download, err := s.assets.Downloader.Download(ctx, url)
+	if err != nil {
+		return "", err
+	}
+	contentType := http.DetectContentType(download.Data)
+	if !imageTypes[contentType] {
+		return "", fmt.Errorf("unsupported asset type %q", contentType)
+	}
+
+	sum := sha256.Sum256(download.Data)
+	hash := hex.EncodeToString(sum[:])
+
+	// identical content mirrored from another url is stored once
+	stored, err := s.assets.Store.Exists(ctx, hash)
+	if err != nil {
+		return "", err
+	}
+	if !stored {
+		if err = s.assets.Store.Put(ctx, hash, download.Data); err != nil {
+			return "", err
+		}
+	}
+
+	err = s.dbRepository.CreateAsset(ctx, model.Asset{
+		URL:         url,
+		Hash:        hash,
+		ContentType: contentType,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return hash, nil
+}
+
+func (s *service) GetAsset(ctx context.Context, hash string) (*model.Asset, io.ReadSeekCloser, error) {
+	if s.assets == nil {
+		return nil, nil, model.ErrNotFound
+	}
+
+	// blobs are immutable, so is what is known about them
+	asset, _, err := cachedValue(ctx, s, assetKeyPrefix+hash, func(ctx context.Context) (*model.Asset, error) {
+		return s.dbRepository.FetchAsset(ctx, hash)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if !imageTypes[asset.ContentType] {
+		asset.ContentType = opaqueAssetType
+	}
+
+	blob, err := s.assets.Store.Open(ctx, hash)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return asset, blob, nil
+}
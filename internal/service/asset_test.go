@@ -0,0 +1,253 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/go-resty/resty/v2"
+	"github.com/inasknh/simple-poke-app/internal/api"
+	"github.com/inasknh/simple-poke-app/internal/fakepokeapi"
+	mocks2 "github.com/inasknh/simple-poke-app/internal/mocks/api"
+	mocks "github.com/inasknh/simple-poke-app/internal/mocks/repository"
+	"github.com/inasknh/simple-poke-app/internal/model"
+	"github.com/inasknh/simple-poke-app/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_service_mirrorAsset(t *testing.T) {
+	const url = "https://sprites/items/potion.png"
+	data := []byte("\x89PNG\r\n\x1a\nsprite")
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+
+	tests := []struct {
+		name     string
+		url      string
+		want     string
+		mockFunc func(mockDB *mocks.Repository, mockStore *mocks.BlobStore, mockDownloader *mocks2.Downloader)
+	}{
+		{
+			name:     "given no sprite should keep it empty",
+			url:      "",
+			want:     "",
+			mockFunc: func(mockDB *mocks.Repository, mockStore *mocks.BlobStore, mockDownloader *mocks2.Downloader) {},
+		},
+		{
+			name: "given a mirrored url with its blob stored should not download it again",
+			url:  url,
+			want: "/assets/" + hash,
+			mockFunc: func(mockDB *mocks.Repository, mockStore *mocks.BlobStore, mockDownloader *mocks2.Downloader) {
+				mockDB.On("FetchAssetByURL", mock.Anything, url).Return(&model.Asset{URL: url, Hash: hash}, nil)
+				mockStore.On("Exists", mock.Anything, hash).Return(true, nil)
+			},
+		},
+		{
+			name: "given a new url should download and store it",
+			url:  url,
+			want: "/assets/" + hash,
+			mockFunc: func(mockDB *mocks.Repository, mockStore *mocks.BlobStore, mockDownloader *mocks2.Downloader) {
+				mockDB.On("FetchAssetByURL", mock.Anything, url).Return(nil, model.ErrNotFound)
+				mockDownloader.On("Download", mock.Anything, url).Return(&api.Download{Data: data}, nil)
+				mockStore.On("Exists", mock.Anything, hash).Return(false, nil)
+				mockStore.On("Put", mock.Anything, hash, data).Return(nil)
+				mockDB.On("CreateAsset", mock.Anything, model.Asset{URL: url, Hash: hash, ContentType: "image/png"}).Return(nil)
+			},
+		},
+		{
+			name: "given a new url with content already stored should only record it",
+			url:  url,
+			want: "/assets/" + hash,
+			mockFunc: func(mockDB *mocks.Repository, mockStore *mocks.BlobStore, mockDownloader *mocks2.Downloader) {
+				mockDB.On("FetchAssetByURL", mock.Anything, url).Return(nil, model.ErrNotFound)
+				mockDownloader.On("Download", mock.Anything, url).Return(&api.Download{Data: data}, nil)
+				mockStore.On("Exists", mock.Anything, hash).Return(true, nil)
+				mockDB.On("CreateAsset", mock.Anything, model.Asset{URL: url, Hash: hash, ContentType: "image/png"}).Return(nil)
+			},
+		},
+		{
+			name: "given a mirrored url with its blob missing should download it again",
+			url:  url,
+			want: "/assets/" + hash,
+			mockFunc: func(mockDB *mocks.Repository, mockStore *mocks.BlobStore, mockDownloader *mocks2.Downloader) {
+				mockDB.On("FetchAssetByURL", mock.Anything, url).Return(&model.Asset{URL: url, Hash: hash}, nil)
+				mockStore.On("Exists", mock.Anything, hash).Return(false, nil)
+				mockDownloader.On("Download", mock.Anything, url).Return(&api.Download{Data: data}, nil)
+				mockStore.On("Put", mock.Anything, hash, data).Return(nil)
+				mockDB.On("CreateAsset", mock.Anything, mock.Anything).Return(nil)
+			},
+		},
+		{
+			name: "given a download error should keep the upstream url",
+			url:  url,
+			want: url,
+			mockFunc: func(mockDB *mocks.Repository, mockStore *mocks.BlobStore, mockDownloader *mocks2.Downloader) {
+				mockDB.On("FetchAssetByURL", mock.Anything, url).Return(nil, model.ErrNotFound)
+				mockDownloader.On("Download", mock.Anything, url).Return(nil, api.ErrNotFound)
+			},
+		},
+		{
+			name: "given content that is not an image should keep the upstream url",
+			url:  url,
+			want: url,
+			mockFunc: func(mockDB *mocks.Repository, mockStore *mocks.BlobStore, mockDownloader *mocks2.Downloader) {
+				mockDB.On("FetchAssetByURL", mock.Anything, url).Return(nil, model.ErrNotFound)
+				mockDownloader.On("Download", mock.Anything, url).Return(&api.Download{Data: []byte("<html><script></script>")}, nil)
+			},
+		},
+		{
+			name: "given an error when storing the blob should keep the upstream url",
+			url:  url,
+			want: url,
+			mockFunc: func(mockDB *mocks.Repository, mockStore *mocks.BlobStore, mockDownloader *mocks2.Downloader) {
+				mockDB.On("FetchAssetByURL", mock.Anything, url).Return(nil, model.ErrNotFound)
+				mockDownloader.On("Download", mock.Anything, url).Return(&api.Download{Data: data}, nil)
+				mockStore.On("Exists", mock.Anything, hash).Return(false, nil)
+				mockStore.On("Put", mock.Anything, hash, data).Return(errors.New("any error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := &mocks.Repository{}
+			mockStore := &mocks.BlobStore{}
+			mockDownloader := &mocks2.Downloader{}
+			tt.mockFunc(mockDB, mockStore, mockDownloader)
+			s := &service{
+				dbRepository: mockDB,
+				assets: &Assets{
+					Store:      mockStore,
+					Downloader: mockDownloader,
+					BaseURL:    "/assets/",
+				},
+			}
+
+			assert.Equal(t, tt.want, s.mirrorAsset(context.Background(), tt.url))
+			mockDB.AssertExpectations(t)
+			mockStore.AssertExpectations(t)
+			mockDownloader.AssertExpectations(t)
+		})
+	}
+
+	t.Run("given no blob store should keep the upstream url", func(t *testing.T) {
+		s := &service{}
+		assert.Equal(t, url, s.mirrorAsset(context.Background(), url))
+	})
+}
+
+func Test_service_GetAsset(t *testing.T) {
+	hash := strings.Repeat("ab", 32)
+	asset := &model.Asset{URL: "https://sprites/items/potion.png", Hash: hash, ContentType: "image/png"}
+
+	t.Run("given a cached asset should open its blob", func(t *testing.T) {
+		mockRedis := &mocks.RedisRepository{}
+		mockStore := &mocks.BlobStore{}
+		mockRedis.On("GetValue", mock.Anything, "asset:"+hash, mock.Anything).
+			Run(func(args mock.Arguments) {
+				*args.Get(2).(*model.Asset) = *asset
+			}).
+			Return(true, nil)
+		mockStore.On("Open", mock.Anything, hash).Return(nopReadSeekCloser{strings.NewReader("sprite")}, nil)
+		s := &service{redisRepository: mockRedis, assets: &Assets{Store: mockStore}}
+
+		got, blob, err := s.GetAsset(context.Background(), hash)
+		assert.NoError(t, err)
+		assert.Equal(t, asset, got)
+		data, _ := io.ReadAll(blob)
+		assert.Equal(t, "sprite", string(data))
+	})
+
+	t.Run("given an asset recorded with another type than an image should serve it as opaque", func(t *testing.T) {
+		mockRedis := &mocks.RedisRepository{}
+		mockStore := &mocks.BlobStore{}
+		mockRedis.On("GetValue", mock.Anything, "asset:"+hash, mock.Anything).
+			Run(func(args mock.Arguments) {
+				*args.Get(2).(*model.Asset) = model.Asset{URL: asset.URL, Hash: hash, ContentType: "text/html"}
+			}).
+			Return(true, nil)
+		mockStore.On("Open", mock.Anything, hash).Return(nopReadSeekCloser{strings.NewReader("sprite")}, nil)
+		s := &service{redisRepository: mockRedis, assets: &Assets{Store: mockStore}}
+
+		got, _, err := s.GetAsset(context.Background(), hash)
+		assert.NoError(t, err)
+		assert.Equal(t, opaqueAssetType, got.ContentType)
+	})
+
+	t.Run("given an unknown hash should return ErrNotFound", func(t *testing.T) {
+		mockDB := &mocks.Repository{}
+		mockRedis := &mocks.RedisRepository{}
+		mockRedis.On("GetValue", mock.Anything, "asset:"+hash, mock.Anything).Return(false, nil)
+		mockDB.On("FetchAsset", mock.Anything, hash).Return(nil, model.ErrNotFound)
+		s := &service{dbRepository: mockDB, redisRepository: mockRedis, assets: &Assets{Store: &mocks.BlobStore{}}}
+
+		_, _, err := s.GetAsset(context.Background(), hash)
+		assert.ErrorIs(t, err, model.ErrNotFound)
+	})
+
+	t.Run("given no blob store should return ErrNotFound", func(t *testing.T) {
+		s := &service{}
+
+		_, _, err := s.GetAsset(context.Background(), hash)
+		assert.ErrorIs(t, err, model.ErrNotFound)
+	})
+}
+
+func Test_service_SyncPokemon_FakePokeAPI_MirrorsSprites(t *testing.T) {
+	server := fakepokeapi.New()
+	defer server.Close()
+
+	mockDB := &mocks.Repository{}
+	var created []model.Pokemon
+	mockDB.
+		On("CreatePokemon", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			created = append(created, args.Get(1).(model.Pokemon))
+		}).
+		Return(nil)
+	mockDB.On("FetchPokemonList", mock.Anything).Return(&model.PokemonListResponse{}, nil)
+	mockDB.On("FetchAssetByURL", mock.Anything, mock.Anything).Return(nil, model.ErrNotFound)
+	mockDB.On("CreateAsset", mock.Anything, mock.Anything).Return(nil)
+
+	dir := t.TempDir()
+	s := newFakeAPIService(server, mockDB)
+	s.assets = &Assets{
+		Store:      repository.NewFileBlobStore(dir),
+		Downloader: api.NewDownloader(resty.New()),
+		BaseURL:    "/assets/",
+	}
+
+	err := s.SyncPokemon(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, created, 3)
+	// list, then a pokemon, its species and its sprite for each of them
+	assert.Equal(t, 10, server.Requests())
+
+	// every fake sprite has the same content and is stored once
+	blobs, _ := filepath.Glob(filepath.Join(dir, "*", "*"))
+	assert.Len(t, blobs, 1)
+	for _, pokemon := range created {
+		assert.Equal(t, "/assets/"+filepath.Base(blobs[0]), pokemon.Sprite)
+	}
+	mockDB.AssertNumberOfCalls(t, "CreateAsset", 3)
+	mockDB.AssertCalled(t, "CreateAsset", mock.Anything, mock.MatchedBy(func(asset model.Asset) bool {
+		return asset.URL == server.URL+"/sprites/pokemon/1.png" && asset.ContentType == "image/png"
+	}))
+
+	data, err := os.ReadFile(blobs[0])
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "\x89PNG"))
+}
+
+type nopReadSeekCloser struct {
+	io.ReadSeeker
+}
+
+func (nopReadSeekCloser) Close() error {
+	return nil
+}
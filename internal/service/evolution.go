@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"github.com/inasknh/simple-poke-app/internal/api"
+	"github.com/inasknh/simple-poke-app/internal/model"
+	"log"
+	"slices"
+	"strconv"
+)
+
+const evolutionKeyPrefix = "evolution:"
+
+func (s *service) SyncEvolutionChains(ctx context.Context) error {
+	return syncEach(ctx, s.client, func(ctx context.Context, chain *api.EvolutionChain) error {
+		res := constructEvolutionChain(chain)
+		if err := s.dbRepository.CreateEvolutionChain(ctx, res); err != nil {
+			return err
+		}
+
+		// every species of the chain is served the same graph
+		for _, species := range res.Species {
+			if err := s.redisRepository.SetValue(ctx, evolutionKey(species.Name), &res); err != nil {
+				log.Printf("failed to cache evolution chain of %q: %v", species.Name, err)
+			}
+		}
+		return nil
+	})
+}
+
+func constructEvolutionChain(chain *api.EvolutionChain) model.EvolutionChain {
+	res := model.EvolutionChain{
+		ID:         chain.ID,
+		Species:    []model.EvolutionSpecies{},
+		Evolutions: []model.Evolution{},
+	}
+	if chain.BabyTriggerItem != nil {
+		res.BabyTriggerItem = chain.BabyTriggerItem.Name
+	}
+
+	var walk func(link api.ChainLink, from string)
+	walk = func(link api.ChainLink, from string) {
+		res.Species = append(res.Species, model.EvolutionSpecies{
+			ID:     link.Species.ID(),
+			Name:   link.Species.Name,
+			IsBaby: link.IsBaby,
+		})
+
+		if from != "" {
+			details := link.EvolutionDetails
+			if len(details) == 0 {
+				// keep the species linked even when PokeAPI does not know how it evolves
+				details = []api.EvolutionDetail{{}}
+			}
+			for _, detail := range details {
+				res.Evolutions = append(res.Evolutions, model.Evolution{
+					From:                 from,
+					To:                   link.Species.Name,
+					EvolutionRequirement: constructEvolutionRequirement(detail),
+				})
+			}
+		}
+
+		for _, next := range link.EvolvesTo {
+			walk(next, link.Species.Name)
+		}
+	}
+	walk(chain.Chain, "")
+
+	return res
+}
+
+func constructEvolutionRequirement(detail api.EvolutionDetail) model.EvolutionRequirement {
+	res := model.EvolutionRequirement{Trigger: detail.Trigger.Name}
+	if detail.Item != nil {
+		res.Item = detail.Item.Name
+	}
+	if detail.HeldItem != nil {
+		res.HeldItem = detail.HeldItem.Name
+	}
+
+	conditions := map[string]string{}
+	ints := map[string]*int{
+		"gender":                  detail.Gender,
+		"min_level":               detail.MinLevel,
+		"min_happiness":           detail.MinHappiness,
+		"min_beauty":              detail.MinBeauty,
+		"min_affection":           detail.MinAffection,
+		"relative_physical_stats": detail.RelativePhysicalStats,
+	}
+	for name, v := range ints {
+		if v != nil {
+			conditions[name] = strconv.Itoa(*v)
+		}
+	}
+	resources := map[string]*api.NamedAPIResource{
+		"known_move":      detail.KnownMove,
+		"known_move_type": detail.KnownMoveType,
+		"location":        detail.Location,
+		"party_species":   detail.PartySpecies,
+		"party_type":      detail.PartyType,
+		"trade_species":   detail.TradeSpecies,
+	}
+	for name, r := range resources {
+		if r != nil {
+			conditions[name] = r.Name
+		}
+	}
+	if detail.TimeOfDay != "" {
+		conditions["time_of_day"] = detail.TimeOfDay
+	}
+	if detail.NeedsOverworldRain {
+		conditions["needs_overworld_rain"] = "true"
+	}
+	if detail.TurnUpsideDown {
+		conditions["turn_upside_down"] = "true"
+	}
+
+	if len(conditions) > 0 {
+		res.Conditions = conditions
+	}
+	return res
+}
+
+func (s *service) GetEvolutionChain(ctx context.Context, species string) (*model.EvolutionChainResponse, CacheStatus, error) {
+	chain, status, err := s.evolutionChain(ctx, species)
+	if err != nil {
+		return nil, status, err
+	}
+
+	return &model.EvolutionChainResponse{
+		ID:              chain.ID,
+		BabyTriggerItem: chain.BabyTriggerItem,
+		Chain:           evolutionTree(chain),
+	}, status, nil
+}
+
+func (s *service) GetEvolutionLinks(ctx context.Context, species string) (*model.EvolutionLinksResponse, CacheStatus, error) {
+	chain, status, err := s.evolutionChain(ctx, species)
+	if err != nil {
+		return nil, status, err
+	}
+
+	res := &model.EvolutionLinksResponse{
+		Species:     species,
+		EvolvesFrom: []model.Evolution{},
+		EvolvesTo:   []model.Evolution{},
+	}
+	for _, evolution := range chain.Evolutions {
+		if evolution.To == species {
+			res.EvolvesFrom = append(res.EvolvesFrom, evolution)
+		}
+		if evolution.From == species {
+			res.EvolvesTo = append(res.EvolvesTo, evolution)
+		}
+	}
+
+	return res, status, nil
+}
+
+func (s *service) evolutionChain(ctx context.Context, species string) (*model.EvolutionChain, CacheStatus, error) {
+	return cachedValue(ctx, s, evolutionKey(species), func(ctx context.Context) (*model.EvolutionChain, error) {
+		return s.dbRepository.FetchEvolutionChain(ctx, species)
+	})
+}
+
+// evolutionTree nests the chain graph from the species nothing evolves into.
+func evolutionTree(chain *model.EvolutionChain) model.EvolutionNode {
+	requirements := map[string][]model.EvolutionRequirement{}
+	children := map[string][]string{}
+	for _, evolution := range chain.Evolutions {
+		if _, ok := requirements[evolution.To]; !ok {
+			children[evolution.From] = append(children[evolution.From], evolution.To)
+		}
+		requirements[evolution.To] = append(requirements[evolution.To], evolution.EvolutionRequirement)
+	}
+
+	var build func(species model.EvolutionSpecies) model.EvolutionNode
+	build = func(species model.EvolutionSpecies) model.EvolutionNode {
+		node := model.EvolutionNode{
+			Species:      species.Name,
+			IsBaby:       species.IsBaby,
+			Requirements: requirements[species.Name],
+			EvolvesTo:    []model.EvolutionNode{},
+		}
+		for _, child := range children[species.Name] {
+			i := slices.IndexFunc(chain.Species, func(s model.EvolutionSpecies) bool { return s.Name == child })
+			if i >= 0 {
+				node.EvolvesTo = append(node.EvolvesTo, build(chain.Species[i]))
+			}
+		}
+		return node
+	}
+
+	for _, species := range chain.Species {
+		if _, ok := requirements[species.Name]; !ok {
+			return build(species)
+		}
+	}
+	return model.EvolutionNode{EvolvesTo: []model.EvolutionNode{}}
+}
+
+func evolutionKey(species string) string {
+	return evolutionKeyPrefix + species
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/inasknh/simple-poke-app/internal/fakepokeapi"
+	mocks "github.com/inasknh/simple-poke-app/internal/mocks/repository"
+	"github.com/inasknh/simple-poke-app/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func Test_service_SyncEvolutionChains_FakePokeAPI(t *testing.T) {
+	server := fakepokeapi.New()
+	defer server.Close()
+
+	mockDB := &mocks.Repository{}
+	var created []model.EvolutionChain
+	mockDB.
+		On("CreateEvolutionChain", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			created = append(created, args.Get(1).(model.EvolutionChain))
+		}).
+		Return(nil)
+	s := newFakeAPIService(server, mockDB)
+
+	err := s.SyncEvolutionChains(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, created, 4)
+	// list, then every chain by id
+	assert.Equal(t, 5, server.Requests())
+
+	bulbasaur := created[0]
+	assert.Equal(t, []model.EvolutionSpecies{{ID: 1, Name: "bulbasaur"}, {ID: 2, Name: "ivysaur"}, {ID: 3, Name: "venusaur"}}, bulbasaur.Species)
+	assert.Equal(t, model.Evolution{
+		From: "bulbasaur",
+		To:   "ivysaur",
+		EvolutionRequirement: model.EvolutionRequirement{
+			Trigger:    "level-up",
+			Conditions: map[string]string{"min_level": "16"},
+		},
+	}, bulbasaur.Evolutions[0])
+
+	eevee := created[3]
+	assert.Len(t, eevee.Evolutions, 5)
+	assert.Equal(t, model.EvolutionRequirement{Trigger: "use-item", Item: "water-stone"}, eevee.Evolutions[0].EvolutionRequirement)
+	assert.Equal(t, map[string]string{"min_happiness": "160", "time_of_day": "night"}, eevee.Evolutions[4].Conditions)
+
+	mockRedis := s.redisRepository.(*mocks.RedisRepository)
+	mockRedis.AssertCalled(t, "SetValue", mock.Anything, "evolution:venusaur", &bulbasaur)
+}
+
+func Test_service_SyncEvolutionChains_FakePokeAPI_Error(t *testing.T) {
+	server := fakepokeapi.New()
+	defer server.Close()
+
+	mockDB := &mocks.Repository{}
+	mockDB.On("CreateEvolutionChain", mock.Anything, mock.Anything).Return(errors.New("an error"))
+	s := newFakeAPIService(server, mockDB)
+
+	err := s.SyncEvolutionChains(context.Background())
+	assert.Error(t, err)
+	mockDB.AssertNumberOfCalls(t, "CreateEvolutionChain", 1)
+	s.redisRepository.(*mocks.RedisRepository).AssertNotCalled(t, "SetValue", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// eeveeChain branches from the root and has a second stage on one branch.
+var eeveeChain = &model.EvolutionChain{
+	ID: 67,
+	Species: []model.EvolutionSpecies{
+		{ID: 133, Name: "eevee"},
+		{ID: 134, Name: "vaporeon"},
+		{ID: 196, Name: "espeon"},
+		{ID: 1000, Name: "mega-espeon"},
+	},
+	Evolutions: []model.Evolution{
+		{From: "eevee", To: "vaporeon", EvolutionRequirement: model.EvolutionRequirement{Trigger: "use-item", Item: "water-stone"}},
+		{From: "eevee", To: "espeon", EvolutionRequirement: model.EvolutionRequirement{Trigger: "level-up", Conditions: map[string]string{"time_of_day": "day"}}},
+		{From: "eevee", To: "espeon", EvolutionRequirement: model.EvolutionRequirement{Trigger: "use-item", Item: "sun-shard"}},
+		{From: "espeon", To: "mega-espeon", EvolutionRequirement: model.EvolutionRequirement{Trigger: "other"}},
+	},
+}
+
+func Test_service_GetEvolutionChain(t *testing.T) {
+	tests := []struct {
+		name     string
+		want     *model.EvolutionChainResponse
+		wantErr  error
+		mockFunc func(mockDB *mocks.Repository, mockRedis *mocks.RedisRepository)
+	}{
+		{
+			name: "given a cached chain should nest it from its first species",
+			want: &model.EvolutionChainResponse{
+				ID: 67,
+				Chain: model.EvolutionNode{
+					Species: "eevee",
+					EvolvesTo: []model.EvolutionNode{
+						{
+							Species:      "vaporeon",
+							Requirements: []model.EvolutionRequirement{{Trigger: "use-item", Item: "water-stone"}},
+							EvolvesTo:    []model.EvolutionNode{},
+						},
+						{
+							Species: "espeon",
+							Requirements: []model.EvolutionRequirement{
+								{Trigger: "level-up", Conditions: map[string]string{"time_of_day": "day"}},
+								{Trigger: "use-item", Item: "sun-shard"},
+							},
+							EvolvesTo: []model.EvolutionNode{
+								{
+									Species:      "mega-espeon",
+									Requirements: []model.EvolutionRequirement{{Trigger: "other"}},
+									EvolvesTo:    []model.EvolutionNode{},
+								},
+							},
+						},
+					},
+				},
+			},
+			mockFunc: func(mockDB *mocks.Repository, mockRedis *mocks.RedisRepository) {
+				mockRedis.
+					On("GetValue", mock.Anything, "evolution:espeon", mock.Anything).
+					Run(func(args mock.Arguments) {
+						*args.Get(2).(*model.EvolutionChain) = *eeveeChain
+					}).
+					Return(true, nil)
+			},
+		},
+		{
+			name:    "given unknown species should return ErrNotFound",
+			want:    nil,
+			wantErr: model.ErrNotFound,
+			mockFunc: func(mockDB *mocks.Repository, mockRedis *mocks.RedisRepository) {
+				mockRedis.On("GetValue", mock.Anything, "evolution:espeon", mock.Anything).Return(false, nil)
+				mockDB.On("FetchEvolutionChain", mock.Anything, "espeon").Return(nil, model.ErrNotFound)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := &mocks.Repository{}
+			mockRedis := &mocks.RedisRepository{}
+			tt.mockFunc(mockDB, mockRedis)
+			s := &service{
+				dbRepository:    mockDB,
+				redisRepository: mockRedis,
+			}
+
+			got, _, err := s.GetEvolutionChain(context.Background(), "espeon")
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_service_GetEvolutionLinks(t *testing.T) {
+	mockDB := &mocks.Repository{}
+	mockRedis := &mocks.RedisRepository{}
+	mockRedis.On("GetValue", mock.Anything, "evolution:espeon", mock.Anything).Return(false, nil)
+	mockDB.On("FetchEvolutionChain", mock.Anything, "espeon").Return(eeveeChain, nil)
+	mockRedis.On("SetValue", mock.Anything, "evolution:espeon", eeveeChain).Return(nil)
+	s := &service{
+		dbRepository:    mockDB,
+		redisRepository: mockRedis,
+	}
+
+	got, status, err := s.GetEvolutionLinks(context.Background(), "espeon")
+	assert.NoError(t, err)
+	assert.Equal(t, CacheMiss, status)
+	assert.Equal(t, &model.EvolutionLinksResponse{
+		Species:     "espeon",
+		EvolvesFrom: eeveeChain.Evolutions[1:3],
+		EvolvesTo:   eeveeChain.Evolutions[3:],
+	}, got)
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"github.com/inasknh/simple-poke-app/internal/api"
+	"github.com/inasknh/simple-poke-app/internal/model"
+	"log"
+	"slices"
+	"strings"
+)
+
+const (
+	catalogueKey           = "catalogue"
+	catalogueItemKeyPrefix = "catalogue:"
+	effectLanguage         = model.DefaultLanguage
+)
+
+func (s *service) SyncItems(ctx context.Context) error {
+	err := syncEach(ctx, s.client, func(ctx context.Context, item *api.Item) error {
+		res := constructItem(item)
+		res.Sprite = s.mirrorAsset(ctx, res.Sprite)
+		if err := s.dbRepository.CreateItem(ctx, res); err != nil {
+			return err
+		}
+
+		// the berry link is only known once berries are walked below
+		if _, err := s.redisRepository.DeleteKey(ctx, catalogueItemKey(res.Name)); err != nil {
+			log.Printf("failed to invalidate item %q: %v", res.Name, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = syncEach(ctx, s.client, func(ctx context.Context, berry *api.Berry) error {
+		if err := s.dbRepository.LinkBerryItem(ctx, berry.Name, berry.Item.ID()); err != nil {
+			return err
+		}
+
+		// the berry is served with the sprite of its item
+		if _, err := s.redisRepository.DeleteKey(ctx, berryKey(berry.Name)); err != nil {
+			log.Printf("failed to invalidate berry %q: %v", berry.Name, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// berries are translated through their item, bump the listing version
+	// so clients and every cached language pick the new names up
+	if _, err = s.dbRepository.CreateSyncRun(ctx); err != nil {
+		return err
+	}
+	if err = s.redisRepository.DeleteData(ctx); err != nil {
+		log.Printf("failed to invalidate items cache after item sync: %v", err)
+	}
+	// the item category tells which berries confuse
+	if _, err = s.redisRepository.DeleteKey(ctx, flavorProfilesKey); err != nil {
+		log.Printf("failed to invalidate flavor profiles: %v", err)
+	}
+
+	catalogue, err := s.dbRepository.FetchItemCatalogue(ctx)
+	if err != nil {
+		return err
+	}
+	if err = s.redisRepository.SetValue(ctx, catalogueKey, catalogue); err != nil {
+		log.Printf("failed to cache item catalogue: %v", err)
+	}
+	s.rebuildSearchIndex(ctx)
+
+	return nil
+}
+
+func constructItem(item *api.Item) model.Item {
+	res := model.Item{
+		ID:         item.ID,
+		Name:       item.Name,
+		Cost:       item.Cost,
+		FlingPower: item.FlingPower,
+		Category: model.ItemRef{
+			ID:   item.Category.ID(),
+			Name: item.Category.Name,
+		},
+		Attributes: make([]model.ItemRef, 0, len(item.Attributes)),
+		Sprite:     item.Sprites.Default,
+	}
+
+	if item.FlingEffect != nil {
+		res.FlingEffect = &model.ItemRef{
+			ID:   item.FlingEffect.ID(),
+			Name: item.FlingEffect.Name,
+		}
+	}
+	for _, attribute := range item.Attributes {
+		res.Attributes = append(res.Attributes, model.ItemRef{
+			ID:   attribute.ID(),
+			Name: attribute.Name,
+		})
+	}
+	for _, entry := range item.EffectEntries {
+		if entry.Language.Name == effectLanguage {
+			res.Effect = entry.ShortEffect
+			break
+		}
+	}
+	res.Translations = constructTranslations(item.Names, item.FlavorTextEntries)
+
+	return res
+}
+
+// constructTranslations pairs every localized name with the flavor text of
+// the latest version group in the same language.
+func constructTranslations(names []api.Name, flavorTexts []api.VersionGroupFlavorText) []model.Translation {
+	descriptions := make(map[string]string, len(flavorTexts))
+	for _, entry := range flavorTexts {
+		// entries are listed from the oldest version group, later ones win
+		descriptions[entry.Language.Name] = strings.Join(strings.Fields(entry.Text), " ")
+	}
+
+	res := make([]model.Translation, 0, len(names))
+	for _, name := range names {
+		res = append(res, model.Translation{
+			Language:    name.Language.Name,
+			Name:        name.Name,
+			Description: descriptions[name.Language.Name],
+		})
+	}
+
+	return res
+}
+
+func (s *service) GetCatalogue(ctx context.Context, filter model.ItemFilter) (*model.ItemCatalogueResponse, CacheStatus, error) {
+	// the whole catalogue is cached once and filtered per request
+	catalogue, status, err := cachedValue(ctx, s, catalogueKey, s.dbRepository.FetchItemCatalogue)
+	if err != nil {
+		return nil, status, err
+	}
+
+	res := &model.ItemCatalogueResponse{Items: []model.ItemSummary{}}
+	for _, item := range catalogue.Items {
+		if filter.Category != "" && item.Category != filter.Category {
+			continue
+		}
+		if filter.Attribute != "" && !slices.Contains(item.Attributes, filter.Attribute) {
+			continue
+		}
+		res.Items = append(res.Items, item)
+	}
+
+	return res, status, nil
+}
+
+func (s *service) GetCatalogueItem(ctx context.Context, name string) (*model.Item, CacheStatus, error) {
+	return cachedValue(ctx, s, catalogueItemKey(name), func(ctx context.Context) (*model.Item, error) {
+		return s.dbRepository.FetchItem(ctx, name)
+	})
+}
+
+func catalogueItemKey(name string) string {
+	return catalogueItemKeyPrefix + name
+}
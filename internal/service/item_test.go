@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/inasknh/simple-poke-app/internal/fakepokeapi"
+	mocks "github.com/inasknh/simple-poke-app/internal/mocks/repository"
+	"github.com/inasknh/simple-poke-app/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func Test_service_SyncItems_FakePokeAPI(t *testing.T) {
+	server := fakepokeapi.New()
+	defer server.Close()
+
+	mockDB := &mocks.Repository{}
+	var created []model.Item
+	mockDB.
+		On("CreateItem", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			created = append(created, args.Get(1).(model.Item))
+		}).
+		Return(nil)
+	mockDB.On("LinkBerryItem", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockDB.On("CreateSyncRun", mock.Anything).Return(&model.SyncRun{ID: 2}, nil)
+	mockDB.On("FetchItemCatalogue", mock.Anything).Return(&model.ItemCatalogueResponse{}, nil)
+	s := newFakeAPIService(server, mockDB)
+	mockRedis := s.redisRepository.(*mocks.RedisRepository)
+	mockRedis.On("DeleteKey", mock.Anything, mock.Anything).Return(true, nil)
+	mockRedis.On("DeleteData", mock.Anything).Return(nil)
+
+	err := s.SyncItems(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, created, 14)
+	// item list and details, then berry list and details
+	assert.Equal(t, 28, server.Requests())
+
+	potion := created[1]
+	assert.Equal(t, model.ItemRef{ID: 27, Name: "healing"}, potion.Category)
+	assert.Nil(t, potion.FlingEffect)
+	assert.Equal(t, "Restores 20 HP.", potion.Effect)
+	assert.Len(t, potion.Attributes, 5)
+	assert.Equal(t, &model.ItemRef{ID: 3, Name: "berry-effect"}, created[2].FlingEffect)
+	// the flavor text of the latest version group is kept
+	assert.Equal(t, []model.Translation{{Language: "en", Name: "Cheri Berry", Description: "Held: Consumed when the holder needs it."}}, created[2].Translations)
+
+	mockDB.AssertCalled(t, "LinkBerryItem", mock.Anything, "cheri", 126)
+	// the berry listing is translated through the items, it gets a new version
+	mockDB.AssertCalled(t, "CreateSyncRun", mock.Anything)
+	mockRedis.AssertCalled(t, "DeleteData", mock.Anything)
+	mockRedis.AssertCalled(t, "DeleteKey", mock.Anything, "catalogue:potion")
+	// the berry is served with the sprite of its item
+	mockRedis.AssertCalled(t, "DeleteKey", mock.Anything, "berry:cheri")
+	mockRedis.AssertCalled(t, "SetValue", mock.Anything, catalogueKey, &model.ItemCatalogueResponse{})
+}
+
+func Test_service_SyncItems_FakePokeAPI_Error(t *testing.T) {
+	server := fakepokeapi.New()
+	defer server.Close()
+
+	mockDB := &mocks.Repository{}
+	mockDB.On("CreateItem", mock.Anything, mock.Anything).Return(nil)
+	mockDB.On("LinkBerryItem", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("an error"))
+	s := newFakeAPIService(server, mockDB)
+	s.redisRepository.(*mocks.RedisRepository).On("DeleteKey", mock.Anything, mock.Anything).Return(true, nil)
+
+	err := s.SyncItems(context.Background())
+	assert.Error(t, err)
+	mockDB.AssertNumberOfCalls(t, "LinkBerryItem", 1)
+	mockDB.AssertNotCalled(t, "FetchItemCatalogue", mock.Anything)
+}
+
+func Test_service_GetCatalogue(t *testing.T) {
+	potion := model.ItemSummary{ID: 17, Name: "potion", Category: "healing", Attributes: []string{"countable", "holdable"}}
+	cheri := model.ItemSummary{ID: 126, Name: "cheri-berry", Category: "medicine", Attributes: []string{"holdable"}}
+	catalogue := &model.ItemCatalogueResponse{Items: []model.ItemSummary{potion, cheri}}
+
+	tests := []struct {
+		name   string
+		filter model.ItemFilter
+		want   []model.ItemSummary
+	}{
+		{
+			name:   "given no filter should return every item",
+			filter: model.ItemFilter{},
+			want:   []model.ItemSummary{potion, cheri},
+		},
+		{
+			name:   "given a category should return its items",
+			filter: model.ItemFilter{Category: "medicine"},
+			want:   []model.ItemSummary{cheri},
+		},
+		{
+			name:   "given an attribute should return the items having it",
+			filter: model.ItemFilter{Attribute: "countable"},
+			want:   []model.ItemSummary{potion},
+		},
+		{
+			name:   "given a category and an attribute should return the items matching both",
+			filter: model.ItemFilter{Category: "medicine", Attribute: "countable"},
+			want:   []model.ItemSummary{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := &mocks.Repository{}
+			mockRedis := &mocks.RedisRepository{}
+			mockRedis.
+				On("GetValue", mock.Anything, catalogueKey, mock.Anything).
+				Run(func(args mock.Arguments) {
+					*args.Get(2).(*model.ItemCatalogueResponse) = *catalogue
+				}).
+				Return(true, nil)
+			s := &service{
+				dbRepository:    mockDB,
+				redisRepository: mockRedis,
+			}
+
+			got, status, err := s.GetCatalogue(context.Background(), tt.filter)
+			assert.NoError(t, err)
+			assert.Equal(t, CacheHit, status)
+			assert.Equal(t, tt.want, got.Items)
+		})
+	}
+}
+
+func Test_service_GetCatalogueItem(t *testing.T) {
+	item := &model.Item{ID: 126, Name: "cheri-berry", Berry: "cheri"}
+
+	tests := []struct {
+		name       string
+		want       *model.Item
+		wantStatus CacheStatus
+		wantErr    error
+		mockFunc   func(mockDB *mocks.Repository, mockRedis *mocks.RedisRepository)
+	}{
+		{
+			name:       "given nil result from redis should read the database and cache the item",
+			want:       item,
+			wantStatus: CacheMiss,
+			mockFunc: func(mockDB *mocks.Repository, mockRedis *mocks.RedisRepository) {
+				mockRedis.On("GetValue", mock.Anything, "catalogue:cheri-berry", mock.Anything).Return(false, nil)
+				mockDB.On("FetchItem", mock.Anything, "cheri-berry").Return(item, nil)
+				mockRedis.On("SetValue", mock.Anything, "catalogue:cheri-berry", item).Return(nil)
+			},
+		},
+		{
+			name:       "given unknown item should return ErrNotFound",
+			want:       nil,
+			wantStatus: CacheMiss,
+			wantErr:    model.ErrNotFound,
+			mockFunc: func(mockDB *mocks.Repository, mockRedis *mocks.RedisRepository) {
+				mockRedis.On("GetValue", mock.Anything, "catalogue:cheri-berry", mock.Anything).Return(false, nil)
+				mockDB.On("FetchItem", mock.Anything, "cheri-berry").Return(nil, model.ErrNotFound)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := &mocks.Repository{}
+			mockRedis := &mocks.RedisRepository{}
+			tt.mockFunc(mockDB, mockRedis)
+			s := &service{
+				dbRepository:    mockDB,
+				redisRepository: mockRedis,
+			}
+
+			got, status, err := s.GetCatalogueItem(context.Background(), "cheri-berry")
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantStatus, status)
+			assert.Equal(t, tt.want, got)
+			mockRedis.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/inasknh/simple-poke-app/internal/api"
+	"github.com/inasknh/simple-poke-app/internal/model"
+	"log"
+	"slices"
+	"sort"
+)
+
+const (
+	natureKeyPrefix   = "nature:"
+	flavorProfilesKey = "flavor-profiles"
+)
+
+func (s *service) SyncNatures(ctx context.Context) error {
+	return syncEach(ctx, s.client, func(ctx context.Context, nature *api.Nature) error {
+		res := constructNature(nature)
+		if err := s.dbRepository.CreateNature(ctx, res); err != nil {
+			return err
+		}
+
+		if err := s.redisRepository.SetValue(ctx, natureKey(res.Name), &res); err != nil {
+			log.Printf("failed to cache nature %q: %v", res.Name, err)
+		}
+		return nil
+	})
+}
+
+func constructNature(nature *api.Nature) model.Nature {
+	res := model.Nature{
+		ID:   nature.ID,
+		Name: nature.Name,
+	}
+
+	if nature.IncreasedStat != nil {
+		res.IncreasedStat = nature.IncreasedStat.Name
+	}
+	if nature.DecreasedStat != nil {
+		res.DecreasedStat = nature.DecreasedStat.Name
+	}
+	if nature.LikesFlavor != nil {
+		res.LikesFlavorID = nature.LikesFlavor.ID()
+		res.LikesFlavor = nature.LikesFlavor.Name
+	}
+	if nature.HatesFlavor != nil {
+		res.HatesFlavorID = nature.HatesFlavor.ID()
+		res.HatesFlavor = nature.HatesFlavor.Name
+	}
+
+	return res
+}
+
+func (s *service) RecommendBerries(ctx context.Context, query model.RecommendationQuery) (*model.RecommendationResponse, CacheStatus, error) {
+	likes, dislikes := query.Likes, query.Dislikes
+	natureStatus := CacheHit
+	var nature *model.Nature
+	if query.Nature != "" {
+		var err error
+		nature, natureStatus, err = cachedValue(ctx, s, natureKey(query.Nature), func(ctx context.Context) (*model.Nature, error) {
+			return s.dbRepository.FetchNature(ctx, query.Nature)
+		})
+		if err != nil {
+			return nil, natureStatus, err
+		}
+		likes, dislikes = nature.LikesFlavor, nature.HatesFlavor
+	}
+
+	profiles, status, err := cachedValue(ctx, s, flavorProfilesKey, s.dbRepository.FetchFlavorProfiles)
+	// the response is a hit only when both lookups were
+	if status == CacheHit {
+		status = natureStatus
+	}
+	if err != nil {
+		return nil, status, err
+	}
+	for _, flavor := range []string{likes, dislikes} {
+		if flavor != "" && !slices.Contains(profiles.Flavors, flavor) {
+			return nil, status, fmt.Errorf("flavor %q: %w", flavor, model.ErrNotFound)
+		}
+	}
+
+	res := recommend(profiles.Berries, likes, dislikes)
+	res.Nature = nature
+	res.Likes = likes
+	res.Dislikes = dislikes
+	return res, status, nil
+}
+
+// recommend ranks the berries by their liked minus their disliked potency.
+// Without a liked flavor every berry free of the disliked one is kept, and
+// berries confusing a Pokémon that dislikes their flavor are excluded.
+func recommend(berries []model.FlavorProfile, likes, dislikes string) *model.RecommendationResponse {
+	res := &model.RecommendationResponse{
+		Berries:  []model.BerryRecommendation{},
+		Excluded: []string{},
+	}
+
+	for _, berry := range berries {
+		liked, disliked := berry.Potency[likes], berry.Potency[dislikes]
+		if berry.Confuses && disliked > 0 {
+			res.Excluded = append(res.Excluded, berry.Berry)
+			continue
+		}
+
+		score := liked - disliked
+		if (likes != "" && score <= 0) || (likes == "" && disliked > 0) {
+			continue
+		}
+		res.Berries = append(res.Berries, model.BerryRecommendation{
+			Berry:           berry.Berry,
+			Score:           score,
+			LikedPotency:    liked,
+			DislikedPotency: disliked,
+		})
+	}
+
+	// on equal scores less of the disliked flavor wins, then the PokeAPI order
+	sort.SliceStable(res.Berries, func(i, j int) bool {
+		if res.Berries[i].Score != res.Berries[j].Score {
+			return res.Berries[i].Score > res.Berries[j].Score
+		}
+		return res.Berries[i].DislikedPotency < res.Berries[j].DislikedPotency
+	})
+
+	return res
+}
+
+func natureKey(name string) string {
+	return natureKeyPrefix + name
+}
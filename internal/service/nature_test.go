@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/inasknh/simple-poke-app/internal/fakepokeapi"
+	mocks "github.com/inasknh/simple-poke-app/internal/mocks/repository"
+	"github.com/inasknh/simple-poke-app/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func Test_service_SyncNatures_FakePokeAPI(t *testing.T) {
+	server := fakepokeapi.New()
+	defer server.Close()
+
+	mockDB := &mocks.Repository{}
+	var created []model.Nature
+	mockDB.
+		On("CreateNature", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			created = append(created, args.Get(1).(model.Nature))
+		}).
+		Return(nil)
+	s := newFakeAPIService(server, mockDB)
+
+	err := s.SyncNatures(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, created, 25)
+
+	assert.Equal(t, model.Nature{ID: 1, Name: "hardy"}, created[0])
+	assert.Equal(t, model.Nature{
+		ID:            11,
+		Name:          "adamant",
+		IncreasedStat: "attack",
+		DecreasedStat: "special-attack",
+		LikesFlavorID: 1,
+		LikesFlavor:   "spicy",
+		HatesFlavorID: 2,
+		HatesFlavor:   "dry",
+	}, created[10])
+	s.redisRepository.(*mocks.RedisRepository).AssertCalled(t, "SetValue", mock.Anything, natureKey("adamant"), &created[10])
+}
+
+func Test_service_SyncNatures_FakePokeAPI_Error(t *testing.T) {
+	server := fakepokeapi.New()
+	defer server.Close()
+
+	mockDB := &mocks.Repository{}
+	mockDB.On("CreateNature", mock.Anything, mock.Anything).Return(errors.New("an error"))
+	s := newFakeAPIService(server, mockDB)
+
+	err := s.SyncNatures(context.Background())
+	assert.Error(t, err)
+	mockDB.AssertNumberOfCalls(t, "CreateNature", 1)
+	s.redisRepository.(*mocks.RedisRepository).AssertNotCalled(t, "SetValue", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_service_RecommendBerries(t *testing.T) {
+	adamant := &model.Nature{ID: 11, Name: "adamant", LikesFlavor: "spicy", HatesFlavor: "dry"}
+	profiles := &model.FlavorProfiles{
+		Flavors: []string{"spicy", "dry", "sweet"},
+		Berries: []model.FlavorProfile{
+			{Berry: "cheri", Potency: map[string]int{"spicy": 10}},
+			{Berry: "figy", Potency: map[string]int{"spicy": 15}, Confuses: true},
+			{Berry: "wiki", Potency: map[string]int{"dry": 15}, Confuses: true},
+			{Berry: "leppa", Potency: map[string]int{"spicy": 10, "dry": 10}},
+			{Berry: "spelon", Potency: map[string]int{"spicy": 30, "dry": 10}},
+			{Berry: "tamato", Potency: map[string]int{"spicy": 20}},
+			{Berry: "pecha", Potency: map[string]int{"sweet": 10}},
+		},
+	}
+	cachedProfiles := func(mockRedis *mocks.RedisRepository) {
+		mockRedis.On("GetValue", mock.Anything, flavorProfilesKey, mock.Anything).
+			Run(func(args mock.Arguments) {
+				*args.Get(2).(*model.FlavorProfiles) = *profiles
+			}).
+			Return(true, nil)
+	}
+
+	tests := []struct {
+		name       string
+		query      model.RecommendationQuery
+		want       *model.RecommendationResponse
+		wantStatus CacheStatus
+		wantErr    error
+		mockFunc   func(mockDB *mocks.Repository, mockRedis *mocks.RedisRepository)
+	}{
+		{
+			name:       "given a cached nature should rank the berries it likes, excluding the confusing ones",
+			query:      model.RecommendationQuery{Nature: "adamant"},
+			wantStatus: CacheHit,
+			want: &model.RecommendationResponse{
+				Nature:   adamant,
+				Likes:    "spicy",
+				Dislikes: "dry",
+				Berries: []model.BerryRecommendation{
+					{Berry: "tamato", Score: 20, LikedPotency: 20},
+					{Berry: "spelon", Score: 20, LikedPotency: 30, DislikedPotency: 10},
+					{Berry: "figy", Score: 15, LikedPotency: 15},
+					{Berry: "cheri", Score: 10, LikedPotency: 10},
+				},
+				Excluded: []string{"wiki"},
+			},
+			mockFunc: func(mockDB *mocks.Repository, mockRedis *mocks.RedisRepository) {
+				mockRedis.On("GetValue", mock.Anything, natureKey("adamant"), mock.Anything).
+					Run(func(args mock.Arguments) {
+						*args.Get(2).(*model.Nature) = *adamant
+					}).
+					Return(true, nil)
+				cachedProfiles(mockRedis)
+			},
+		},
+		{
+			name:       "given an uncached neutral nature should read it from the database and keep every berry",
+			query:      model.RecommendationQuery{Nature: "hardy"},
+			wantStatus: CacheMiss,
+			want: &model.RecommendationResponse{
+				Nature: &model.Nature{ID: 1, Name: "hardy"},
+				Berries: []model.BerryRecommendation{
+					{Berry: "cheri"}, {Berry: "figy"}, {Berry: "wiki"}, {Berry: "leppa"},
+					{Berry: "spelon"}, {Berry: "tamato"}, {Berry: "pecha"},
+				},
+				Excluded: []string{},
+			},
+			mockFunc: func(mockDB *mocks.Repository, mockRedis *mocks.RedisRepository) {
+				mockRedis.On("GetValue", mock.Anything, natureKey("hardy"), mock.Anything).Return(false, nil)
+				mockDB.On("FetchNature", mock.Anything, "hardy").Return(&model.Nature{ID: 1, Name: "hardy"}, nil)
+				mockRedis.On("SetValue", mock.Anything, natureKey("hardy"), mock.Anything).Return(nil)
+				cachedProfiles(mockRedis)
+			},
+		},
+		{
+			name:       "given only a disliked flavor should keep the berries without it",
+			query:      model.RecommendationQuery{Dislikes: "dry"},
+			wantStatus: CacheHit,
+			want: &model.RecommendationResponse{
+				Dislikes: "dry",
+				Berries: []model.BerryRecommendation{
+					{Berry: "cheri"},
+					{Berry: "figy"},
+					{Berry: "tamato"},
+					{Berry: "pecha"},
+				},
+				Excluded: []string{"wiki"},
+			},
+			mockFunc: func(mockDB *mocks.Repository, mockRedis *mocks.RedisRepository) {
+				cachedProfiles(mockRedis)
+			},
+		},
+		{
+			name:       "given an unknown nature should return ErrNotFound",
+			query:      model.RecommendationQuery{Nature: "grumpy"},
+			wantStatus: CacheMiss,
+			wantErr:    model.ErrNotFound,
+			mockFunc: func(mockDB *mocks.Repository, mockRedis *mocks.RedisRepository) {
+				mockRedis.On("GetValue", mock.Anything, natureKey("grumpy"), mock.Anything).Return(false, nil)
+				mockDB.On("FetchNature", mock.Anything, "grumpy").Return(nil, model.ErrNotFound)
+			},
+		},
+		{
+			name:       "given an unknown flavor should return ErrNotFound",
+			query:      model.RecommendationQuery{Likes: "salty"},
+			wantStatus: CacheHit,
+			wantErr:    model.ErrNotFound,
+			mockFunc: func(mockDB *mocks.Repository, mockRedis *mocks.RedisRepository) {
+				cachedProfiles(mockRedis)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := &mocks.Repository{}
+			mockRedis := &mocks.RedisRepository{}
+			tt.mockFunc(mockDB, mockRedis)
+			s := &service{
+				dbRepository:    mockDB,
+				redisRepository: mockRedis,
+			}
+
+			got, status, err := s.RecommendBerries(context.Background(), tt.query)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantStatus, status)
+			assert.Equal(t, tt.want, got)
+			mockDB.AssertExpectations(t)
+			mockRedis.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/inasknh/simple-poke-app/internal/api"
+	"github.com/inasknh/simple-poke-app/internal/model"
+	"log"
+	"sort"
+)
+
+const (
+	berryGrowthKey = "berry-growth"
+	// MaxPlanPlots and MaxPlanHours bound the size of a farming plan.
+	MaxPlanPlots = 100
+	MaxPlanHours = 30 * 24
+	// growthStages is how many stages a tree grows through before harvest.
+	growthStages = 4
+	// soilMoisture is the moisture of freshly watered soil, it drops by the
+	// soil dryness of the tree every hour and the soil stays moist while it
+	// is above zero.
+	soilMoisture = 100
+)
+
+func (s *service) SyncBerryGrowth(ctx context.Context) error {
+	err := syncEach(ctx, s.client, func(ctx context.Context, berry *api.Berry) error {
+		return s.dbRepository.SetBerryGrowth(ctx, model.BerryGrowth{
+			Berry:       berry.Name,
+			GrowthTime:  berry.GrowthTime,
+			MaxHarvest:  berry.MaxHarvest,
+			Size:        berry.Size,
+			SoilDryness: berry.SoilDryness,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	growths, err := s.dbRepository.FetchBerryGrowths(ctx)
+	if err != nil {
+		return err
+	}
+	if err = s.redisRepository.SetValue(ctx, berryGrowthKey, growths); err != nil {
+		log.Printf("failed to cache berry growth: %v", err)
+	}
+
+	return nil
+}
+
+func (s *service) PlanFarm(ctx context.Context, query model.PlanQuery) (*model.PlanResponse, CacheStatus, error) {
+	growths, status, err := cachedValue(ctx, s, berryGrowthKey, s.dbRepository.FetchBerryGrowths)
+	if err != nil {
+		return nil, status, err
+	}
+
+	byName := make(map[string]model.BerryGrowth, len(growths.Berries))
+	for _, growth := range growths.Berries {
+		byName[growth.Berry] = growth
+	}
+	berries := make([]model.BerryGrowth, 0, len(query.Berries))
+	for _, name := range query.Berries {
+		growth, ok := byName[name]
+		if !ok {
+			return nil, status, fmt.Errorf("berry %q: %w", name, model.ErrNotFound)
+		}
+		berries = append(berries, growth)
+	}
+
+	return plan(berries, query.Plots, query.Hours), status, nil
+}
+
+// plan plants the berries over the plots in turn and replants every plot as
+// soon as it is harvested, as long as the next harvest fits in hours. The
+// soil is watered on planting and before it dries out, so every harvest
+// yields the most berries.
+func plan(berries []model.BerryGrowth, plots, hours int) *model.PlanResponse {
+	res := &model.PlanResponse{
+		Hours:    hours,
+		Plots:    make([]model.PlotPlan, 0, plots),
+		Harvests: []model.ScheduledHarvest{},
+		Yield:    map[string]int{},
+	}
+
+	for i := 0; i < plots; i++ {
+		plot := planPlot(i+1, berries[i%len(berries)], hours)
+		res.Plots = append(res.Plots, plot)
+		res.Yield[plot.Berry] += plot.Yield
+		res.TotalYield += plot.Yield
+		res.Waterings += plot.Waterings
+
+		for _, event := range plot.Timeline {
+			if event.Action != model.PlotActionHarvest {
+				continue
+			}
+			res.Harvests = append(res.Harvests, model.ScheduledHarvest{
+				Hour:  event.Hour,
+				Plot:  plot.Plot,
+				Berry: plot.Berry,
+				Yield: event.Yield,
+			})
+		}
+	}
+
+	// plots are walked in order, a stable sort keeps them ordered within an hour
+	sort.SliceStable(res.Harvests, func(i, j int) bool {
+		return res.Harvests[i].Hour < res.Harvests[j].Hour
+	})
+
+	return res
+}
+
+func planPlot(n int, growth model.BerryGrowth, hours int) model.PlotPlan {
+	plot := model.PlotPlan{
+		Plot:             n,
+		BerryGrowth:      growth,
+		WateringInterval: wateringInterval(growth.SoilDryness),
+		Timeline:         []model.PlotEvent{},
+	}
+
+	cycle := growthStages * growth.GrowthTime
+	if cycle <= 0 {
+		return plot
+	}
+	for start := 0; start+cycle <= hours; start += cycle {
+		plot.Timeline = append(plot.Timeline, model.PlotEvent{Hour: start, Action: model.PlotActionPlant})
+		for hour := start; hour < start+cycle; hour += plot.WateringInterval {
+			plot.Timeline = append(plot.Timeline, model.PlotEvent{Hour: hour, Action: model.PlotActionWater})
+			plot.Waterings++
+			if plot.WateringInterval == 0 {
+				break
+			}
+		}
+
+		plot.Timeline = append(plot.Timeline, model.PlotEvent{Hour: start + cycle, Action: model.PlotActionHarvest, Yield: growth.MaxHarvest})
+		plot.Harvests++
+		plot.Yield += growth.MaxHarvest
+	}
+
+	return plot
+}
+
+// wateringInterval is the most hours the soil stays moist after watering, or
+// zero for trees that never dry it out.
+func wateringInterval(soilDryness int) int {
+	if soilDryness <= 0 {
+		return 0
+	}
+	return max((soilMoisture-1)/soilDryness, 1)
+}
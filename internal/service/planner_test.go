@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/inasknh/simple-poke-app/internal/fakepokeapi"
+	mocks "github.com/inasknh/simple-poke-app/internal/mocks/repository"
+	"github.com/inasknh/simple-poke-app/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+var (
+	cheriGrowth = model.BerryGrowth{Berry: "cheri", GrowthTime: 3, MaxHarvest: 5, Size: 20, SoilDryness: 15}
+	lumGrowth   = model.BerryGrowth{Berry: "lum", GrowthTime: 12, MaxHarvest: 5, Size: 34, SoilDryness: 8}
+)
+
+func Test_service_SyncBerryGrowth_FakePokeAPI(t *testing.T) {
+	server := fakepokeapi.New()
+	defer server.Close()
+
+	mockDB := &mocks.Repository{}
+	var synced []model.BerryGrowth
+	mockDB.
+		On("SetBerryGrowth", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			synced = append(synced, args.Get(1).(model.BerryGrowth))
+		}).
+		Return(nil)
+	growths := &model.BerryGrowths{Berries: []model.BerryGrowth{cheriGrowth}}
+	mockDB.On("FetchBerryGrowths", mock.Anything).Return(growths, nil)
+	s := newFakeAPIService(server, mockDB)
+
+	err := s.SyncBerryGrowth(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, synced, 12)
+	// berry list, then every berry by name
+	assert.Equal(t, 13, server.Requests())
+	assert.Equal(t, cheriGrowth, synced[0])
+	assert.Equal(t, lumGrowth, synced[8])
+	s.redisRepository.(*mocks.RedisRepository).AssertCalled(t, "SetValue", mock.Anything, berryGrowthKey, growths)
+}
+
+func Test_service_SyncBerryGrowth_FakePokeAPI_Error(t *testing.T) {
+	server := fakepokeapi.New()
+	defer server.Close()
+
+	mockDB := &mocks.Repository{}
+	mockDB.On("SetBerryGrowth", mock.Anything, mock.Anything).Return(errors.New("an error"))
+	s := newFakeAPIService(server, mockDB)
+
+	err := s.SyncBerryGrowth(context.Background())
+	assert.Error(t, err)
+	mockDB.AssertNumberOfCalls(t, "SetBerryGrowth", 1)
+	mockDB.AssertNotCalled(t, "FetchBerryGrowths", mock.Anything)
+}
+
+func Test_service_PlanFarm(t *testing.T) {
+	growths := &model.BerryGrowths{Berries: []model.BerryGrowth{cheriGrowth, lumGrowth}}
+	cheriPlan := &model.PlanResponse{
+		Hours: 24,
+		Plots: []model.PlotPlan{{
+			Plot:             1,
+			BerryGrowth:      cheriGrowth,
+			Harvests:         2,
+			Yield:            10,
+			Waterings:        4,
+			WateringInterval: 6,
+			Timeline: []model.PlotEvent{
+				{Hour: 0, Action: model.PlotActionPlant},
+				{Hour: 0, Action: model.PlotActionWater},
+				{Hour: 6, Action: model.PlotActionWater},
+				{Hour: 12, Action: model.PlotActionHarvest, Yield: 5},
+				{Hour: 12, Action: model.PlotActionPlant},
+				{Hour: 12, Action: model.PlotActionWater},
+				{Hour: 18, Action: model.PlotActionWater},
+				{Hour: 24, Action: model.PlotActionHarvest, Yield: 5},
+			},
+		}},
+		Harvests: []model.ScheduledHarvest{
+			{Hour: 12, Plot: 1, Berry: "cheri", Yield: 5},
+			{Hour: 24, Plot: 1, Berry: "cheri", Yield: 5},
+		},
+		Yield:      map[string]int{"cheri": 10},
+		TotalYield: 10,
+		Waterings:  4,
+	}
+
+	tests := []struct {
+		name       string
+		query      model.PlanQuery
+		want       *model.PlanResponse
+		wantStatus CacheStatus
+		wantErr    error
+		mockFunc   func(mockDB *mocks.Repository, mockRedis *mocks.RedisRepository)
+	}{
+		{
+			name:       "given cached growth should plan every harvest and watering",
+			query:      model.PlanQuery{Berries: []string{"cheri"}, Plots: 1, Hours: 24},
+			want:       cheriPlan,
+			wantStatus: CacheHit,
+			mockFunc: func(mockDB *mocks.Repository, mockRedis *mocks.RedisRepository) {
+				mockRedis.On("GetValue", mock.Anything, berryGrowthKey, mock.Anything).
+					Run(func(args mock.Arguments) {
+						*args.Get(2).(*model.BerryGrowths) = *growths
+					}).
+					Return(true, nil)
+			},
+		},
+		{
+			name:       "given uncached growth should read it from the database",
+			query:      model.PlanQuery{Berries: []string{"cheri"}, Plots: 1, Hours: 24},
+			want:       cheriPlan,
+			wantStatus: CacheMiss,
+			mockFunc: func(mockDB *mocks.Repository, mockRedis *mocks.RedisRepository) {
+				mockRedis.On("GetValue", mock.Anything, berryGrowthKey, mock.Anything).Return(false, nil)
+				mockDB.On("FetchBerryGrowths", mock.Anything).Return(growths, nil)
+				mockRedis.On("SetValue", mock.Anything, berryGrowthKey, growths).Return(nil)
+			},
+		},
+		{
+			name:       "given a berry without growth should return ErrNotFound",
+			query:      model.PlanQuery{Berries: []string{"cheri", "oran"}, Plots: 2, Hours: 24},
+			wantStatus: CacheHit,
+			wantErr:    model.ErrNotFound,
+			mockFunc: func(mockDB *mocks.Repository, mockRedis *mocks.RedisRepository) {
+				mockRedis.On("GetValue", mock.Anything, berryGrowthKey, mock.Anything).
+					Run(func(args mock.Arguments) {
+						*args.Get(2).(*model.BerryGrowths) = *growths
+					}).
+					Return(true, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := &mocks.Repository{}
+			mockRedis := &mocks.RedisRepository{}
+			tt.mockFunc(mockDB, mockRedis)
+			s := &service{
+				dbRepository:    mockDB,
+				redisRepository: mockRedis,
+			}
+
+			got, status, err := s.PlanFarm(context.Background(), tt.query)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantStatus, status)
+			assert.Equal(t, tt.want, got)
+			mockDB.AssertExpectations(t)
+			mockRedis.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_plan(t *testing.T) {
+	got := plan([]model.BerryGrowth{cheriGrowth, lumGrowth}, 3, 48)
+
+	// berries are planted over the plots in turn
+	assert.Len(t, got.Plots, 3)
+	assert.Equal(t, []string{"cheri", "lum", "cheri"}, []string{got.Plots[0].Berry, got.Plots[1].Berry, got.Plots[2].Berry})
+	assert.Equal(t, model.PlotPlan{
+		Plot:             2,
+		BerryGrowth:      lumGrowth,
+		Harvests:         1,
+		Yield:            5,
+		Waterings:        4,
+		WateringInterval: 12,
+		Timeline: []model.PlotEvent{
+			{Hour: 0, Action: model.PlotActionPlant},
+			{Hour: 0, Action: model.PlotActionWater},
+			{Hour: 12, Action: model.PlotActionWater},
+			{Hour: 24, Action: model.PlotActionWater},
+			{Hour: 36, Action: model.PlotActionWater},
+			{Hour: 48, Action: model.PlotActionHarvest, Yield: 5},
+		},
+	}, got.Plots[1])
+
+	// harvests are scheduled by hour, then plot
+	assert.Len(t, got.Harvests, 9)
+	assert.Equal(t, []model.ScheduledHarvest{
+		{Hour: 48, Plot: 1, Berry: "cheri", Yield: 5},
+		{Hour: 48, Plot: 2, Berry: "lum", Yield: 5},
+		{Hour: 48, Plot: 3, Berry: "cheri", Yield: 5},
+	}, got.Harvests[6:])
+	assert.Equal(t, map[string]int{"cheri": 40, "lum": 5}, got.Yield)
+	assert.Equal(t, 45, got.TotalYield)
+	assert.Equal(t, 20, got.Waterings)
+
+	// a budget shorter than the growth of a berry harvests nothing
+	short := plan([]model.BerryGrowth{lumGrowth}, 1, 24)
+	assert.Equal(t, 0, short.TotalYield)
+	assert.Empty(t, short.Plots[0].Timeline)
+	assert.Empty(t, short.Harvests)
+}
+
+func Test_wateringInterval(t *testing.T) {
+	tests := []struct {
+		soilDryness int
+		want        int
+	}{
+		{soilDryness: 0, want: 0},
+		{soilDryness: 7, want: 14},
+		{soilDryness: 10, want: 9},
+		{soilDryness: 15, want: 6},
+		{soilDryness: 200, want: 1},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, wateringInterval(tt.soilDryness), "soil dryness %d", tt.soilDryness)
+	}
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"github.com/inasknh/simple-poke-app/internal/api"
+	"github.com/inasknh/simple-poke-app/internal/model"
+	"log"
+)
+
+const (
+	pokemonListKey   = "pokemon"
+	pokemonKeyPrefix = "pokemon:"
+)
+
+func (s *service) SyncPokemon(ctx context.Context) error {
+	err := syncEach(ctx, s.client, func(ctx context.Context, pokemon *api.Pokemon) error {
+		species, err := api.Get[api.PokemonSpecies](ctx, s.client, pokemon.Species.Name)
+		if err != nil {
+			return err
+		}
+
+		res := constructPokemon(pokemon, species)
+		res.Sprite = s.mirrorAsset(ctx, res.Sprite)
+		if err = s.dbRepository.CreatePokemon(ctx, res); err != nil {
+			return err
+		}
+
+		// the stored Pokémon is already at hand, cache it instead of invalidating
+		if err = s.redisRepository.SetValue(ctx, pokemonKey(res.Name), &res); err != nil {
+			log.Printf("failed to cache pokemon %q: %v", res.Name, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	list, err := s.dbRepository.FetchPokemonList(ctx)
+	if err != nil {
+		return err
+	}
+	if err = s.redisRepository.SetValue(ctx, pokemonListKey, list); err != nil {
+		log.Printf("failed to cache pokemon listing: %v", err)
+	}
+	s.rebuildSearchIndex(ctx)
+
+	return nil
+}
+
+func constructPokemon(pokemon *api.Pokemon, species *api.PokemonSpecies) model.Pokemon {
+	res := model.Pokemon{
+		ID:             pokemon.ID,
+		Name:           pokemon.Name,
+		Species:        species.Name,
+		Height:         pokemon.Height,
+		Weight:         pokemon.Weight,
+		BaseExperience: pokemon.BaseExperience,
+		CaptureRate:    species.CaptureRate,
+		IsLegendary:    species.IsLegendary,
+		IsMythical:     species.IsMythical,
+		Sprite:         pokemon.Sprites.FrontDefault,
+		Types:          make([]model.PokemonType, 0, len(pokemon.Types)),
+		Abilities:      make([]model.PokemonAbility, 0, len(pokemon.Abilities)),
+		Stats:          make([]model.PokemonStat, 0, len(pokemon.Stats)),
+		Names:          constructTranslations(species.Names, nil),
+	}
+
+	for _, t := range pokemon.Types {
+		res.Types = append(res.Types, model.PokemonType{
+			ID:   t.Type.ID(),
+			Name: t.Type.Name,
+			Slot: t.Slot,
+		})
+	}
+	for _, a := range pokemon.Abilities {
+		res.Abilities = append(res.Abilities, model.PokemonAbility{
+			ID:       a.Ability.ID(),
+			Name:     a.Ability.Name,
+			Slot:     a.Slot,
+			IsHidden: a.IsHidden,
+		})
+	}
+	for _, stat := range pokemon.Stats {
+		res.Stats = append(res.Stats, model.PokemonStat{
+			Name:     stat.Stat.Name,
+			BaseStat: stat.BaseStat,
+			Effort:   stat.Effort,
+		})
+	}
+
+	return res
+}
+
+func (s *service) GetPokemonList(ctx context.Context) (*model.PokemonListResponse, CacheStatus, error) {
+	return cachedValue(ctx, s, pokemonListKey, s.dbRepository.FetchPokemonList)
+}
+
+func (s *service) GetPokemon(ctx context.Context, name string) (*model.Pokemon, CacheStatus, error) {
+	return cachedValue(ctx, s, pokemonKey(name), func(ctx context.Context) (*model.Pokemon, error) {
+		return s.dbRepository.FetchPokemon(ctx, name)
+	})
+}
+
+func pokemonKey(name string) string {
+	return pokemonKeyPrefix + name
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/inasknh/simple-poke-app/internal/fakepokeapi"
+	mocks "github.com/inasknh/simple-poke-app/internal/mocks/repository"
+	"github.com/inasknh/simple-poke-app/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func Test_service_SyncPokemon_FakePokeAPI(t *testing.T) {
+	server := fakepokeapi.New()
+	defer server.Close()
+
+	mockDB := &mocks.Repository{}
+	var created []model.Pokemon
+	mockDB.
+		On("CreatePokemon", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			created = append(created, args.Get(1).(model.Pokemon))
+		}).
+		Return(nil)
+	mockDB.On("FetchPokemonList", mock.Anything).Return(&model.PokemonListResponse{}, nil)
+	s := newFakeAPIService(server, mockDB)
+
+	err := s.SyncPokemon(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, created, 3)
+	// list, then a pokemon and its species for each of them
+	assert.Equal(t, 7, server.Requests())
+
+	bulbasaur := created[0]
+	assert.Equal(t, "bulbasaur", bulbasaur.Species)
+	assert.Equal(t, 45, bulbasaur.CaptureRate)
+	assert.Equal(t, []model.PokemonType{{ID: 12, Name: "grass", Slot: 1}, {ID: 4, Name: "poison", Slot: 2}}, bulbasaur.Types)
+	assert.Equal(t, model.PokemonAbility{ID: 34, Name: "chlorophyll", Slot: 2, IsHidden: true}, bulbasaur.Abilities[1])
+	assert.Equal(t, model.PokemonStat{Name: "hp", BaseStat: 45}, bulbasaur.Stats[0])
+	assert.Equal(t, []model.Translation{
+		{Language: "en", Name: "Bulbasaur"},
+		{Language: "fr", Name: "Bulbizarre"},
+		{Language: "ja", Name: "フシギダネ"},
+	}, bulbasaur.Names)
+
+	mockRedis := s.redisRepository.(*mocks.RedisRepository)
+	mockRedis.AssertCalled(t, "SetValue", mock.Anything, "pokemon:bulbasaur", mock.Anything)
+	mockRedis.AssertCalled(t, "SetValue", mock.Anything, pokemonListKey, &model.PokemonListResponse{})
+}
+
+func Test_service_SyncPokemon_FakePokeAPI_Error(t *testing.T) {
+	server := fakepokeapi.New()
+	defer server.Close()
+
+	mockDB := &mocks.Repository{}
+	mockDB.On("CreatePokemon", mock.Anything, mock.Anything).Return(errors.New("an error"))
+	s := newFakeAPIService(server, mockDB)
+
+	err := s.SyncPokemon(context.Background())
+	assert.Error(t, err)
+	mockDB.AssertNumberOfCalls(t, "CreatePokemon", 1)
+	mockDB.AssertNotCalled(t, "FetchPokemonList", mock.Anything)
+}
+
+func Test_service_GetPokemon(t *testing.T) {
+	pokemon := &model.Pokemon{ID: 1, Name: "bulbasaur"}
+
+	tests := []struct {
+		name       string
+		want       *model.Pokemon
+		wantStatus CacheStatus
+		wantErr    error
+		mockFunc   func(mockDB *mocks.Repository, mockRedis *mocks.RedisRepository)
+	}{
+		{
+			name:       "given result from redis should return pokemon",
+			want:       pokemon,
+			wantStatus: CacheHit,
+			mockFunc: func(mockDB *mocks.Repository, mockRedis *mocks.RedisRepository) {
+				mockRedis.
+					On("GetValue", mock.Anything, "pokemon:bulbasaur", mock.Anything).
+					Run(func(args mock.Arguments) {
+						*args.Get(2).(*model.Pokemon) = *pokemon
+					}).
+					Return(true, nil)
+			},
+		},
+		{
+			name:       "given nil result from redis should read the database and cache the pokemon",
+			want:       pokemon,
+			wantStatus: CacheMiss,
+			mockFunc: func(mockDB *mocks.Repository, mockRedis *mocks.RedisRepository) {
+				mockRedis.On("GetValue", mock.Anything, "pokemon:bulbasaur", mock.Anything).Return(false, nil)
+				mockDB.On("FetchPokemon", mock.Anything, "bulbasaur").Return(pokemon, nil)
+				mockRedis.On("SetValue", mock.Anything, "pokemon:bulbasaur", pokemon).Return(nil)
+			},
+		},
+		{
+			name:       "given unknown pokemon should return ErrNotFound",
+			want:       nil,
+			wantStatus: CacheMiss,
+			wantErr:    model.ErrNotFound,
+			mockFunc: func(mockDB *mocks.Repository, mockRedis *mocks.RedisRepository) {
+				mockRedis.On("GetValue", mock.Anything, "pokemon:bulbasaur", mock.Anything).Return(false, nil)
+				mockDB.On("FetchPokemon", mock.Anything, "bulbasaur").Return(nil, model.ErrNotFound)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := &mocks.Repository{}
+			mockRedis := &mocks.RedisRepository{}
+			tt.mockFunc(mockDB, mockRedis)
+			s := &service{
+				dbRepository:    mockDB,
+				redisRepository: mockRedis,
+			}
+
+			got, status, err := s.GetPokemon(context.Background(), "bulbasaur")
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantStatus, status)
+			assert.Equal(t, tt.want, got)
+			mockRedis.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"github.com/inasknh/simple-poke-app/internal/model"
+	"github.com/inasknh/simple-poke-app/internal/search"
+	"log"
+	"time"
+)
+
+const (
+	searchDocumentsKey = "search:documents"
+	// searchIndexTTL bounds how long a replica searches an index built
+	// before a sync made elsewhere.
+	searchIndexTTL = time.Minute
+	// DefaultSearchLimit and MaxSearchLimit bound the number of results.
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
+)
+
+// builtIndex is the in-process search index and when it was built.
+type builtIndex struct {
+	index   *search.Index
+	builtAt time.Time
+}
+
+func (s *service) Search(ctx context.Context, query string, limit int) (*model.SearchResponse, CacheStatus, error) {
+	index, status, err := s.searchIndex(ctx)
+	if err != nil {
+		return nil, status, err
+	}
+
+	limit = min(max(limit, 1), MaxSearchLimit)
+	return &model.SearchResponse{
+		Query:   query,
+		Results: index.Search(query, limit),
+	}, status, nil
+}
+
+// searchIndex returns the in-process index, building it from the cached
+// documents once it is older than searchIndexTTL.
+func (s *service) searchIndex(ctx context.Context) (*search.Index, CacheStatus, error) {
+	if built := s.index.Load(); built != nil && time.Since(built.builtAt) < searchIndexTTL {
+		return built.index, CacheHit, nil
+	}
+
+	type result struct {
+		index  *search.Index
+		status CacheStatus
+	}
+	// the build is shared by every waiting search, it must outlive the first one
+	res, err, _ := s.rebuilds.Do(searchDocumentsKey, func() (interface{}, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), rebuildLockTTL)
+		defer cancel()
+
+		docs, status, err := cachedValue(ctx, s, searchDocumentsKey, s.dbRepository.FetchSearchDocuments)
+		if err != nil {
+			return result{status: status}, err
+		}
+
+		index := search.NewIndex(docs.Documents)
+		s.index.Store(&builtIndex{index: index, builtAt: time.Now()})
+		return result{index: index, status: status}, nil
+	})
+
+	r := res.(result)
+	return r.index, r.status, err
+}
+
+// rebuildSearchIndex indexes the resources stored by a sync. Failures are
+// logged, searches keep serving the previous index until it expires.
+func (s *service) rebuildSearchIndex(ctx context.Context) {
+	docs, err := s.dbRepository.FetchSearchDocuments(ctx)
+	if err != nil {
+		log.Printf("failed to rebuild search index: %v", err)
+		return
+	}
+
+	if err = s.redisRepository.SetValue(ctx, searchDocumentsKey, docs); err != nil {
+		log.Printf("failed to cache search documents: %v", err)
+	}
+	s.index.Store(&builtIndex{index: search.NewIndex(docs.Documents), builtAt: time.Now()})
+}
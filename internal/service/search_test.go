@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"errors"
+	mocks "github.com/inasknh/simple-poke-app/internal/mocks/repository"
+	"github.com/inasknh/simple-poke-app/internal/model"
+	"github.com/inasknh/simple-poke-app/internal/search"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_service_Search(t *testing.T) {
+	documents := &model.SearchDocuments{Documents: []model.SearchDocument{
+		{Type: model.SearchTypeItem, Name: "potion", Names: map[string]string{"fr": "Potion"}},
+		{Type: model.SearchTypePokemon, Name: "bulbasaur"},
+	}}
+	bulbasaur := []model.SearchResult{{Type: model.SearchTypePokemon, Name: "bulbasaur", Score: 0.75}}
+
+	tests := []struct {
+		name       string
+		index      *builtIndex
+		want       *model.SearchResponse
+		wantStatus CacheStatus
+		wantErr    bool
+		mockFunc   func(mockDB *mocks.Repository, mockRedis *mocks.RedisRepository)
+	}{
+		{
+			name:       "given a fresh index should search it without reading the documents",
+			index:      &builtIndex{index: search.NewIndex(documents.Documents), builtAt: time.Now()},
+			want:       &model.SearchResponse{Query: "bulbsaur", Results: bulbasaur},
+			wantStatus: CacheHit,
+			mockFunc:   func(mockDB *mocks.Repository, mockRedis *mocks.RedisRepository) {},
+		},
+		{
+			name:       "given an expired index should rebuild it from the cached documents",
+			index:      &builtIndex{index: search.NewIndex(nil), builtAt: time.Now().Add(-2 * searchIndexTTL)},
+			want:       &model.SearchResponse{Query: "bulbsaur", Results: bulbasaur},
+			wantStatus: CacheHit,
+			mockFunc: func(mockDB *mocks.Repository, mockRedis *mocks.RedisRepository) {
+				mockRedis.On("GetValue", mock.Anything, searchDocumentsKey, mock.Anything).
+					Run(func(args mock.Arguments) {
+						*args.Get(2).(*model.SearchDocuments) = *documents
+					}).
+					Return(true, nil)
+			},
+		},
+		{
+			name:       "given no index nor cached documents should build it from the database",
+			want:       &model.SearchResponse{Query: "bulbsaur", Results: bulbasaur},
+			wantStatus: CacheMiss,
+			mockFunc: func(mockDB *mocks.Repository, mockRedis *mocks.RedisRepository) {
+				mockRedis.On("GetValue", mock.Anything, searchDocumentsKey, mock.Anything).Return(false, nil)
+				mockDB.On("FetchSearchDocuments", mock.Anything).Return(documents, nil)
+				mockRedis.On("SetValue", mock.Anything, searchDocumentsKey, documents).Return(nil)
+			},
+		},
+		{
+			name:       "given an error when reading the documents should return it",
+			wantStatus: CacheMiss,
+			wantErr:    true,
+			mockFunc: func(mockDB *mocks.Repository, mockRedis *mocks.RedisRepository) {
+				mockRedis.On("GetValue", mock.Anything, searchDocumentsKey, mock.Anything).Return(false, nil)
+				mockDB.On("FetchSearchDocuments", mock.Anything).Return(nil, errors.New("an error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := &mocks.Repository{}
+			mockRedis := &mocks.RedisRepository{}
+			tt.mockFunc(mockDB, mockRedis)
+			s := &service{
+				dbRepository:    mockDB,
+				redisRepository: mockRedis,
+			}
+			if tt.index != nil {
+				s.index.Store(tt.index)
+			}
+
+			got, status, err := s.Search(context.Background(), "bulbsaur", DefaultSearchLimit)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.wantStatus, status)
+			assert.Equal(t, tt.want, got)
+			mockDB.AssertExpectations(t)
+			mockRedis.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_service_Search_CancelledCaller(t *testing.T) {
+	documents := &model.SearchDocuments{Documents: []model.SearchDocument{{Type: model.SearchTypePokemon, Name: "bulbasaur"}}}
+	mockRedis := &mocks.RedisRepository{}
+	// the build is shared with the second search, it must not be cancelled
+	// with the search that started it and must still be bounded
+	mockRedis.
+		On("GetValue", mock.MatchedBy(func(ctx context.Context) bool {
+			_, ok := ctx.Deadline()
+			return ctx.Err() == nil && ok
+		}), searchDocumentsKey, mock.Anything).
+		After(50*time.Millisecond).
+		Run(func(args mock.Arguments) {
+			*args.Get(2).(*model.SearchDocuments) = *documents
+		}).
+		Return(true, nil)
+	s := &service{
+		dbRepository:    &mocks.Repository{},
+		redisRepository: mockRedis,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var wg sync.WaitGroup
+	for _, ctx := range []context.Context{ctx, context.Background()} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, status, err := s.Search(ctx, "bulbasaur", DefaultSearchLimit)
+			assert.NoError(t, err)
+			assert.Equal(t, CacheHit, status)
+			assert.Len(t, got.Results, 1)
+		}()
+		// the second search joins the build started by the first
+		time.Sleep(10 * time.Millisecond)
+	}
+	wg.Wait()
+
+	mockRedis.AssertNumberOfCalls(t, "GetValue", 1)
+}
+
+func Test_service_rebuildSearchIndex(t *testing.T) {
+	documents := &model.SearchDocuments{Documents: []model.SearchDocument{{Type: model.SearchTypePokemon, Name: "bulbasaur"}}}
+	mockDB := &mocks.Repository{}
+	mockRedis := &mocks.RedisRepository{}
+	mockDB.On("FetchSearchDocuments", mock.Anything).Return(documents, nil)
+	mockRedis.On("SetValue", mock.Anything, searchDocumentsKey, documents).Return(errors.New("an error"))
+	s := &service{dbRepository: mockDB, redisRepository: mockRedis}
+
+	s.rebuildSearchIndex(context.Background())
+
+	// the local index is rebuilt even when the cache is unavailable
+	got, status, err := s.Search(context.Background(), "bulba", DefaultSearchLimit)
+	assert.NoError(t, err)
+	assert.Equal(t, CacheHit, status)
+	assert.Equal(t, []model.SearchResult{{Type: model.SearchTypePokemon, Name: "bulbasaur", Score: 1}}, got.Results)
+}
@@ -2,69 +2,464 @@ package service
 
 import (
 	"context"
+	"errors"
 	"github.com/inasknh/simple-poke-app/internal/api"
 	"github.com/inasknh/simple-poke-app/internal/model"
 	"github.com/inasknh/simple-poke-app/internal/repository"
+	"golang.org/x/sync/singleflight"
+	"io"
+	"log"
+	"strconv"
+	"sync/atomic"
+	"time"
 )
 
+const (
+	// itemsLock, suffixed with the language, guards rebuilding the cached /items listing.
+	itemsLock           = "items"
+	itemsRefresh        = "items:refresh"
+	rebuildLockTTL      = 5 * time.Second
+	rebuildWait         = 2 * time.Second
+	rebuildPollInterval = 50 * time.Millisecond
+	// syncWarmupTimeout bounds warming the cache after a sync.
+	syncWarmupTimeout = 30 * time.Second
+	berryKeyPrefix    = "berry:"
+	firmnessesKey     = "firmnesses"
+	flavorsKey        = "flavors"
+)
+
+// CacheStatus reports how a cached read was served, exposed to clients as X-Cache.
+type CacheStatus string
+
+const (
+	// CacheHit is a fresh value served from the cache.
+	CacheHit CacheStatus = "HIT"
+	// CacheMiss is a value rebuilt from the database and cached.
+	CacheMiss CacheStatus = "MISS"
+	// CacheStale is a stale value served from the cache while it is refreshed.
+	CacheStale CacheStatus = "STALE"
+	// CacheBypass is a value served from the database because the cache is unavailable.
+	CacheBypass CacheStatus = "BYPASS"
+)
+
+// ItemStream receives a listing row by row.
+type ItemStream interface {
+	// Begin is called once before any row with the sync run the rows are
+	// read after, nil before the first sync.
+	Begin(run *model.SyncRun) error
+	Write(berry model.Berry) error
+}
+
+// Assets mirrors the sprites of synced resources into a blob store.
+type Assets struct {
+	Store      repository.BlobStore
+	Downloader api.Downloader
+	// BaseURL prefixes the content hash in the asset urls exposed by the models.
+	BaseURL string
+}
+
 type service struct {
 	dbRepository    repository.Repository
 	redisRepository repository.RedisRepository
 	client          api.Client
+	assets          *Assets
+	rebuilds        singleflight.Group
+	index           atomic.Pointer[builtIndex]
 }
 
 type Service interface {
 	SyncData(ctx context.Context) error
-	GetItems(ctx context.Context) (*model.BerriesResponse, error)
+	// GetItems returns the berry listing localized in lang and how the cache served it.
+	GetItems(ctx context.Context, lang string) (*model.BerriesResponse, CacheStatus, error)
+	// StreamItems writes the listing localized in lang to stream straight
+	// from the database, bypassing the cache.
+	StreamItems(ctx context.Context, lang string, stream ItemStream) error
+	// GetItem returns a single berry and how the cache served it.
+	GetItem(ctx context.Context, name string) (*model.Berry, CacheStatus, error)
+	// GetFirmnesses returns every firmness with its berries.
+	GetFirmnesses(ctx context.Context) (*model.FirmnessesResponse, CacheStatus, error)
+	// GetFlavors returns every flavor with its berries, strongest first.
+	GetFlavors(ctx context.Context) (*model.FlavorsResponse, CacheStatus, error)
+	// SyncPokemon stores every Pokémon with its species data and refreshes their cache entries.
+	SyncPokemon(ctx context.Context) error
+	GetPokemonList(ctx context.Context) (*model.PokemonListResponse, CacheStatus, error)
+	// GetPokemon returns a single Pokémon, or model.ErrNotFound.
+	GetPokemon(ctx context.Context, name string) (*model.Pokemon, CacheStatus, error)
+	// SyncItems stores the item catalogue and links every berry to its item.
+	SyncItems(ctx context.Context) error
+	// GetCatalogue returns the items matching filter.
+	GetCatalogue(ctx context.Context, filter model.ItemFilter) (*model.ItemCatalogueResponse, CacheStatus, error)
+	// GetCatalogueItem returns a single catalogue item, or model.ErrNotFound.
+	GetCatalogueItem(ctx context.Context, name string) (*model.Item, CacheStatus, error)
+	// SyncTypes stores the damage relations of every type and the natural gift of every berry.
+	SyncTypes(ctx context.Context) error
+	// GetEffectiveness returns the damage multipliers against a defender and the
+	// berries whose natural gift is super effective against it.
+	GetEffectiveness(ctx context.Context, query model.EffectivenessQuery) (*model.EffectivenessResponse, CacheStatus, error)
+	// SyncNatures stores every nature with the flavors it likes and hates.
+	SyncNatures(ctx context.Context) error
+	// RecommendBerries ranks the berries for a nature or flavor preference, or
+	// returns model.ErrNotFound for an unknown nature or flavor.
+	RecommendBerries(ctx context.Context, query model.RecommendationQuery) (*model.RecommendationResponse, CacheStatus, error)
+	// SyncBerryGrowth stores how the tree of every berry grows.
+	SyncBerryGrowth(ctx context.Context) error
+	// PlanFarm plans the harvests and waterings of berries planted over
+	// plots, or returns model.ErrNotFound for a berry without growth data.
+	PlanFarm(ctx context.Context, query model.PlanQuery) (*model.PlanResponse, CacheStatus, error)
+	// SyncEvolutionChains stores every evolution chain as a graph of species and evolutions.
+	SyncEvolutionChains(ctx context.Context) error
+	// GetEvolutionChain returns the whole chain of a species, or model.ErrNotFound.
+	GetEvolutionChain(ctx context.Context, species string) (*model.EvolutionChainResponse, CacheStatus, error)
+	// GetEvolutionLinks returns how a species evolves and what it evolves from, or model.ErrNotFound.
+	GetEvolutionLinks(ctx context.Context, species string) (*model.EvolutionLinksResponse, CacheStatus, error)
+	// GetAsset returns a mirrored asset with its content, or model.ErrNotFound.
+	// Callers must close the content.
+	GetAsset(ctx context.Context, hash string) (*model.Asset, io.ReadSeekCloser, error)
+	// Search returns up to limit berries, items and Pokémon whose name or a
+	// localized name matches query, best first.
+	Search(ctx context.Context, query string, limit int) (*model.SearchResponse, CacheStatus, error)
+	// WarmCache pre-populates the cached listing and every per-berry entry.
+	WarmCache(ctx context.Context) error
+	// ListCacheKeys describes every key in the cache namespace.
+	ListCacheKeys(ctx context.Context) ([]model.CacheKey, error)
+	// DeleteCacheKey removes a single key, or returns model.ErrNotFound.
+	DeleteCacheKey(ctx context.Context, key string) error
+	// PurgeCache removes every key in the cache namespace and returns how many were removed.
+	PurgeCache(ctx context.Context) (int, error)
 }
 
+// NewService creates a Service, sprites keep their upstream urls when assets is nil.
 func NewService(repository repository.Repository,
 	redisRepository repository.RedisRepository,
-	client api.Client) Service {
+	client api.Client,
+	assets *Assets) Service {
 	return &service{
 		dbRepository:    repository,
 		client:          client,
 		redisRepository: redisRepository,
+		assets:          assets,
 	}
 }
 
+// syncPageSize is the number of berries requested per page while syncing.
+const syncPageSize = 100
+
 func (s *service) SyncData(ctx context.Context) error {
-	// get data from client
-	res, err := s.client.GetBerries(ctx, api.BerriesRequest{})
+	err := api.EachPage(ctx, s.client, api.EndpointBerry, syncPageSize, func(results []api.NamedAPIResource) error {
+		// insert to db
+		berries := constructBerries(results)
+		return s.dbRepository.CreateBerry(ctx, berries)
+	})
+	if err != nil {
+		return err
+	}
+
+	// reference data links the berries synced above
+	for _, syncReference := range []func(ctx context.Context) error{s.syncFirmnesses, s.syncFlavors} {
+		err = syncReference(ctx)
+		if errors.Is(err, api.ErrNotFound) {
+			// file and csv dumps only carry the berry listing
+			log.Printf("skipping berry reference data missing from the source: %v", err)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if _, err = s.dbRepository.CreateSyncRun(ctx); err != nil {
+		return err
+	}
+	s.rebuildSearchIndex(ctx)
+	if _, err = s.redisRepository.DeleteKey(ctx, flavorProfilesKey); err != nil {
+		log.Printf("failed to invalidate flavor profiles: %v", err)
+	}
+
+	// every cached language is dropped as warming only refills the default
+	// one. The synced rows are already stored, a failed invalidation only
+	// delays them until the cache expires
+	if err = s.redisRepository.DeleteData(ctx); err != nil {
+		log.Printf("failed to invalidate items cache after sync: %v", err)
+	}
+
+	warmCtx, cancel := context.WithTimeout(ctx, syncWarmupTimeout)
+	defer cancel()
+	if err = s.WarmCache(warmCtx); err != nil {
+		log.Printf("failed to warm items cache after sync: %v", err)
+	}
+
+	return nil
+}
+
+func (s *service) WarmCache(ctx context.Context) error {
+	// other languages are cached as they are requested
+	response, err := s.fetchItems(ctx, model.DefaultLanguage)
+	if err != nil {
+		return err
+	}
+
+	if err = s.redisRepository.SetData(ctx, model.DefaultLanguage, response); err != nil {
+		return err
+	}
+
+	for i := range response.Berries {
+		berry := response.Berries[i]
+		if err = s.redisRepository.SetValue(ctx, berryKey(berry.Name), &berry); err != nil {
+			return err
+		}
+	}
+
+	firmnesses, err := s.dbRepository.FetchFirmnesses(ctx)
+	if err != nil {
+		return err
+	}
+	if err = s.redisRepository.SetValue(ctx, firmnessesKey, firmnesses); err != nil {
+		return err
+	}
+
+	flavors, err := s.dbRepository.FetchFlavors(ctx)
 	if err != nil {
 		return err
 	}
 
-	// insert to db
-	berries := constructBerries(res)
-	err = s.dbRepository.CreateBerry(ctx, berries)
+	return s.redisRepository.SetValue(ctx, flavorsKey, flavors)
+}
+
+func (s *service) ListCacheKeys(ctx context.Context) ([]model.CacheKey, error) {
+	return s.redisRepository.ListKeys(ctx)
+}
+
+func (s *service) DeleteCacheKey(ctx context.Context, key string) error {
+	deleted, err := s.redisRepository.DeleteKey(ctx, key)
 	if err != nil {
 		return err
 	}
+	if !deleted {
+		return model.ErrNotFound
+	}
 
 	return nil
 }
 
-func constructBerries(res *api.BerriesResponse) []model.Berry {
-	berries := make([]model.Berry, 0, len(res.Results))
-	for _, result := range res.Results {
+func (s *service) PurgeCache(ctx context.Context) (int, error) {
+	return s.redisRepository.Purge(ctx)
+}
+
+func itemsLockName(lang string) string {
+	return itemsLock + ":" + lang
+}
+
+func berryKey(name string) string {
+	return berryKeyPrefix + name
+}
+
+func (s *service) syncFirmnesses(ctx context.Context) error {
+	return syncEach(ctx, s.client, func(ctx context.Context, firmness *api.BerryFirmness) error {
+		berries := constructBerries(firmness.Berries)
+		return s.dbRepository.CreateFirmness(ctx, model.Firmness{ID: firmness.ID, Name: firmness.Name, Berries: berries})
+	})
+}
+
+func (s *service) syncFlavors(ctx context.Context) error {
+	return syncEach(ctx, s.client, func(ctx context.Context, flavor *api.BerryFlavor) error {
+		berries := make([]model.FlavorBerry, 0, len(flavor.Berries))
+		for _, berry := range flavor.Berries {
+			// berries without the flavor are listed with no potency
+			if berry.Potency <= 0 {
+				continue
+			}
+			berries = append(berries, model.FlavorBerry{
+				Berry:   model.Berry{Name: berry.Berry.Name, URL: berry.Berry.URL},
+				Potency: berry.Potency,
+			})
+		}
+
+		return s.dbRepository.CreateFlavor(ctx, model.Flavor{ID: flavor.ID, Name: flavor.Name, Berries: berries})
+	})
+}
+
+// syncEach pages through the T list endpoint, fetches every listed T and
+// hands it to store.
+func syncEach[T api.Resource](ctx context.Context, c api.Client, store func(ctx context.Context, res *T) error) error {
+	return api.EachPage(ctx, c, api.Endpoint[T](), syncPageSize, func(results []api.NamedAPIResource) error {
+		for _, result := range results {
+			idOrName := result.Name
+			if idOrName == "" {
+				// unnamed resources, e.g. evolution chains, are listed by url only
+				idOrName = strconv.Itoa(result.ID())
+			}
+			res, err := api.Get[T](ctx, c, idOrName)
+			if err != nil {
+				return err
+			}
+
+			if err = store(ctx, res); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func constructBerries(results []api.NamedAPIResource) []model.Berry {
+	berries := make([]model.Berry, 0, len(results))
+	for _, result := range results {
 		berries = append(berries, model.Berry{
 			Name: result.Name,
-			URL:  result.Url,
+			URL:  result.URL,
 		})
 	}
 
 	return berries
 }
 
-func (s *service) GetItems(ctx context.Context) (*model.BerriesResponse, error) {
+func (s *service) GetItems(ctx context.Context, lang string) (*model.BerriesResponse, CacheStatus, error) {
+	cacheRes, stale, err := s.redisRepository.GetData(ctx, lang)
+	switch {
+	case errors.Is(err, repository.ErrCorruptEntry):
+		log.Printf("discarding items cache entry: %v", err)
+		if err = s.redisRepository.DeleteData(ctx); err != nil {
+			log.Printf("failed to delete corrupt items cache entry: %v", err)
+		}
+	case err != nil:
+		// redis is unavailable, serve from the database without touching it again
+		log.Printf("items cache unavailable, reading from database: %v", err)
+		response, err := s.fetchItems(ctx, lang)
+		if err != nil {
+			return nil, CacheBypass, err
+		}
+		return response, CacheBypass, nil
+	case cacheRes != nil && stale:
+		s.refreshItems(lang)
+		return cacheRes, CacheStale, nil
+	case cacheRes != nil:
+		return cacheRes, CacheHit, nil
+	}
+
+	// coalesce concurrent misses in this process into a single rebuild, it
+	// is shared by every waiting caller so it must outlive the first one
+	res, err, _ := s.rebuilds.Do(itemsLockName(lang), func() (interface{}, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), rebuildLockTTL)
+		defer cancel()
+
+		return s.rebuildItems(ctx, lang, true)
+	})
+	if err != nil {
+		return nil, CacheMiss, err
+	}
+
+	return res.(*model.BerriesResponse), CacheMiss, nil
+}
+
+func (s *service) StreamItems(ctx context.Context, lang string, stream ItemStream) error {
+	run, err := s.dbRepository.FetchLastSyncRun(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err = stream.Begin(run); err != nil {
+		return err
+	}
+
+	return s.dbRepository.StreamBerries(ctx, lang, stream.Write)
+}
+
+func (s *service) GetItem(ctx context.Context, name string) (*model.Berry, CacheStatus, error) {
+	return cachedValue(ctx, s, berryKey(name), func(ctx context.Context) (*model.Berry, error) {
+		return s.dbRepository.FetchBerry(ctx, name)
+	})
+}
+
+func (s *service) GetFirmnesses(ctx context.Context) (*model.FirmnessesResponse, CacheStatus, error) {
+	return cachedValue(ctx, s, firmnessesKey, s.dbRepository.FetchFirmnesses)
+}
 
-	cacheRes, err := s.redisRepository.GetData(ctx)
-	if cacheRes != nil {
-		return cacheRes, nil
+func (s *service) GetFlavors(ctx context.Context) (*model.FlavorsResponse, CacheStatus, error) {
+	return cachedValue(ctx, s, flavorsKey, s.dbRepository.FetchFlavors)
+}
+
+// cachedValue serves key from the cache, reading it with fetch and caching
+// it on a miss. Unlike the listing it is not guarded by a rebuild lock.
+func cachedValue[T any](ctx context.Context, s *service, key string, fetch func(ctx context.Context) (*T, error)) (*T, CacheStatus, error) {
+	var cacheRes T
+	found, err := s.redisRepository.GetValue(ctx, key, &cacheRes)
+	switch {
+	case errors.Is(err, repository.ErrCorruptEntry):
+		// the entry is overwritten below
+		log.Printf("discarding %s cache entry: %v", key, err)
+	case err != nil:
+		log.Printf("%s cache unavailable, reading from database: %v", key, err)
+		res, err := fetch(ctx)
+		if err != nil {
+			return nil, CacheBypass, err
+		}
+		return res, CacheBypass, nil
+	case found:
+		return &cacheRes, CacheHit, nil
 	}
 
-	data, err := s.dbRepository.FetchBerries(ctx)
+	res, err := fetch(ctx)
+	if err != nil {
+		return nil, CacheMiss, err
+	}
+
+	if err = s.redisRepository.SetValue(ctx, key, res); err != nil {
+		log.Printf("failed to cache %s: %v", key, err)
+	}
+
+	return res, CacheMiss, nil
+}
+
+// refreshItems rebuilds a stale listing in the background while callers
+// keep being served the stale value.
+func (s *service) refreshItems(lang string) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), rebuildLockTTL)
+		defer cancel()
+
+		_, _, _ = s.rebuilds.Do(itemsRefresh+":"+lang, func() (interface{}, error) {
+			return s.rebuildItems(ctx, lang, false)
+		})
+	}()
+}
+
+// rebuildItems reads the listing from the database and refreshes the cache.
+// Across replicas only the holder of the rebuild lock hits the database;
+// the others wait for the cache to be filled when wait is set, or leave the
+// rebuild to the lock holder and return nil otherwise.
+func (s *service) rebuildItems(ctx context.Context, lang string, wait bool) (*model.BerriesResponse, error) {
+	lock := itemsLockName(lang)
+	token, err := s.redisRepository.AcquireLock(ctx, lock, rebuildLockTTL)
+	if err == nil && token == "" {
+		if !wait {
+			return nil, nil
+		}
+		if cacheRes := s.waitForItems(ctx, lang); cacheRes != nil {
+			return cacheRes, nil
+		}
+	}
+	if token != "" {
+		defer func() {
+			_ = s.redisRepository.ReleaseLock(ctx, lock, token)
+		}()
+	}
+
+	response, err := s.fetchItems(ctx, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	// regardless the return from SetData, it should be return response
+	if err = s.redisRepository.SetData(ctx, lang, response); err != nil {
+		log.Printf("failed to cache items: %v", err)
+	}
+
+	return response, nil
+}
+
+// fetchItems reads the listing from the database.
+func (s *service) fetchItems(ctx context.Context, lang string) (*model.BerriesResponse, error) {
+	data, err := s.dbRepository.FetchBerries(ctx, lang)
 	if err != nil {
 		return nil, err
 	}
@@ -72,15 +467,39 @@ func (s *service) GetItems(ctx context.Context) (*model.BerriesResponse, error)
 	berries := make([]model.Berry, 0, len(data.Berries))
 	for _, berry := range data.Berries {
 		berries = append(berries, model.Berry{
-			Name: berry.Name,
-			URL:  berry.URL,
+			Name:        berry.Name,
+			URL:         berry.URL,
+			DisplayName: berry.DisplayName,
+			Description: berry.Description,
+			Sprite:      berry.Sprite,
 		})
 	}
 
-	response := &model.BerriesResponse{Berries: berries}
+	return &model.BerriesResponse{
+		Berries:  berries,
+		SyncID:   data.SyncID,
+		SyncedAt: data.SyncedAt,
+	}, nil
+}
 
-	err = s.redisRepository.SetData(ctx, response)
+// waitForItems polls the cache while another replica rebuilds it, giving up
+// after rebuildWait so a crashed lock holder cannot stall readers.
+func (s *service) waitForItems(ctx context.Context, lang string) *model.BerriesResponse {
+	deadline := time.NewTimer(rebuildWait)
+	defer deadline.Stop()
+	ticker := time.NewTicker(rebuildPollInterval)
+	defer ticker.Stop()
 
-	// regardless the return from SetData, it should be return response
-	return response, nil
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-deadline.C:
+			return nil
+		case <-ticker.C:
+			if cacheRes, _, _ := s.redisRepository.GetData(ctx, lang); cacheRes != nil {
+				return cacheRes
+			}
+		}
+	}
 }
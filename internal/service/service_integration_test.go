@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"github.com/go-resty/resty/v2"
+	"github.com/inasknh/simple-poke-app/internal/api"
+	"github.com/inasknh/simple-poke-app/internal/config"
+	"github.com/inasknh/simple-poke-app/internal/fakepokeapi"
+	mocks "github.com/inasknh/simple-poke-app/internal/mocks/repository"
+	"github.com/inasknh/simple-poke-app/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// newFakeAPIService wires a service to the fake PokeAPI through a real resty
+// client, using the same retry policy as cmd/server.
+func newFakeAPIService(server *fakepokeapi.Server, mockDB *mocks.Repository) *service {
+	restyClient := resty.New().
+		SetTimeout(time.Second).
+		SetRetryCount(3).
+		SetRetryWaitTime(time.Millisecond).
+		SetRetryMaxWaitTime(5 * time.Millisecond).
+		AddRetryCondition(func(response *resty.Response, err error) bool {
+			return err != nil || response.StatusCode() >= 500 || response.StatusCode() == http.StatusTooManyRequests
+		})
+
+	client := api.NewClient(config.Api{
+		Host: server.BaseURL(),
+	}, restyClient)
+
+	// every sync ends by rebuilding the search index
+	mockDB.On("FetchSearchDocuments", mock.Anything).Return(&model.SearchDocuments{}, nil)
+
+	mockRedis := &mocks.RedisRepository{}
+	mockRedis.On("SetData", mock.Anything, "en", mock.Anything).Return(nil)
+	mockRedis.On("DeleteData", mock.Anything).Return(nil)
+	mockRedis.On("SetValue", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockRedis.On("DeleteKey", mock.Anything, flavorProfilesKey).Return(true, nil)
+
+	return &service{
+		dbRepository:    mockDB,
+		redisRepository: mockRedis,
+		client:          client,
+	}
+}
+
+func recordCreatedBerries(mockDB *mocks.Repository) *[]model.Berry {
+	var created []model.Berry
+	mockDB.
+		On("CreateBerry", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			created = append(created, args.Get(1).([]model.Berry)...)
+		}).
+		Return(nil)
+	mockDB.
+		On("CreateFirmness", mock.Anything, mock.Anything).
+		Return(nil)
+	mockDB.
+		On("CreateFlavor", mock.Anything, mock.Anything).
+		Return(nil)
+	mockDB.
+		On("FetchFirmnesses", mock.Anything).
+		Return(&model.FirmnessesResponse{}, nil)
+	mockDB.
+		On("FetchFlavors", mock.Anything).
+		Return(&model.FlavorsResponse{}, nil)
+	mockDB.
+		On("CreateSyncRun", mock.Anything).
+		Return(&model.SyncRun{ID: 1}, nil)
+	mockDB.
+		On("FetchBerries", mock.Anything, "en").
+		Return(func(ctx context.Context, lang string) (*model.BerriesResponse, error) {
+			return &model.BerriesResponse{Berries: created}, nil
+		})
+	return &created
+}
+
+func Test_service_SyncData_FakePokeAPI(t *testing.T) {
+	tests := []struct {
+		name         string
+		fault        func(server *fakepokeapi.Server)
+		wantErr      bool
+		wantBerries  int
+		wantRequests int
+	}{
+		{
+			name:         "given healthy upstream should sync every berry",
+			fault:        func(server *fakepokeapi.Server) {},
+			wantErr:      false,
+			wantBerries:  12,
+			wantRequests: 13,
+		},
+		{
+			name: "given upstream capping the page size should follow pagination",
+			fault: func(server *fakepokeapi.Server) {
+				server.SetMaxLimit(5)
+			},
+			wantErr:      false,
+			wantBerries:  12,
+			wantRequests: 15,
+		},
+		{
+			name: "given transient server errors should retry and sync every berry",
+			fault: func(server *fakepokeapi.Server) {
+				server.FailWithServerErrors(2)
+			},
+			wantErr:      false,
+			wantBerries:  12,
+			wantRequests: 15,
+		},
+		{
+			name: "given rate limiting should retry and sync every berry",
+			fault: func(server *fakepokeapi.Server) {
+				server.FailWithTooManyRequests(1)
+			},
+			wantErr:      false,
+			wantBerries:  12,
+			wantRequests: 14,
+		},
+		{
+			name: "given persistent server errors should return an error",
+			fault: func(server *fakepokeapi.Server) {
+				server.FailWithServerErrors(10)
+			},
+			wantErr:      true,
+			wantBerries:  0,
+			wantRequests: 4,
+		},
+		{
+			name: "given malformed json should return an error",
+			fault: func(server *fakepokeapi.Server) {
+				server.FailWithMalformedJSON(1)
+			},
+			wantErr:      true,
+			wantBerries:  0,
+			wantRequests: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := fakepokeapi.New()
+			defer server.Close()
+			tt.fault(server)
+
+			mockDB := &mocks.Repository{}
+			created := recordCreatedBerries(mockDB)
+			s := newFakeAPIService(server, mockDB)
+
+			err := s.SyncData(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SyncData() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			assert.Len(t, *created, tt.wantBerries)
+			assert.Equal(t, tt.wantRequests, server.Requests())
+		})
+	}
+}
+
+func Test_service_SyncData_FakePokeAPI_Latency(t *testing.T) {
+	server := fakepokeapi.New()
+	defer server.Close()
+	server.SetLatency(50 * time.Millisecond)
+
+	mockDB := &mocks.Repository{}
+	recordCreatedBerries(mockDB)
+	s := newFakeAPIService(server, mockDB)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := s.SyncData(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func Test_service_SyncData_FakePokeAPI_ReferenceData(t *testing.T) {
+	server := fakepokeapi.New()
+	defer server.Close()
+
+	mockDB := &mocks.Repository{}
+	firmnesses := map[string][]string{}
+	mockDB.
+		On("CreateFirmness", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			firmness := args.Get(1).(model.Firmness)
+			for _, berry := range firmness.Berries {
+				firmnesses[firmness.Name] = append(firmnesses[firmness.Name], berry.Name)
+			}
+		}).
+		Return(nil)
+	flavors := map[string][]model.FlavorBerry{}
+	mockDB.
+		On("CreateFlavor", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			flavor := args.Get(1).(model.Flavor)
+			flavors[flavor.Name] = flavor.Berries
+		}).
+		Return(nil)
+	recordCreatedBerries(mockDB)
+	s := newFakeAPIService(server, mockDB)
+
+	err := s.SyncData(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, firmnesses, 5)
+	assert.Equal(t, []string{"cheri", "figy"}, firmnesses["soft"])
+	assert.Len(t, flavors, 5)
+	assert.Len(t, flavors["sweet"], 5)
+	assert.Contains(t, flavors["dry"], model.FlavorBerry{
+		Berry:   model.Berry{Name: "wiki", URL: server.BaseURL() + "berry/12/"},
+		Potency: 15,
+	})
+}
@@ -3,14 +3,18 @@ package service
 import (
 	"context"
 	"errors"
-	"github.com/go-redis/redis/v7"
+	"fmt"
 	"github.com/inasknh/simple-poke-app/internal/api"
 	mocks2 "github.com/inasknh/simple-poke-app/internal/mocks/api"
 	mocks "github.com/inasknh/simple-poke-app/internal/mocks/repository"
 	"github.com/inasknh/simple-poke-app/internal/model"
+	"github.com/inasknh/simple-poke-app/internal/repository"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"reflect"
+	"sync"
 	"testing"
+	"time"
 )
 
 func Test_service_SyncData(t *testing.T) {
@@ -35,7 +39,7 @@ func Test_service_SyncData(t *testing.T) {
 				mockClient := &mocks2.Client{}
 
 				mockClient.
-					On("GetBerries", mock.Anything, api.BerriesRequest{}).
+					On("ListResources", mock.Anything, api.EndpointBerry, api.ListRequest{Limit: syncPageSize}).
 					Return(nil, errors.New("an error"))
 				return &service{
 					dbRepository:    mockDB,
@@ -56,15 +60,15 @@ func Test_service_SyncData(t *testing.T) {
 				mockClient := &mocks2.Client{}
 
 				mockClient.
-					On("GetBerries", mock.Anything, api.BerriesRequest{}).
-					Return(&api.BerriesResponse{
+					On("ListResources", mock.Anything, api.EndpointBerry, api.ListRequest{Limit: syncPageSize}).
+					Return(&api.NamedAPIResourceList{
 						Count:    0,
 						Next:     "",
 						Previous: "",
-						Results: []api.Berry{
+						Results: []api.NamedAPIResource{
 							{
 								Name: "1",
-								Url:  "1",
+								URL:  "1",
 							},
 						},
 					}, nil)
@@ -94,15 +98,15 @@ func Test_service_SyncData(t *testing.T) {
 				mockClient := &mocks2.Client{}
 
 				mockClient.
-					On("GetBerries", mock.Anything, api.BerriesRequest{}).
-					Return(&api.BerriesResponse{
+					On("ListResources", mock.Anything, api.EndpointBerry, api.ListRequest{Limit: syncPageSize}).
+					Return(&api.NamedAPIResourceList{
 						Count:    0,
 						Next:     "",
 						Previous: "",
-						Results: []api.Berry{
+						Results: []api.NamedAPIResource{
 							{
 								Name: "1",
-								Url:  "1",
+								URL:  "1",
 							},
 						},
 					}, nil)
@@ -113,6 +117,197 @@ func Test_service_SyncData(t *testing.T) {
 						URL:  "1",
 					},
 				}).Return(nil)
+				mockClient.
+					On("ListResources", mock.Anything, api.EndpointBerryFirmness, api.ListRequest{Limit: syncPageSize}).
+					Return(&api.NamedAPIResourceList{}, nil)
+				mockClient.
+					On("ListResources", mock.Anything, api.EndpointBerryFlavor, api.ListRequest{Limit: syncPageSize}).
+					Return(nil, api.ErrNotFound)
+				mockDB.On("CreateSyncRun", mock.Anything).Return(&model.SyncRun{ID: 1}, nil)
+				mockDB.On("FetchSearchDocuments", mock.Anything).Return(&model.SearchDocuments{}, nil)
+				mockRedis.On("SetValue", mock.Anything, searchDocumentsKey, mock.Anything).Return(nil)
+				mockRedis.On("DeleteKey", mock.Anything, flavorProfilesKey).Return(true, nil)
+				mockRedis.On("DeleteData", mock.Anything).Return(nil)
+				mockDB.On("FetchBerries", mock.Anything, "en").Return(&model.BerriesResponse{Berries: []model.Berry{
+					{
+						Name: "1",
+						URL:  "1",
+					},
+				}}, nil)
+				mockRedis.On("SetData", mock.Anything, "en", mock.Anything).Return(nil)
+				mockDB.On("FetchFirmnesses", mock.Anything).Return(&model.FirmnessesResponse{}, nil)
+				mockDB.On("FetchFlavors", mock.Anything).Return(&model.FlavorsResponse{}, nil)
+				mockRedis.On("SetValue", mock.Anything, firmnessesKey, mock.Anything).Return(nil)
+				mockRedis.On("SetValue", mock.Anything, flavorsKey, mock.Anything).Return(nil)
+				mockRedis.On("SetValue", mock.Anything, "berry:1", &model.Berry{Name: "1", URL: "1"}).Return(nil)
+				return &service{
+					dbRepository:    mockDB,
+					redisRepository: mockRedis,
+					client:          mockClient,
+				}
+			},
+		},
+		{
+			name: "given more than one page from client should insert every page",
+			args: args{
+				ctx: context.Background(),
+			},
+			wantErr: false,
+			mockFunc: func() *service {
+				mockDB := &mocks.Repository{}
+				mockRedis := &mocks.RedisRepository{}
+				mockClient := &mocks2.Client{}
+
+				mockClient.
+					On("ListResources", mock.Anything, api.EndpointBerry, api.ListRequest{Limit: syncPageSize}).
+					Return(&api.NamedAPIResourceList{
+						Count: 2,
+						Next:  "next",
+						Results: []api.NamedAPIResource{
+							{
+								Name: "1",
+								URL:  "1",
+							},
+						},
+					}, nil)
+				mockClient.
+					On("ListResources", mock.Anything, api.EndpointBerry, api.ListRequest{Limit: syncPageSize, Offset: 1}).
+					Return(&api.NamedAPIResourceList{
+						Count: 2,
+						Results: []api.NamedAPIResource{
+							{
+								Name: "2",
+								URL:  "2",
+							},
+						},
+					}, nil)
+
+				mockDB.On("CreateBerry", mock.Anything, []model.Berry{
+					{
+						Name: "1",
+						URL:  "1",
+					},
+				}).Return(nil)
+				mockDB.On("CreateBerry", mock.Anything, []model.Berry{
+					{
+						Name: "2",
+						URL:  "2",
+					},
+				}).Return(nil)
+				mockClient.
+					On("ListResources", mock.Anything, api.EndpointBerryFirmness, api.ListRequest{Limit: syncPageSize}).
+					Return(&api.NamedAPIResourceList{}, nil)
+				mockClient.
+					On("ListResources", mock.Anything, api.EndpointBerryFlavor, api.ListRequest{Limit: syncPageSize}).
+					Return(nil, api.ErrNotFound)
+				mockDB.On("CreateSyncRun", mock.Anything).Return(&model.SyncRun{ID: 1}, nil)
+				mockDB.On("FetchSearchDocuments", mock.Anything).Return(&model.SearchDocuments{}, nil)
+				mockRedis.On("SetValue", mock.Anything, searchDocumentsKey, mock.Anything).Return(nil)
+				mockRedis.On("DeleteKey", mock.Anything, flavorProfilesKey).Return(true, nil)
+				mockRedis.On("DeleteData", mock.Anything).Return(nil)
+				mockDB.On("FetchBerries", mock.Anything, "en").Return(&model.BerriesResponse{Berries: []model.Berry{
+					{
+						Name: "1",
+						URL:  "1",
+					},
+					{
+						Name: "2",
+						URL:  "2",
+					},
+				}}, nil)
+				mockRedis.On("SetData", mock.Anything, "en", mock.Anything).Return(nil)
+				mockDB.On("FetchFirmnesses", mock.Anything).Return(&model.FirmnessesResponse{}, nil)
+				mockDB.On("FetchFlavors", mock.Anything).Return(&model.FlavorsResponse{}, nil)
+				mockRedis.On("SetValue", mock.Anything, firmnessesKey, mock.Anything).Return(nil)
+				mockRedis.On("SetValue", mock.Anything, flavorsKey, mock.Anything).Return(nil)
+				mockRedis.On("SetValue", mock.Anything, "berry:1", mock.Anything).Return(nil)
+				mockRedis.On("SetValue", mock.Anything, "berry:2", mock.Anything).Return(nil)
+				return &service{
+					dbRepository:    mockDB,
+					redisRepository: mockRedis,
+					client:          mockClient,
+				}
+			},
+		},
+		{
+			name: "given an error when recording the sync run should return an error",
+			args: args{
+				ctx: context.Background(),
+			},
+			wantErr: true,
+			mockFunc: func() *service {
+				mockDB := &mocks.Repository{}
+				mockRedis := &mocks.RedisRepository{}
+				mockClient := &mocks2.Client{}
+
+				mockClient.
+					On("ListResources", mock.Anything, api.EndpointBerry, api.ListRequest{Limit: syncPageSize}).
+					Return(&api.NamedAPIResourceList{
+						Count: 1,
+						Results: []api.NamedAPIResource{
+							{
+								Name: "1",
+								URL:  "1",
+							},
+						},
+					}, nil)
+
+				mockDB.On("CreateBerry", mock.Anything, mock.Anything).Return(nil)
+				mockClient.
+					On("ListResources", mock.Anything, api.EndpointBerryFirmness, api.ListRequest{Limit: syncPageSize}).
+					Return(&api.NamedAPIResourceList{}, nil)
+				mockClient.
+					On("ListResources", mock.Anything, api.EndpointBerryFlavor, api.ListRequest{Limit: syncPageSize}).
+					Return(nil, api.ErrNotFound)
+				mockDB.On("CreateSyncRun", mock.Anything).Return(nil, errors.New("an error"))
+				return &service{
+					dbRepository:    mockDB,
+					redisRepository: mockRedis,
+					client:          mockClient,
+				}
+			},
+		},
+		{
+			name: "given an error when warming and invalidating the cache should return nil error",
+			args: args{
+				ctx: context.Background(),
+			},
+			wantErr: false,
+			mockFunc: func() *service {
+				mockDB := &mocks.Repository{}
+				mockRedis := &mocks.RedisRepository{}
+				mockClient := &mocks2.Client{}
+
+				mockClient.
+					On("ListResources", mock.Anything, api.EndpointBerry, api.ListRequest{Limit: syncPageSize}).
+					Return(&api.NamedAPIResourceList{
+						Count: 1,
+						Results: []api.NamedAPIResource{
+							{
+								Name: "1",
+								URL:  "1",
+							},
+						},
+					}, nil)
+
+				mockDB.On("CreateBerry", mock.Anything, []model.Berry{
+					{
+						Name: "1",
+						URL:  "1",
+					},
+				}).Return(nil)
+				mockClient.
+					On("ListResources", mock.Anything, api.EndpointBerryFirmness, api.ListRequest{Limit: syncPageSize}).
+					Return(&api.NamedAPIResourceList{}, nil)
+				mockClient.
+					On("ListResources", mock.Anything, api.EndpointBerryFlavor, api.ListRequest{Limit: syncPageSize}).
+					Return(nil, api.ErrNotFound)
+				mockDB.On("CreateSyncRun", mock.Anything).Return(&model.SyncRun{ID: 1}, nil)
+				mockDB.On("FetchSearchDocuments", mock.Anything).Return(&model.SearchDocuments{}, nil)
+				mockRedis.On("SetValue", mock.Anything, searchDocumentsKey, mock.Anything).Return(nil)
+				mockRedis.On("DeleteKey", mock.Anything, flavorProfilesKey).Return(true, nil)
+				mockDB.On("FetchBerries", mock.Anything, "en").Return(nil, errors.New("an error"))
+				mockRedis.On("DeleteData", mock.Anything).Return(errors.New("an error"))
 				return &service{
 					dbRepository:    mockDB,
 					redisRepository: mockRedis,
@@ -131,16 +326,63 @@ func Test_service_SyncData(t *testing.T) {
 	}
 }
 
+func Test_service_SyncData_DropsEveryLanguage(t *testing.T) {
+	mockDB := &mocks.Repository{}
+	mockRedis := &mocks.RedisRepository{}
+	mockClient := &mocks2.Client{}
+
+	// cached listings per language, as the redis repository would hold them
+	stale := &model.BerriesResponse{Berries: []model.Berry{{Name: "1", DisplayName: "Ancienne"}}}
+	cached := map[string]*model.BerriesResponse{"en": stale, "fr": stale}
+	mockRedis.On("DeleteData", mock.Anything).
+		Run(func(args mock.Arguments) {
+			clear(cached)
+		}).
+		Return(nil)
+	mockRedis.On("SetData", mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			cached[args.String(1)] = args.Get(2).(*model.BerriesResponse)
+		}).
+		Return(nil)
+	mockRedis.On("SetValue", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockRedis.On("DeleteKey", mock.Anything, flavorProfilesKey).Return(true, nil)
+
+	mockClient.
+		On("ListResources", mock.Anything, api.EndpointBerry, api.ListRequest{Limit: syncPageSize}).
+		Return(&api.NamedAPIResourceList{Results: []api.NamedAPIResource{{Name: "1", URL: "1"}}}, nil)
+	mockClient.
+		On("ListResources", mock.Anything, mock.Anything, api.ListRequest{Limit: syncPageSize}).
+		Return(&api.NamedAPIResourceList{}, nil)
+	fresh := &model.BerriesResponse{Berries: []model.Berry{{Name: "1", URL: "1"}}}
+	mockDB.On("CreateBerry", mock.Anything, mock.Anything).Return(nil)
+	mockDB.On("CreateSyncRun", mock.Anything).Return(&model.SyncRun{ID: 1}, nil)
+	mockDB.On("FetchSearchDocuments", mock.Anything).Return(&model.SearchDocuments{}, nil)
+	mockDB.On("FetchBerries", mock.Anything, "en").Return(fresh, nil)
+	mockDB.On("FetchFirmnesses", mock.Anything).Return(&model.FirmnessesResponse{}, nil)
+	mockDB.On("FetchFlavors", mock.Anything).Return(&model.FlavorsResponse{}, nil)
+	s := &service{
+		dbRepository:    mockDB,
+		redisRepository: mockRedis,
+		client:          mockClient,
+	}
+
+	err := s.SyncData(context.Background())
+	assert.NoError(t, err)
+	// the default language is warmed, the others are read again on request
+	assert.Equal(t, map[string]*model.BerriesResponse{"en": fresh}, cached)
+}
+
 func Test_service_GetItems(t *testing.T) {
 	type args struct {
 		ctx context.Context
 	}
 	tests := []struct {
-		name     string
-		args     args
-		want     *model.BerriesResponse
-		wantErr  bool
-		mockFunc func() *service
+		name       string
+		args       args
+		want       *model.BerriesResponse
+		wantStatus CacheStatus
+		wantErr    bool
+		mockFunc   func() *service
 	}{
 		{
 			name: "given result from redis should return response and no error",
@@ -155,20 +397,21 @@ func Test_service_GetItems(t *testing.T) {
 					},
 				},
 			},
-			wantErr: false,
+			wantStatus: CacheHit,
+			wantErr:    false,
 			mockFunc: func() *service {
 				mockDB := &mocks.Repository{}
 				mockRedis := &mocks.RedisRepository{}
 				mockClient := &mocks2.Client{}
 
 				mockRedis.
-					On("GetData", mock.Anything).
+					On("GetData", mock.Anything, "en").
 					Return(&model.BerriesResponse{Berries: []model.Berry{
 						{
 							Name: "1",
 							URL:  "1",
 						},
-					}}, nil)
+					}}, false, nil)
 				return &service{
 					dbRepository:    mockDB,
 					redisRepository: mockRedis,
@@ -182,19 +425,26 @@ func Test_service_GetItems(t *testing.T) {
 			args: args{
 				ctx: context.Background(),
 			},
-			want:    nil,
-			wantErr: true,
+			want:       nil,
+			wantStatus: CacheMiss,
+			wantErr:    true,
 			mockFunc: func() *service {
 				mockDB := &mocks.Repository{}
 				mockRedis := &mocks.RedisRepository{}
 				mockClient := &mocks2.Client{}
 
 				mockRedis.
-					On("GetData", mock.Anything).
-					Return(nil, redis.Nil)
+					On("GetData", mock.Anything, "en").
+					Return(nil, false, nil)
+				mockRedis.
+					On("AcquireLock", mock.Anything, "items:en", rebuildLockTTL).
+					Return("token", nil)
+				mockRedis.
+					On("ReleaseLock", mock.Anything, "items:en", "token").
+					Return(nil)
 
 				mockDB.
-					On("FetchBerries", mock.Anything).
+					On("FetchBerries", mock.Anything, "en").
 					Return(nil, errors.New("an error"))
 
 				return &service{
@@ -218,18 +468,25 @@ func Test_service_GetItems(t *testing.T) {
 					},
 				},
 			},
-			wantErr: false,
+			wantStatus: CacheMiss,
+			wantErr:    false,
 			mockFunc: func() *service {
 				mockDB := &mocks.Repository{}
 				mockRedis := &mocks.RedisRepository{}
 				mockClient := &mocks2.Client{}
 
 				mockRedis.
-					On("GetData", mock.Anything).
-					Return(nil, redis.Nil)
+					On("GetData", mock.Anything, "en").
+					Return(nil, false, nil)
+				mockRedis.
+					On("AcquireLock", mock.Anything, "items:en", rebuildLockTTL).
+					Return("token", nil)
+				mockRedis.
+					On("ReleaseLock", mock.Anything, "items:en", "token").
+					Return(nil)
 
 				mockDB.
-					On("FetchBerries", mock.Anything).
+					On("FetchBerries", mock.Anything, "en").
 					Return(&model.BerriesResponse{
 						Berries: []model.Berry{
 							{
@@ -239,7 +496,7 @@ func Test_service_GetItems(t *testing.T) {
 						},
 					}, nil)
 
-				mockRedis.On("SetData", mock.Anything, &model.BerriesResponse{
+				mockRedis.On("SetData", mock.Anything, "en", &model.BerriesResponse{
 					Berries: []model.Berry{
 						{
 							Name: "1",
@@ -269,18 +526,25 @@ func Test_service_GetItems(t *testing.T) {
 					},
 				},
 			},
-			wantErr: false,
+			wantStatus: CacheMiss,
+			wantErr:    false,
 			mockFunc: func() *service {
 				mockDB := &mocks.Repository{}
 				mockRedis := &mocks.RedisRepository{}
 				mockClient := &mocks2.Client{}
 
 				mockRedis.
-					On("GetData", mock.Anything).
-					Return(nil, redis.Nil)
+					On("GetData", mock.Anything, "en").
+					Return(nil, false, nil)
+				mockRedis.
+					On("AcquireLock", mock.Anything, "items:en", rebuildLockTTL).
+					Return("token", nil)
+				mockRedis.
+					On("ReleaseLock", mock.Anything, "items:en", "token").
+					Return(nil)
 
 				mockDB.
-					On("FetchBerries", mock.Anything).
+					On("FetchBerries", mock.Anything, "en").
 					Return(&model.BerriesResponse{
 						Berries: []model.Berry{
 							{
@@ -290,7 +554,7 @@ func Test_service_GetItems(t *testing.T) {
 						},
 					}, nil)
 
-				mockRedis.On("SetData", mock.Anything, &model.BerriesResponse{
+				mockRedis.On("SetData", mock.Anything, "en", &model.BerriesResponse{
 					Berries: []model.Berry{
 						{
 							Name: "1",
@@ -299,6 +563,224 @@ func Test_service_GetItems(t *testing.T) {
 					},
 				}).Return(nil)
 
+				return &service{
+					dbRepository:    mockDB,
+					redisRepository: mockRedis,
+					client:          mockClient,
+				}
+			},
+		},
+		{
+			name: "given rebuild lock held by another replica should wait" +
+				" and return response from redis without calling FetchBerries",
+			args: args{
+				ctx: context.Background(),
+			},
+			want: &model.BerriesResponse{
+				Berries: []model.Berry{
+					{
+						Name: "1",
+						URL:  "1",
+					},
+				},
+			},
+			wantStatus: CacheMiss,
+			wantErr:    false,
+			mockFunc: func() *service {
+				mockDB := &mocks.Repository{}
+				mockRedis := &mocks.RedisRepository{}
+				mockClient := &mocks2.Client{}
+
+				mockRedis.
+					On("GetData", mock.Anything, "en").
+					Return(nil, false, nil).
+					Once()
+				mockRedis.
+					On("AcquireLock", mock.Anything, "items:en", rebuildLockTTL).
+					Return("", nil)
+				mockRedis.
+					On("GetData", mock.Anything, "en").
+					Return(&model.BerriesResponse{Berries: []model.Berry{
+						{
+							Name: "1",
+							URL:  "1",
+						},
+					}}, false, nil)
+
+				return &service{
+					dbRepository:    mockDB,
+					redisRepository: mockRedis,
+					client:          mockClient,
+				}
+			},
+		},
+		{
+			name: "given an error when AcquireLock should still rebuild from database" +
+				" and return response and no error",
+			args: args{
+				ctx: context.Background(),
+			},
+			want: &model.BerriesResponse{
+				Berries: []model.Berry{
+					{
+						Name: "1",
+						URL:  "1",
+					},
+				},
+			},
+			wantStatus: CacheMiss,
+			wantErr:    false,
+			mockFunc: func() *service {
+				mockDB := &mocks.Repository{}
+				mockRedis := &mocks.RedisRepository{}
+				mockClient := &mocks2.Client{}
+
+				mockRedis.
+					On("GetData", mock.Anything, "en").
+					Return(nil, false, nil)
+				mockRedis.
+					On("AcquireLock", mock.Anything, "items:en", rebuildLockTTL).
+					Return("", errors.New("an error"))
+
+				mockDB.
+					On("FetchBerries", mock.Anything, "en").
+					Return(&model.BerriesResponse{
+						Berries: []model.Berry{
+							{
+								Name: "1",
+								URL:  "1",
+							},
+						},
+					}, nil)
+
+				mockRedis.On("SetData", mock.Anything, "en", mock.Anything).Return(nil)
+
+				return &service{
+					dbRepository:    mockDB,
+					redisRepository: mockRedis,
+					client:          mockClient,
+				}
+			},
+		},
+		{
+			name: "given corrupt entry in redis should delete it" +
+				" and rebuild from database",
+			args: args{
+				ctx: context.Background(),
+			},
+			want: &model.BerriesResponse{
+				Berries: []model.Berry{
+					{
+						Name: "1",
+						URL:  "1",
+					},
+				},
+			},
+			wantStatus: CacheMiss,
+			wantErr:    false,
+			mockFunc: func() *service {
+				mockDB := &mocks.Repository{}
+				mockRedis := &mocks.RedisRepository{}
+				mockClient := &mocks2.Client{}
+
+				mockRedis.
+					On("GetData", mock.Anything, "en").
+					Return(nil, false, fmt.Errorf("%w: invalid character", repository.ErrCorruptEntry))
+				mockRedis.
+					On("DeleteData", mock.Anything).
+					Return(nil)
+				mockRedis.
+					On("AcquireLock", mock.Anything, "items:en", rebuildLockTTL).
+					Return("token", nil)
+				mockRedis.
+					On("ReleaseLock", mock.Anything, "items:en", "token").
+					Return(nil)
+
+				mockDB.
+					On("FetchBerries", mock.Anything, "en").
+					Return(&model.BerriesResponse{
+						Berries: []model.Berry{
+							{
+								Name: "1",
+								URL:  "1",
+							},
+						},
+					}, nil)
+
+				mockRedis.On("SetData", mock.Anything, "en", mock.Anything).Return(nil)
+
+				return &service{
+					dbRepository:    mockDB,
+					redisRepository: mockRedis,
+					client:          mockClient,
+				}
+			},
+		},
+		{
+			name: "given redis unavailable should read from database" +
+				" without rebuilding the cache",
+			args: args{
+				ctx: context.Background(),
+			},
+			want: &model.BerriesResponse{
+				Berries: []model.Berry{
+					{
+						Name: "1",
+						URL:  "1",
+					},
+				},
+			},
+			wantStatus: CacheBypass,
+			wantErr:    false,
+			mockFunc: func() *service {
+				mockDB := &mocks.Repository{}
+				mockRedis := mocks.NewRedisRepository(t)
+				mockClient := &mocks2.Client{}
+
+				mockRedis.
+					On("GetData", mock.Anything, "en").
+					Return(nil, false, context.DeadlineExceeded)
+
+				mockDB.
+					On("FetchBerries", mock.Anything, "en").
+					Return(&model.BerriesResponse{
+						Berries: []model.Berry{
+							{
+								Name: "1",
+								URL:  "1",
+							},
+						},
+					}, nil)
+
+				return &service{
+					dbRepository:    mockDB,
+					redisRepository: mockRedis,
+					client:          mockClient,
+				}
+			},
+		},
+		{
+			name: "given redis unavailable and an error when call FetchBerries" +
+				" should return nil response and an error",
+			args: args{
+				ctx: context.Background(),
+			},
+			want:       nil,
+			wantStatus: CacheBypass,
+			wantErr:    true,
+			mockFunc: func() *service {
+				mockDB := &mocks.Repository{}
+				mockRedis := &mocks.RedisRepository{}
+				mockClient := &mocks2.Client{}
+
+				mockRedis.
+					On("GetData", mock.Anything, "en").
+					Return(nil, false, context.DeadlineExceeded)
+
+				mockDB.
+					On("FetchBerries", mock.Anything, "en").
+					Return(nil, errors.New("an error"))
+
 				return &service{
 					dbRepository:    mockDB,
 					redisRepository: mockRedis,
@@ -310,14 +792,506 @@ func Test_service_GetItems(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			s := tt.mockFunc()
-			got, err := s.GetItems(tt.args.ctx)
+			got, status, err := s.GetItems(tt.args.ctx, "en")
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetItems() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			if status != tt.wantStatus {
+				t.Errorf("GetItems() status = %v, want %v", status, tt.wantStatus)
+			}
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("GetItems() got = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
+
+func Test_service_GetItems_Language(t *testing.T) {
+	mockDB := &mocks.Repository{}
+	mockRedis := &mocks.RedisRepository{}
+	english := &model.BerriesResponse{Berries: []model.Berry{{Name: "cheri", DisplayName: "Cheri Berry"}}}
+	french := &model.BerriesResponse{Berries: []model.Berry{{Name: "cheri", DisplayName: "Baie Ceriz"}}}
+
+	mockRedis.On("GetData", mock.Anything, "en").Return(english, false, nil)
+	mockRedis.On("GetData", mock.Anything, "fr").Return(nil, false, nil)
+	mockRedis.On("AcquireLock", mock.Anything, "items:fr", rebuildLockTTL).Return("token", nil)
+	mockRedis.On("ReleaseLock", mock.Anything, "items:fr", "token").Return(nil)
+	mockRedis.On("SetData", mock.Anything, "fr", french).Return(nil)
+	mockDB.On("FetchBerries", mock.Anything, "fr").Return(french, nil)
+
+	s := &service{
+		dbRepository:    mockDB,
+		redisRepository: mockRedis,
+	}
+
+	got, status, err := s.GetItems(context.Background(), "fr")
+	assert.NoError(t, err)
+	assert.Equal(t, CacheMiss, status)
+	assert.Equal(t, french, got)
+
+	// each language is cached on its own
+	got, status, err = s.GetItems(context.Background(), "en")
+	assert.NoError(t, err)
+	assert.Equal(t, CacheHit, status)
+	assert.Equal(t, english, got)
+	mockRedis.AssertExpectations(t)
+}
+
+func Test_service_GetItems_ConcurrentMisses(t *testing.T) {
+	mockDB := &mocks.Repository{}
+	mockRedis := &mocks.RedisRepository{}
+
+	mockRedis.
+		On("GetData", mock.Anything, "en").
+		Return(nil, false, nil)
+	mockRedis.
+		On("AcquireLock", mock.Anything, "items:en", rebuildLockTTL).
+		Return("token", nil)
+	mockRedis.
+		On("ReleaseLock", mock.Anything, "items:en", "token").
+		Return(nil)
+	mockRedis.
+		On("SetData", mock.Anything, "en", mock.Anything).
+		Return(nil)
+
+	mockDB.
+		On("FetchBerries", mock.Anything, "en").
+		After(50*time.Millisecond).
+		Return(&model.BerriesResponse{Berries: []model.Berry{
+			{
+				Name: "1",
+				URL:  "1",
+			},
+		}}, nil)
+
+	s := &service{
+		dbRepository:    mockDB,
+		redisRepository: mockRedis,
+		client:          &mocks2.Client{},
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, _, err := s.GetItems(context.Background(), "en")
+			assert.NoError(t, err)
+			assert.Len(t, got.Berries, 1)
+		}()
+	}
+	wg.Wait()
+
+	mockDB.AssertNumberOfCalls(t, "FetchBerries", 1)
+}
+
+func Test_service_GetItems_CancelledCaller(t *testing.T) {
+	mockDB := &mocks.Repository{}
+	mockRedis := &mocks.RedisRepository{}
+
+	mockRedis.
+		On("GetData", mock.Anything, "en").
+		Return(nil, false, nil)
+	mockRedis.
+		On("AcquireLock", mock.Anything, "items:en", rebuildLockTTL).
+		Return("token", nil)
+	mockRedis.
+		On("ReleaseLock", mock.Anything, "items:en", "token").
+		Return(nil)
+	mockRedis.
+		On("SetData", mock.Anything, "en", mock.Anything).
+		Return(nil)
+
+	// the rebuild is shared with other callers, it must not be cancelled
+	// with the caller that started it and must still be bounded
+	mockDB.
+		On("FetchBerries", mock.MatchedBy(func(ctx context.Context) bool {
+			_, ok := ctx.Deadline()
+			return ctx.Err() == nil && ok
+		}), "en").
+		Return(&model.BerriesResponse{Berries: []model.Berry{
+			{
+				Name: "1",
+				URL:  "1",
+			},
+		}}, nil)
+
+	s := &service{
+		dbRepository:    mockDB,
+		redisRepository: mockRedis,
+		client:          &mocks2.Client{},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	got, status, err := s.GetItems(ctx, "en")
+	assert.NoError(t, err)
+	assert.Equal(t, CacheMiss, status)
+	assert.Len(t, got.Berries, 1)
+	mockDB.AssertExpectations(t)
+}
+
+func Test_service_GetItems_StaleWhileRevalidate(t *testing.T) {
+	mockDB := &mocks.Repository{}
+	mockRedis := &mocks.RedisRepository{}
+
+	stale := &model.BerriesResponse{Berries: []model.Berry{
+		{
+			Name: "1",
+			URL:  "1",
+		},
+	}}
+	fresh := &model.BerriesResponse{Berries: []model.Berry{
+		{
+			Name: "1",
+			URL:  "1",
+		},
+		{
+			Name: "2",
+			URL:  "2",
+		},
+	}}
+
+	mockRedis.
+		On("GetData", mock.Anything, "en").
+		Return(stale, true, nil)
+	mockRedis.
+		On("AcquireLock", mock.Anything, "items:en", rebuildLockTTL).
+		Return("token", nil)
+	mockRedis.
+		On("ReleaseLock", mock.Anything, "items:en", "token").
+		Return(nil)
+	mockDB.
+		On("FetchBerries", mock.Anything, "en").
+		Return(fresh, nil)
+
+	refreshed := make(chan struct{})
+	mockRedis.
+		On("SetData", mock.Anything, "en", fresh).
+		Run(func(args mock.Arguments) {
+			close(refreshed)
+		}).
+		Return(nil)
+
+	s := &service{
+		dbRepository:    mockDB,
+		redisRepository: mockRedis,
+		client:          &mocks2.Client{},
+	}
+
+	got, status, err := s.GetItems(context.Background(), "en")
+	assert.NoError(t, err)
+	assert.Equal(t, CacheStale, status)
+	assert.Equal(t, stale, got)
+
+	select {
+	case <-refreshed:
+	case <-time.After(time.Second):
+		t.Fatal("stale listing was not refreshed in the background")
+	}
+}
+
+func Test_service_WarmCache(t *testing.T) {
+	berries := &model.BerriesResponse{Berries: []model.Berry{
+		{
+			Name: "cheri",
+			URL:  "1",
+		},
+		{
+			Name: "chesto",
+			URL:  "2",
+		},
+	}}
+
+	tests := []struct {
+		name     string
+		wantErr  bool
+		mockFunc func(mockDB *mocks.Repository, mockRedis *mocks.RedisRepository)
+	}{
+		{
+			name:    "given an error when FetchBerries should return an error",
+			wantErr: true,
+			mockFunc: func(mockDB *mocks.Repository, mockRedis *mocks.RedisRepository) {
+				mockDB.On("FetchBerries", mock.Anything, "en").Return(nil, errors.New("an error"))
+			},
+		},
+		{
+			name:    "given an error when SetData should return an error",
+			wantErr: true,
+			mockFunc: func(mockDB *mocks.Repository, mockRedis *mocks.RedisRepository) {
+				mockDB.On("FetchBerries", mock.Anything, "en").Return(berries, nil)
+				mockRedis.On("SetData", mock.Anything, "en", berries).Return(errors.New("an error"))
+			},
+		},
+		{
+			name:    "given an error when SetValue should return an error",
+			wantErr: true,
+			mockFunc: func(mockDB *mocks.Repository, mockRedis *mocks.RedisRepository) {
+				mockDB.On("FetchBerries", mock.Anything, "en").Return(berries, nil)
+				mockRedis.On("SetData", mock.Anything, "en", berries).Return(nil)
+				mockRedis.On("SetValue", mock.Anything, "berry:cheri", mock.Anything).Return(errors.New("an error"))
+			},
+		},
+		{
+			name:    "given happy flow should cache the listing, every berry and the reference data",
+			wantErr: false,
+			mockFunc: func(mockDB *mocks.Repository, mockRedis *mocks.RedisRepository) {
+				mockDB.On("FetchBerries", mock.Anything, "en").Return(berries, nil)
+				mockRedis.On("SetData", mock.Anything, "en", berries).Return(nil)
+				mockRedis.On("SetValue", mock.Anything, "berry:cheri", &model.Berry{Name: "cheri", URL: "1"}).Return(nil).Once()
+				mockRedis.On("SetValue", mock.Anything, "berry:chesto", &model.Berry{Name: "chesto", URL: "2"}).Return(nil).Once()
+				mockDB.On("FetchFirmnesses", mock.Anything).Return(&model.FirmnessesResponse{}, nil)
+				mockDB.On("FetchFlavors", mock.Anything).Return(&model.FlavorsResponse{}, nil)
+				mockRedis.On("SetValue", mock.Anything, firmnessesKey, &model.FirmnessesResponse{}).Return(nil).Once()
+				mockRedis.On("SetValue", mock.Anything, flavorsKey, &model.FlavorsResponse{}).Return(nil).Once()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := &mocks.Repository{}
+			mockRedis := &mocks.RedisRepository{}
+			tt.mockFunc(mockDB, mockRedis)
+			s := &service{
+				dbRepository:    mockDB,
+				redisRepository: mockRedis,
+			}
+
+			if err := s.WarmCache(context.Background()); (err != nil) != tt.wantErr {
+				t.Errorf("WarmCache() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			mockRedis.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_service_GetItem(t *testing.T) {
+	berry := &model.Berry{Name: "cheri", URL: "1"}
+
+	tests := []struct {
+		name       string
+		want       *model.Berry
+		wantStatus CacheStatus
+		wantErr    error
+		mockFunc   func(mockDB *mocks.Repository, mockRedis *mocks.RedisRepository)
+	}{
+		{
+			name:       "given result from redis should return berry",
+			want:       berry,
+			wantStatus: CacheHit,
+			mockFunc: func(mockDB *mocks.Repository, mockRedis *mocks.RedisRepository) {
+				mockRedis.
+					On("GetValue", mock.Anything, "berry:cheri", mock.Anything).
+					Run(func(args mock.Arguments) {
+						*args.Get(2).(*model.Berry) = *berry
+					}).
+					Return(true, nil)
+			},
+		},
+		{
+			name:       "given nil result from redis should read the database and cache the berry",
+			want:       berry,
+			wantStatus: CacheMiss,
+			mockFunc: func(mockDB *mocks.Repository, mockRedis *mocks.RedisRepository) {
+				mockRedis.On("GetValue", mock.Anything, "berry:cheri", mock.Anything).Return(false, nil)
+				mockDB.On("FetchBerry", mock.Anything, "cheri").Return(berry, nil)
+				mockRedis.On("SetValue", mock.Anything, "berry:cheri", berry).Return(nil)
+			},
+		},
+		{
+			name:       "given corrupt entry in redis should read the database and overwrite it",
+			want:       berry,
+			wantStatus: CacheMiss,
+			mockFunc: func(mockDB *mocks.Repository, mockRedis *mocks.RedisRepository) {
+				mockRedis.On("GetValue", mock.Anything, "berry:cheri", mock.Anything).Return(false, repository.ErrCorruptEntry)
+				mockDB.On("FetchBerry", mock.Anything, "cheri").Return(berry, nil)
+				mockRedis.On("SetValue", mock.Anything, "berry:cheri", berry).Return(nil)
+			},
+		},
+		{
+			name:       "given unknown berry should return ErrNotFound",
+			want:       nil,
+			wantStatus: CacheMiss,
+			wantErr:    model.ErrNotFound,
+			mockFunc: func(mockDB *mocks.Repository, mockRedis *mocks.RedisRepository) {
+				mockRedis.On("GetValue", mock.Anything, "berry:cheri", mock.Anything).Return(false, nil)
+				mockDB.On("FetchBerry", mock.Anything, "cheri").Return(nil, model.ErrNotFound)
+			},
+		},
+		{
+			name:       "given redis unavailable should read the database without caching",
+			want:       berry,
+			wantStatus: CacheBypass,
+			mockFunc: func(mockDB *mocks.Repository, mockRedis *mocks.RedisRepository) {
+				mockRedis.On("GetValue", mock.Anything, "berry:cheri", mock.Anything).Return(false, errors.New("an error"))
+				mockDB.On("FetchBerry", mock.Anything, "cheri").Return(berry, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := &mocks.Repository{}
+			mockRedis := &mocks.RedisRepository{}
+			tt.mockFunc(mockDB, mockRedis)
+			s := &service{
+				dbRepository:    mockDB,
+				redisRepository: mockRedis,
+			}
+
+			got, status, err := s.GetItem(context.Background(), "cheri")
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantStatus, status)
+			assert.Equal(t, tt.want, got)
+			mockRedis.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_service_DeleteCacheKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		wantErr  error
+		mockFunc func(mockRedis *mocks.RedisRepository)
+	}{
+		{
+			name:    "given existing key should return nil error",
+			wantErr: nil,
+			mockFunc: func(mockRedis *mocks.RedisRepository) {
+				mockRedis.On("DeleteKey", mock.Anything, "items").Return(true, nil)
+			},
+		},
+		{
+			name:    "given missing key should return ErrNotFound",
+			wantErr: model.ErrNotFound,
+			mockFunc: func(mockRedis *mocks.RedisRepository) {
+				mockRedis.On("DeleteKey", mock.Anything, "items").Return(false, nil)
+			},
+		},
+		{
+			name:    "given an error from redis should return the error",
+			wantErr: errors.New("an error"),
+			mockFunc: func(mockRedis *mocks.RedisRepository) {
+				mockRedis.On("DeleteKey", mock.Anything, "items").Return(false, errors.New("an error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRedis := &mocks.RedisRepository{}
+			tt.mockFunc(mockRedis)
+			s := &service{
+				redisRepository: mockRedis,
+			}
+
+			err := s.DeleteCacheKey(context.Background(), "items")
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr.Error())
+			}
+		})
+	}
+}
+
+type recordingStream struct {
+	run     *model.SyncRun
+	berries []model.Berry
+	err     error
+}
+
+func (s *recordingStream) Begin(run *model.SyncRun) error {
+	s.run = run
+	return s.err
+}
+
+func (s *recordingStream) Write(berry model.Berry) error {
+	s.berries = append(s.berries, berry)
+	return nil
+}
+
+func Test_service_StreamItems(t *testing.T) {
+	run := &model.SyncRun{ID: 2}
+
+	t.Run("given happy flow should begin with the sync run and write every berry", func(t *testing.T) {
+		mockDB := &mocks.Repository{}
+		mockDB.On("FetchLastSyncRun", mock.Anything).Return(run, nil)
+		mockDB.
+			On("StreamBerries", mock.Anything, "en", mock.Anything).
+			Run(func(args mock.Arguments) {
+				fn := args.Get(2).(func(model.Berry) error)
+				_ = fn(model.Berry{Name: "1", URL: "1"})
+				_ = fn(model.Berry{Name: "2", URL: "2"})
+			}).
+			Return(nil)
+		s := &service{dbRepository: mockDB}
+
+		stream := &recordingStream{}
+		err := s.StreamItems(context.Background(), "en", stream)
+		assert.NoError(t, err)
+		assert.Equal(t, run, stream.run)
+		assert.Equal(t, []model.Berry{{Name: "1", URL: "1"}, {Name: "2", URL: "2"}}, stream.berries)
+	})
+
+	t.Run("given an error when FetchLastSyncRun should return an error", func(t *testing.T) {
+		mockDB := &mocks.Repository{}
+		mockDB.On("FetchLastSyncRun", mock.Anything).Return(nil, errors.New("an error"))
+		s := &service{dbRepository: mockDB}
+
+		err := s.StreamItems(context.Background(), "en", &recordingStream{})
+		assert.Error(t, err)
+	})
+
+	t.Run("given an error when Begin should not read the berries", func(t *testing.T) {
+		mockDB := &mocks.Repository{}
+		mockDB.On("FetchLastSyncRun", mock.Anything).Return(run, nil)
+		s := &service{dbRepository: mockDB}
+
+		err := s.StreamItems(context.Background(), "en", &recordingStream{err: errors.New("not modified")})
+		assert.Error(t, err)
+		mockDB.AssertNotCalled(t, "StreamBerries", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func Test_service_GetFirmnesses(t *testing.T) {
+	firmnesses := &model.FirmnessesResponse{Firmnesses: []model.Firmness{
+		{
+			Name:    "soft",
+			Berries: []model.Berry{{Name: "cheri", URL: "1"}},
+		},
+	}}
+
+	t.Run("given nil result from redis should read the database and cache the firmnesses", func(t *testing.T) {
+		mockDB := &mocks.Repository{}
+		mockRedis := &mocks.RedisRepository{}
+		mockRedis.On("GetValue", mock.Anything, firmnessesKey, mock.Anything).Return(false, nil)
+		mockDB.On("FetchFirmnesses", mock.Anything).Return(firmnesses, nil)
+		mockRedis.On("SetValue", mock.Anything, firmnessesKey, firmnesses).Return(nil)
+		s := &service{dbRepository: mockDB, redisRepository: mockRedis}
+
+		got, status, err := s.GetFirmnesses(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, CacheMiss, status)
+		assert.Equal(t, firmnesses, got)
+		mockRedis.AssertExpectations(t)
+	})
+
+	t.Run("given result from redis should not read the database", func(t *testing.T) {
+		mockDB := &mocks.Repository{}
+		mockRedis := &mocks.RedisRepository{}
+		mockRedis.
+			On("GetValue", mock.Anything, firmnessesKey, mock.Anything).
+			Run(func(args mock.Arguments) {
+				*args.Get(2).(*model.FirmnessesResponse) = *firmnesses
+			}).
+			Return(true, nil)
+		s := &service{dbRepository: mockDB, redisRepository: mockRedis}
+
+		got, status, err := s.GetFirmnesses(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, CacheHit, status)
+		assert.Equal(t, firmnesses, got)
+		mockDB.AssertNotCalled(t, "FetchFirmnesses", mock.Anything)
+	})
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"github.com/inasknh/simple-poke-app/internal/api"
+	"github.com/inasknh/simple-poke-app/internal/model"
+	"log"
+	"slices"
+)
+
+const typeChartKey = "type-chart"
+
+func (s *service) SyncTypes(ctx context.Context) error {
+	err := syncEach(ctx, s.client, func(ctx context.Context, t *api.Type) error {
+		// types without damage relations, like unknown and shadow, would only
+		// add neutral rows to every matchup
+		if !hasDamageRelations(t.DamageRelations) {
+			return nil
+		}
+		return s.dbRepository.CreateType(ctx, constructType(t))
+	})
+	if err != nil {
+		return err
+	}
+
+	err = syncEach(ctx, s.client, func(ctx context.Context, berry *api.Berry) error {
+		return s.dbRepository.SetBerryNaturalGift(ctx, berry.Name, berry.NaturalGiftType.ID(), berry.NaturalGiftPower)
+	})
+	if err != nil {
+		return err
+	}
+
+	chart, err := s.dbRepository.FetchTypeChart(ctx)
+	if err != nil {
+		return err
+	}
+	if err = s.redisRepository.SetValue(ctx, typeChartKey, chart); err != nil {
+		log.Printf("failed to cache type chart: %v", err)
+	}
+
+	return nil
+}
+
+func hasDamageRelations(r api.TypeRelations) bool {
+	return len(r.NoDamageTo)+len(r.HalfDamageTo)+len(r.DoubleDamageTo)+
+		len(r.NoDamageFrom)+len(r.HalfDamageFrom)+len(r.DoubleDamageFrom) > 0
+}
+
+func constructType(t *api.Type) model.Type {
+	res := model.Type{
+		ID:   t.ID,
+		Name: t.Name,
+	}
+
+	relations := []struct {
+		types      []api.NamedAPIResource
+		multiplier float64
+	}{
+		{t.DamageRelations.NoDamageTo, 0},
+		{t.DamageRelations.HalfDamageTo, 0.5},
+		{t.DamageRelations.DoubleDamageTo, 2},
+	}
+	for _, relation := range relations {
+		for _, defending := range relation.types {
+			res.DamageTo = append(res.DamageTo, model.DamageRelation{
+				TypeID:     defending.ID(),
+				Type:       defending.Name,
+				Multiplier: relation.multiplier,
+			})
+		}
+	}
+
+	return res
+}
+
+func (s *service) GetEffectiveness(ctx context.Context, query model.EffectivenessQuery) (*model.EffectivenessResponse, CacheStatus, error) {
+	defending := query.Types
+	if query.Pokemon != "" {
+		pokemon, status, err := s.GetPokemon(ctx, query.Pokemon)
+		if err != nil {
+			return nil, status, err
+		}
+
+		defending = make([]string, 0, len(pokemon.Types))
+		for _, t := range pokemon.Types {
+			defending = append(defending, t.Name)
+		}
+	}
+
+	chart, status, err := cachedValue(ctx, s, typeChartKey, s.dbRepository.FetchTypeChart)
+	if err != nil {
+		return nil, status, err
+	}
+	for _, t := range defending {
+		if !slices.Contains(chart.Types, t) {
+			return nil, status, fmt.Errorf("type %q: %w", t, model.ErrNotFound)
+		}
+	}
+
+	return effectiveness(chart, defending), status, nil
+}
+
+// effectiveness multiplies the damage of every type against each defending type.
+func effectiveness(chart *model.TypeChart, defending []string) *model.EffectivenessResponse {
+	res := &model.EffectivenessResponse{
+		Defending:   defending,
+		Multipliers: make([]model.TypeMultiplier, 0, len(chart.Types)),
+		Berries:     []model.NaturalGiftMatchup{},
+	}
+
+	multipliers := make(map[string]float64, len(chart.Types))
+	for _, attacking := range chart.Types {
+		multiplier := 1.0
+		for _, t := range defending {
+			if m, ok := chart.Multipliers[attacking][t]; ok {
+				multiplier *= m
+			}
+		}
+		multipliers[attacking] = multiplier
+		res.Multipliers = append(res.Multipliers, model.TypeMultiplier{
+			Type:       attacking,
+			Multiplier: multiplier,
+		})
+	}
+
+	for _, gift := range chart.NaturalGifts {
+		if multipliers[gift.Type] > 1 {
+			res.Berries = append(res.Berries, model.NaturalGiftMatchup{
+				NaturalGift: gift,
+				Multiplier:  multipliers[gift.Type],
+			})
+		}
+	}
+	slices.SortStableFunc(res.Berries, func(a, b model.NaturalGiftMatchup) int {
+		return cmp.Or(cmp.Compare(b.Multiplier, a.Multiplier), cmp.Compare(b.Power, a.Power))
+	})
+
+	return res
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/inasknh/simple-poke-app/internal/fakepokeapi"
+	mocks "github.com/inasknh/simple-poke-app/internal/mocks/repository"
+	"github.com/inasknh/simple-poke-app/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func Test_service_SyncTypes_FakePokeAPI(t *testing.T) {
+	server := fakepokeapi.New()
+	defer server.Close()
+
+	mockDB := &mocks.Repository{}
+	var created []model.Type
+	mockDB.
+		On("CreateType", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			created = append(created, args.Get(1).(model.Type))
+		}).
+		Return(nil)
+	mockDB.On("SetBerryNaturalGift", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockDB.On("FetchTypeChart", mock.Anything).Return(&model.TypeChart{}, nil)
+	s := newFakeAPIService(server, mockDB)
+
+	err := s.SyncTypes(context.Background())
+	assert.NoError(t, err)
+	// unknown and shadow are fetched but not stored
+	assert.Len(t, created, 18)
+	for _, created := range created {
+		assert.NotContains(t, []string{"unknown", "shadow"}, created.Name)
+	}
+	// type list and details, then berry list and details
+	assert.Equal(t, 34, server.Requests())
+
+	normal := created[0]
+	assert.Equal(t, "normal", normal.Name)
+	assert.Equal(t, []model.DamageRelation{
+		{TypeID: 8, Type: "ghost", Multiplier: 0},
+		{TypeID: 6, Type: "rock", Multiplier: 0.5},
+		{TypeID: 9, Type: "steel", Multiplier: 0.5},
+	}, normal.DamageTo)
+
+	mockDB.AssertCalled(t, "SetBerryNaturalGift", mock.Anything, "cheri", 10, 60)
+	s.redisRepository.(*mocks.RedisRepository).AssertCalled(t, "SetValue", mock.Anything, typeChartKey, &model.TypeChart{})
+}
+
+func Test_service_SyncTypes_FakePokeAPI_Error(t *testing.T) {
+	server := fakepokeapi.New()
+	defer server.Close()
+
+	mockDB := &mocks.Repository{}
+	mockDB.On("CreateType", mock.Anything, mock.Anything).Return(errors.New("an error"))
+	s := newFakeAPIService(server, mockDB)
+
+	err := s.SyncTypes(context.Background())
+	assert.Error(t, err)
+	mockDB.AssertNumberOfCalls(t, "CreateType", 1)
+	mockDB.AssertNotCalled(t, "SetBerryNaturalGift", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_service_GetEffectiveness(t *testing.T) {
+	chart := &model.TypeChart{
+		Types: []string{"poison", "fire", "water", "grass", "psychic"},
+		Multipliers: map[string]map[string]float64{
+			"poison":  {"poison": 0.5, "grass": 2},
+			"fire":    {"fire": 0.5, "water": 0.5, "grass": 2},
+			"water":   {"water": 0.5, "grass": 0.5, "fire": 2},
+			"grass":   {"poison": 0.5, "fire": 0.5, "grass": 0.5, "water": 2},
+			"psychic": {"poison": 2, "psychic": 0.5},
+		},
+		NaturalGifts: []model.NaturalGift{
+			{Berry: "cheri", Type: "fire", Power: 60},
+			{Berry: "chesto", Type: "water", Power: 60},
+			{Berry: "sitrus", Type: "psychic", Power: 60},
+			{Berry: "figy", Type: "fire", Power: 80},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		query    model.EffectivenessQuery
+		want     *model.EffectivenessResponse
+		wantErr  error
+		mockFunc func(mockDB *mocks.Repository, mockRedis *mocks.RedisRepository)
+	}{
+		{
+			name:  "given a type combination should multiply the damage against each type",
+			query: model.EffectivenessQuery{Types: []string{"grass", "poison"}},
+			want: &model.EffectivenessResponse{
+				Defending: []string{"grass", "poison"},
+				Multipliers: []model.TypeMultiplier{
+					{Type: "poison", Multiplier: 1},
+					{Type: "fire", Multiplier: 2},
+					{Type: "water", Multiplier: 0.5},
+					{Type: "grass", Multiplier: 0.25},
+					{Type: "psychic", Multiplier: 2},
+				},
+				Berries: []model.NaturalGiftMatchup{
+					{NaturalGift: model.NaturalGift{Berry: "figy", Type: "fire", Power: 80}, Multiplier: 2},
+					{NaturalGift: model.NaturalGift{Berry: "cheri", Type: "fire", Power: 60}, Multiplier: 2},
+					{NaturalGift: model.NaturalGift{Berry: "sitrus", Type: "psychic", Power: 60}, Multiplier: 2},
+				},
+			},
+		},
+		{
+			name:  "given a pokemon should use its types",
+			query: model.EffectivenessQuery{Pokemon: "squirtle"},
+			want: &model.EffectivenessResponse{
+				Defending: []string{"water"},
+				Multipliers: []model.TypeMultiplier{
+					{Type: "poison", Multiplier: 1},
+					{Type: "fire", Multiplier: 0.5},
+					{Type: "water", Multiplier: 0.5},
+					{Type: "grass", Multiplier: 2},
+					{Type: "psychic", Multiplier: 1},
+				},
+				Berries: []model.NaturalGiftMatchup{},
+			},
+			mockFunc: func(mockDB *mocks.Repository, mockRedis *mocks.RedisRepository) {
+				mockRedis.
+					On("GetValue", mock.Anything, "pokemon:squirtle", mock.Anything).
+					Run(func(args mock.Arguments) {
+						*args.Get(2).(*model.Pokemon) = model.Pokemon{Name: "squirtle", Types: []model.PokemonType{{Name: "water"}}}
+					}).
+					Return(true, nil)
+			},
+		},
+		{
+			name:    "given an unknown type should return ErrNotFound",
+			query:   model.EffectivenessQuery{Types: []string{"sound"}},
+			want:    nil,
+			wantErr: model.ErrNotFound,
+		},
+		{
+			name:    "given an unknown pokemon should return ErrNotFound",
+			query:   model.EffectivenessQuery{Pokemon: "missingno"},
+			want:    nil,
+			wantErr: model.ErrNotFound,
+			mockFunc: func(mockDB *mocks.Repository, mockRedis *mocks.RedisRepository) {
+				mockRedis.On("GetValue", mock.Anything, "pokemon:missingno", mock.Anything).Return(false, nil)
+				mockDB.On("FetchPokemon", mock.Anything, "missingno").Return(nil, model.ErrNotFound)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB := &mocks.Repository{}
+			mockRedis := &mocks.RedisRepository{}
+			mockRedis.
+				On("GetValue", mock.Anything, typeChartKey, mock.Anything).
+				Run(func(args mock.Arguments) {
+					*args.Get(2).(*model.TypeChart) = *chart
+				}).
+				Return(true, nil)
+			if tt.mockFunc != nil {
+				tt.mockFunc(mockDB, mockRedis)
+			}
+			s := &service{
+				dbRepository:    mockDB,
+				redisRepository: mockRedis,
+			}
+
+			got, _, err := s.GetEffectiveness(context.Background(), tt.query)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}